@@ -0,0 +1,633 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModuleNode is a module found in the source and the modules it imports
+type ModuleNode struct {
+	Name    string
+	File    string
+	Line    int
+	Imports []string
+}
+
+// ModuleGraph is the module dependency graph of a project, built statically
+type ModuleGraph struct {
+	Modules map[string]*ModuleNode
+}
+
+// importEdge is one import of a module
+type importEdge struct {
+	from, to string
+}
+
+// moduleConstructors build a module from a literal name argument
+var moduleConstructors = map[string]bool{
+	"NewModule":     true,
+	"DefaultModule": true,
+}
+
+// scanModules parses every Go file under rootDir and builds the module graph from
+// NewModule/DefaultModule calls and core.Module literals, and their WithImports
+// chains, Imports assignments or Imports fields
+func scanModules(rootDir string) (*ModuleGraph, error) {
+	graph := &ModuleGraph{Modules: make(map[string]*ModuleNode)}
+
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip test files and vendor
+		if !strings.HasSuffix(path, ".go") ||
+			strings.HasSuffix(path, "_test.go") ||
+			strings.Contains(path, "vendor/") ||
+			strings.Contains(path, ".git/") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, parser.AllErrors)
+		if err != nil {
+			// Log warning but continue
+			fmt.Fprintf(os.Stderr, "Warning: could not parse %s: %v\n", path, err)
+			return nil
+		}
+
+		graph.scanFile(path, fset, node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, module := range graph.Modules {
+		sort.Strings(module.Imports)
+	}
+	return graph, nil
+}
+
+// scanFile adds the modules defined and imported in one file
+func (g *ModuleGraph) scanFile(path string, fset *token.FileSet, node *ast.File) {
+	// Modules built only to be imported are references, not definitions
+	var importRanges [][2]token.Pos
+	ast.Inspect(node, func(n ast.Node) bool {
+		for _, arg := range importArgs(n) {
+			importRanges = append(importRanges, [2]token.Pos{arg.Pos(), arg.End()})
+		}
+		return true
+	})
+	isImportArg := func(pos token.Pos) bool {
+		for _, r := range importRanges {
+			if r[0] <= pos && pos < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Variables holding modules, e.g. module := core.NewModule("orders", "1.0.0")
+	vars := make(map[string]string)
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, l := range lhs {
+			if ident, ok := l.(*ast.Ident); ok {
+				if name := moduleName(rhs[i], vars); name != "" {
+					vars[ident.Name] = name
+				} else {
+					delete(vars, ident.Name)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			assign(n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			assign(lhs, n.Values)
+		case *ast.CallExpr:
+			if name, ok := constructorName(n); ok && !isImportArg(n.Pos()) {
+				g.define(name, path, fset.Position(n.Pos()).Line)
+			}
+		case *ast.CompositeLit:
+			if name, ok := literalName(n); ok && !isImportArg(n.Pos()) {
+				g.define(name, path, fset.Position(n.Pos()).Line)
+			}
+		}
+
+		// Imports are attributed to the module they are added to
+		if owner := importOwner(n, vars); owner != "" {
+			for _, arg := range importArgs(n) {
+				target := moduleName(arg, vars)
+				if target == "" {
+					// Built elsewhere, e.g. users.Module(); shown as an unresolved import
+					target = types.ExprString(arg)
+				}
+				g.addImport(owner, target)
+			}
+		}
+		return true
+	})
+}
+
+// define records a module definition, keeping the first location seen
+func (g *ModuleGraph) define(name, file string, line int) {
+	module := g.node(name)
+	if module.File == "" {
+		module.File, module.Line = file, line
+	}
+}
+
+// addImport records that module imports target
+func (g *ModuleGraph) addImport(module, target string) {
+	node := g.node(module)
+	for _, existing := range node.Imports {
+		if existing == target {
+			return
+		}
+	}
+	node.Imports = append(node.Imports, target)
+	g.node(target)
+}
+
+// node returns the module, creating it on first use
+func (g *ModuleGraph) node(name string) *ModuleNode {
+	module, exists := g.Modules[name]
+	if !exists {
+		module = &ModuleNode{Name: name}
+		g.Modules[name] = module
+	}
+	return module
+}
+
+// defined reports whether a module with the name was found in the source
+func (g *ModuleGraph) defined(name string) bool {
+	module, exists := g.Modules[name]
+	return exists && module.File != ""
+}
+
+// Names returns the defined modules in sorted order
+func (g *ModuleGraph) Names() []string {
+	var names []string
+	for name := range g.Modules {
+		if g.defined(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// edges returns every import in sorted order
+func (g *ModuleGraph) edges() []importEdge {
+	var edges []importEdge
+	for _, name := range g.sortedAll() {
+		for _, target := range g.Modules[name].Imports {
+			edges = append(edges, importEdge{from: name, to: target})
+		}
+	}
+	return edges
+}
+
+// sortedAll returns every module name, defined or not, in sorted order
+func (g *ModuleGraph) sortedAll() []string {
+	names := make([]string, 0, len(g.Modules))
+	for name := range g.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Roots returns the defined modules that no other module imports
+func (g *ModuleGraph) Roots() []string {
+	imported := make(map[string]bool)
+	for _, edge := range g.edges() {
+		if edge.from != edge.to {
+			imported[edge.to] = true
+		}
+	}
+
+	var roots []string
+	for _, name := range g.Names() {
+		if !imported[name] {
+			roots = append(roots, name)
+		}
+	}
+	return roots
+}
+
+// Unresolved returns the imports of modules that are not defined anywhere in the source
+func (g *ModuleGraph) Unresolved() []importEdge {
+	var unresolved []importEdge
+	for _, edge := range g.edges() {
+		if !g.defined(edge.to) {
+			unresolved = append(unresolved, edge)
+		}
+	}
+	return unresolved
+}
+
+// Cycles returns each group of modules that import each other, directly or
+// transitively, using Tarjan's strongly connected components algorithm
+func (g *ModuleGraph) Cycles() [][]string {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		selfImport := false
+		for _, target := range g.Modules[name].Imports {
+			if target == name {
+				selfImport = true
+			}
+			if _, seen := index[target]; !seen {
+				visit(target)
+				lowlink[name] = min(lowlink[name], lowlink[target])
+			} else if onStack[target] {
+				lowlink[name] = min(lowlink[name], index[target])
+			}
+		}
+
+		if lowlink[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		if len(component) > 1 || selfImport {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, name := range g.sortedAll() {
+		if _, seen := index[name]; !seen {
+			visit(name)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// cyclicEdges returns the imports that are part of a cycle
+func (g *ModuleGraph) cyclicEdges() map[importEdge]bool {
+	component := make(map[string]int)
+	for i, cycle := range g.Cycles() {
+		for _, name := range cycle {
+			component[name] = i + 1
+		}
+	}
+
+	cyclic := make(map[importEdge]bool)
+	for _, edge := range g.edges() {
+		if component[edge.from] != 0 && component[edge.from] == component[edge.to] {
+			cyclic[edge] = true
+		}
+	}
+	return cyclic
+}
+
+// summary returns the roots, unresolved imports and cycles as comment lines
+func (g *ModuleGraph) summary() []string {
+	lines := []string{"Roots: " + joinOrNone(g.Roots())}
+
+	var unresolved []string
+	for _, edge := range g.Unresolved() {
+		unresolved = append(unresolved, edge.from+" -> "+edge.to)
+	}
+	lines = append(lines, "Unresolved imports: "+joinOrNone(unresolved))
+
+	var cycles []string
+	for _, cycle := range g.Cycles() {
+		cycles = append(cycles, "["+strings.Join(cycle, ", ")+"]")
+	}
+	return append(lines, "Cycles: "+joinOrNone(cycles))
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// WriteDOT writes the graph in Graphviz DOT format; unresolved modules are dashed
+// and imports that form cycles are red
+func (g *ModuleGraph) WriteDOT(w io.Writer) {
+	fmt.Fprintln(w, "// Module dependency graph generated by doffy-graph")
+	for _, line := range g.summary() {
+		fmt.Fprintln(w, "// "+line)
+	}
+	fmt.Fprintln(w, "digraph modules {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [shape=box];")
+
+	for _, name := range g.sortedAll() {
+		if g.defined(name) {
+			fmt.Fprintf(w, "\t%s;\n", strconv.Quote(name))
+		} else {
+			fmt.Fprintf(w, "\t%s [style=dashed, color=gray];\n", strconv.Quote(name))
+		}
+	}
+
+	cyclic := g.cyclicEdges()
+	for _, edge := range g.edges() {
+		attributes := ""
+		if cyclic[edge] {
+			attributes = " [color=red]"
+		}
+		fmt.Fprintf(w, "\t%s -> %s%s;\n", strconv.Quote(edge.from), strconv.Quote(edge.to), attributes)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart with the same highlighting as WriteDOT
+func (g *ModuleGraph) WriteMermaid(w io.Writer) {
+	fmt.Fprintln(w, "%% Module dependency graph generated by doffy-graph")
+	for _, line := range g.summary() {
+		fmt.Fprintln(w, "%% "+line)
+	}
+	fmt.Fprintln(w, "graph LR")
+
+	// Module names may contain characters Mermaid ids do not allow
+	ids := make(map[string]string)
+	var unresolved []string
+	for i, name := range g.sortedAll() {
+		ids[name] = fmt.Sprintf("m%d", i)
+		fmt.Fprintf(w, "\t%s[%s]\n", ids[name], strconv.Quote(name))
+		if !g.defined(name) {
+			unresolved = append(unresolved, ids[name])
+		}
+	}
+
+	cyclic := g.cyclicEdges()
+	var cyclicLinks []string
+	for i, edge := range g.edges() {
+		fmt.Fprintf(w, "\t%s --> %s\n", ids[edge.from], ids[edge.to])
+		if cyclic[edge] {
+			cyclicLinks = append(cyclicLinks, strconv.Itoa(i))
+		}
+	}
+
+	if len(unresolved) > 0 {
+		fmt.Fprintln(w, "\tclassDef unresolved stroke-dasharray: 5 5,color:gray")
+		fmt.Fprintf(w, "\tclass %s unresolved\n", strings.Join(unresolved, ","))
+	}
+	if len(cyclicLinks) > 0 {
+		fmt.Fprintf(w, "\tlinkStyle %s stroke:red\n", strings.Join(cyclicLinks, ","))
+	}
+}
+
+// importArgs returns the module expressions imported by a WithImports call,
+// a X.Imports = []*core.Module{...} assignment, an append to X.Imports or the
+// Imports field of a core.Module literal
+func importArgs(n ast.Node) []ast.Expr {
+	switch n := n.(type) {
+	case *ast.CompositeLit:
+		if imports, ok := literalField(n, "Imports").(*ast.CompositeLit); ok {
+			return imports.Elts
+		}
+	case *ast.CallExpr:
+		if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "WithImports" {
+			return n.Args
+		}
+	case *ast.AssignStmt:
+		if len(n.Lhs) != 1 || len(n.Rhs) != 1 || importsField(n.Lhs[0]) == nil {
+			return nil
+		}
+		switch rhs := n.Rhs[0].(type) {
+		case *ast.CompositeLit:
+			return rhs.Elts
+		case *ast.CallExpr:
+			if ident, ok := rhs.Fun.(*ast.Ident); ok && ident.Name == "append" && len(rhs.Args) > 1 {
+				return rhs.Args[1:]
+			}
+		}
+	}
+	return nil
+}
+
+// importOwner returns the module whose imports the node adds to
+func importOwner(n ast.Node, vars map[string]string) string {
+	switch n := n.(type) {
+	case *ast.CompositeLit:
+		name, _ := literalName(n)
+		return name
+	case *ast.CallExpr:
+		if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "WithImports" {
+			return moduleName(sel.X, vars)
+		}
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 1 {
+			if sel := importsField(n.Lhs[0]); sel != nil {
+				return moduleName(sel.X, vars)
+			}
+		}
+	}
+	return ""
+}
+
+// importsField returns the expression when it is a X.Imports selector
+func importsField(expr ast.Expr) *ast.SelectorExpr {
+	if sel, ok := expr.(*ast.SelectorExpr); ok && sel.Sel.Name == "Imports" {
+		return sel
+	}
+	return nil
+}
+
+// moduleName returns the name of the module an expression evaluates to, following
+// With* builder chains and variables; "" when it cannot be determined statically
+func moduleName(expr ast.Expr, vars map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return moduleName(e.X, vars)
+	case *ast.UnaryExpr:
+		return moduleName(e.X, vars)
+	case *ast.CompositeLit:
+		name, _ := literalName(e)
+		return name
+	case *ast.Ident:
+		return vars[e.Name]
+	case *ast.CallExpr:
+		if name, ok := constructorName(e); ok {
+			return name
+		}
+		// Builder methods return the module they were called on
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && strings.HasPrefix(sel.Sel.Name, "With") {
+			return moduleName(sel.X, vars)
+		}
+	}
+	return ""
+}
+
+// constructorName returns the module name of a NewModule/DefaultModule call with a literal name
+func constructorName(call *ast.CallExpr) (string, bool) {
+	var name string
+	switch f := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		name = f.Sel.Name
+	case *ast.Ident:
+		name = f.Name
+	}
+	if !moduleConstructors[name] || len(call.Args) == 0 {
+		return "", false
+	}
+
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// literalName returns the name of a core.Module literal with a literal Name field
+func literalName(lit *ast.CompositeLit) (string, bool) {
+	value, ok := literalField(lit, "Name").(*ast.BasicLit)
+	if !ok || value.Kind != token.STRING {
+		return "", false
+	}
+	name, err := strconv.Unquote(value.Value)
+	return name, err == nil
+}
+
+// literalField returns a field of a core.Module literal, or nil
+func literalField(lit *ast.CompositeLit, field string) ast.Expr {
+	typeName := ""
+	switch t := lit.Type.(type) {
+	case *ast.SelectorExpr:
+		typeName = t.Sel.Name
+	case *ast.Ident:
+		typeName = t.Name
+	}
+	if typeName != "Module" {
+		return nil
+	}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// printUsage prints the usage information
+func printUsage() {
+	fmt.Printf(`Usage: %s [options] <project-root>
+
+Builds the module dependency graph from NewModule/DefaultModule calls and
+core.Module literals, and their imports, without running the application.
+
+Options:
+  -format string   Output format: "dot" (default) or "mermaid"
+  -output string   Write the graph to a file instead of stdout
+  -help, -h        Show this help message
+
+Examples:
+  %s ./my-project | dot -Tsvg > modules.svg
+  %s -format=mermaid -output=modules.mmd ./my-project
+
+`, os.Args[0], os.Args[0], os.Args[0])
+}
+
+func main() {
+	var format, output string
+	var help bool
+
+	flag.StringVar(&format, "format", "dot", "Output format: dot or mermaid")
+	flag.StringVar(&output, "output", "", "Output file (default: stdout)")
+	flag.BoolVar(&help, "help", false, "Show help")
+	flag.BoolVar(&help, "h", false, "Show help")
+	flag.Parse()
+
+	if help {
+		printUsage()
+		os.Exit(0)
+	}
+
+	if flag.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Error: Missing project root directory\n\n")
+		printUsage()
+		os.Exit(1)
+	}
+
+	if format != "dot" && format != "mermaid" {
+		fmt.Fprintf(os.Stderr, "Error: Invalid format '%s'. Must be 'dot' or 'mermaid'\n\n", format)
+		printUsage()
+		os.Exit(1)
+	}
+
+	rootDir := flag.Arg(0)
+	if _, err := os.Stat(rootDir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: Directory '%s' does not exist\n", rootDir)
+		os.Exit(1)
+	}
+
+	graph, err := scanModules(rootDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to scan directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if format == "mermaid" {
+		graph.WriteMermaid(w)
+	} else {
+		graph.WriteDOT(w)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ordersModuleSource = `package orders
+
+func (p *OrdersPlugin) Module() *core.Module {
+	return core.NewModule("orders", "1.0.0").
+		WithPrefix("/orders").
+		WithImports(core.DefaultModule("users", "1.0.0"), payments.Module())
+}
+
+func (p *OrdersPlugin) Register(container core.DIContainer) error {
+	module := p.Module()
+	_ = module
+	return nil
+}
+`
+
+const usersModuleSource = `package users
+
+var auditModule = core.NewModule("audit", "1.0.0")
+
+func (p *UsersPlugin) Module() *core.Module {
+	module := core.DefaultModule("users", "1.0.0")
+	module.Imports = []*core.Module{core.NewModule("audit", "1.0.0")}
+	return module
+}
+
+var billing = &core.Module{
+	Name:    "billing",
+	Version: "1.0.0",
+	Imports: []*core.Module{core.DefaultModule("users", "1.0.0")},
+}
+
+func init() {
+	auditModule.Imports = append(auditModule.Imports, core.NewModule("users", "1.0.0"))
+}
+`
+
+func writeGraphSources(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "orders"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "users"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "orders", "module.go"), []byte(ordersModuleSource), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "users", "module.go"), []byte(usersModuleSource), 0644))
+	return root
+}
+
+func TestScanModules_BuildsGraph(t *testing.T) {
+	graph, err := scanModules(writeGraphSources(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"audit", "billing", "orders", "users"}, graph.Names())
+	assert.Equal(t, []string{"payments.Module()", "users"}, graph.Modules["orders"].Imports)
+	assert.Equal(t, []string{"audit"}, graph.Modules["users"].Imports)
+	assert.Equal(t, []string{"users"}, graph.Modules["audit"].Imports)
+	assert.Equal(t, []string{"users"}, graph.Modules["billing"].Imports)
+
+	assert.Equal(t, []string{"billing", "orders"}, graph.Roots())
+	assert.Equal(t, []importEdge{{from: "orders", to: "payments.Module()"}}, graph.Unresolved())
+	assert.Equal(t, [][]string{{"audit", "users"}}, graph.Cycles())
+}
+
+func TestModuleGraph_WriteDOT(t *testing.T) {
+	graph, err := scanModules(writeGraphSources(t))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	graph.WriteDOT(&out)
+
+	assert.Contains(t, out.String(), "// Roots: billing, orders\n")
+	assert.Contains(t, out.String(), "// Unresolved imports: orders -> payments.Module()\n")
+	assert.Contains(t, out.String(), "// Cycles: [audit, users]\n")
+	assert.Contains(t, out.String(), "\t\"payments.Module()\" [style=dashed, color=gray];\n")
+	assert.Contains(t, out.String(), "\t\"orders\" -> \"users\";\n")
+	assert.Contains(t, out.String(), "\t\"users\" -> \"audit\" [color=red];\n")
+}
+
+func TestModuleGraph_WriteMermaid(t *testing.T) {
+	graph, err := scanModules(writeGraphSources(t))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	graph.WriteMermaid(&out)
+
+	// Nodes are numbered in sorted order: audit, billing, orders, payments.Module(), users
+	assert.Contains(t, out.String(), "graph LR\n")
+	assert.Contains(t, out.String(), "\tm3[\"payments.Module()\"]\n")
+	assert.Contains(t, out.String(), "\tclass m3 unresolved\n")
+	// Edges: audit->users, billing->users, orders->payments, orders->users, users->audit
+	assert.Contains(t, out.String(), "\tlinkStyle 0,4 stroke:red\n")
+}
@@ -7,11 +7,13 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +24,7 @@ type RouteInfo struct {
 	Method    string   `json:"method"`
 	Path      string   `json:"path"`
 	IsHard    bool     `json:"is_hard"` // true if path starts with /
+	Partial   bool     `json:"partial,omitempty"` // true if some segments are dynamic and shown as {expr}
 	Suggested string   `json:"suggested,omitempty"`
 	Context   []string `json:"context,omitempty"` // surrounding lines for context
 }
@@ -95,6 +98,8 @@ func main() {
 				if route.Suggested != "" {
 					fmt.Printf("   → Suggested: %s %s\n", route.Method, route.Suggested)
 				}
+			} else if route.Partial {
+				fmt.Printf("⚠️  %s:%d - %s %s (partially unknown)\n", route.File, route.Line, route.Method, route.Path)
 			} else {
 				fmt.Printf("✅ %s:%d - %s %s\n", route.File, route.Line, route.Method, route.Path)
 			}
@@ -201,6 +206,9 @@ func analyzeFile(filePath string) ([]RouteInfo, error) {
 	}
 	lines := strings.Split(string(content), "\n")
 
+	// Resolve package-level string consts so GET(UsersPath) is reported
+	consts := collectStringConsts(node)
+
 	// Look for route method calls
 	ast.Inspect(node, func(n ast.Node) bool {
 		callExpr, ok := n.(*ast.CallExpr)
@@ -224,7 +232,7 @@ func analyzeFile(filePath string) ([]RouteInfo, error) {
 			return true
 		}
 
-		path, isHard, err := extractPathFromArg(callExpr.Args[0], lines, fset.Position(callExpr.Pos()).Line)
+		path, isHard, partial, err := extractPathFromArg(callExpr.Args[0], consts)
 		if err != nil {
 			log.Printf("Error extracting path in %s: %v", filePath, err)
 			return true
@@ -241,6 +249,7 @@ func analyzeFile(filePath string) ([]RouteInfo, error) {
 			Method:  method,
 			Path:    path,
 			IsHard:  isHard,
+			Partial: partial,
 			Context: context,
 		}
 
@@ -266,20 +275,108 @@ func isHTTPMethod(method string) bool {
 }
 
 // extractPathFromArg extracts the path string from a function argument
-func extractPathFromArg(arg ast.Expr, lines []string, lineNum int) (string, bool, error) {
-	basicLit, ok := arg.(*ast.BasicLit)
-	if !ok {
-		return "", false, fmt.Errorf("argument is not a string literal")
+// String literals, same-file string consts and "+" concatenations of them are resolved;
+// any other segment is kept as a {expr} placeholder and the path is reported as partial
+func extractPathFromArg(arg ast.Expr, consts map[string]string) (string, bool, bool, error) {
+	if basicLit, ok := arg.(*ast.BasicLit); ok && basicLit.Kind != token.STRING {
+		return "", false, false, fmt.Errorf("argument is not a string")
 	}
 
-	if basicLit.Kind != token.STRING {
-		return "", false, fmt.Errorf("argument is not a string")
-	}
+	path, partial := resolvePathExpr(arg, consts)
 
-	path := strings.Trim(basicLit.Value, `"`)
+	// A leading dynamic segment (e.g. prefix + "/users") may or may not start with /
 	isHard := strings.HasPrefix(path, "/")
 
-	return path, isHard, nil
+	return path, isHard, partial, nil
+}
+
+// resolvePathExpr renders a path expression, returning true if any segment is dynamic
+func resolvePathExpr(expr ast.Expr, consts map[string]string) (string, bool) {
+	if value, ok := resolveStringExpr(expr, consts); ok {
+		return value, false
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return resolvePathExpr(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			left, leftPartial := resolvePathExpr(e.X, consts)
+			right, rightPartial := resolvePathExpr(e.Y, consts)
+			return left + right, leftPartial || rightPartial
+		}
+	}
+
+	return "{" + types.ExprString(expr) + "}", true
+}
+
+// resolveStringExpr evaluates a string literal, a known const or a concatenation of them
+func resolveStringExpr(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return "", false
+		}
+		return value, true
+	case *ast.Ident:
+		value, ok := consts[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return resolveStringExpr(e.X, consts)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := resolveStringExpr(e.X, consts)
+		if !ok {
+			return "", false
+		}
+		right, ok := resolveStringExpr(e.Y, consts)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	}
+	return "", false
+}
+
+// collectStringConsts returns the package-level string consts declared in the file
+// Consts that reference other consts are resolved regardless of declaration order
+func collectStringConsts(file *ast.File) map[string]string {
+	pending := make(map[string]ast.Expr)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || len(valueSpec.Names) != len(valueSpec.Values) {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				pending[name.Name] = valueSpec.Values[i]
+			}
+		}
+	}
+
+	consts := make(map[string]string)
+	for resolved := true; resolved && len(pending) > 0; {
+		resolved = false
+		for name, expr := range pending {
+			if value, ok := resolveStringExpr(expr, consts); ok {
+				consts[name] = value
+				delete(pending, name)
+				resolved = true
+			}
+		}
+	}
+
+	return consts
 }
 
 // getContextLines returns lines around the target line for context
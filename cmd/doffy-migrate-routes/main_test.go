@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeFile_ResolvesPathExpressions(t *testing.T) {
+	source := `package routes
+
+const (
+	APIPrefix = "/api"
+	UsersPath = APIPrefix + "/users"
+	raw       = ` + "`/raw`" + `
+)
+
+func register(r Router, prefix string) {
+	r.GET("/health", nil)
+	r.GET(UsersPath, nil)
+	r.POST(UsersPath + "/" + ":id", nil)
+	r.GET(raw, nil)
+	r.GET(prefix + "/orders", nil)
+	r.DELETE(APIPrefix + cfg.Path, nil)
+}
+`
+	file := filepath.Join(t.TempDir(), "routes.go")
+	require.NoError(t, os.WriteFile(file, []byte(source), 0644))
+
+	routes, err := analyzeFile(file)
+	require.NoError(t, err)
+
+	type result struct {
+		Method  string
+		Path    string
+		IsHard  bool
+		Partial bool
+	}
+	var got []result
+	for _, route := range routes {
+		got = append(got, result{route.Method, route.Path, route.IsHard, route.Partial})
+	}
+
+	assert.Equal(t, []result{
+		{"GET", "/health", true, false},
+		{"GET", "/api/users", true, false},
+		{"POST", "/api/users/:id", true, false},
+		{"GET", "/raw", true, false},
+		{"GET", "{prefix}/orders", false, true},
+		{"DELETE", "/api{cfg.Path}", true, true},
+	}, got)
+}
@@ -6,17 +6,110 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// resolveMethods maps each container resolution method to its argument count
+// and the index of the service name argument
+var resolveMethods = map[string]struct {
+	args        int
+	nameArg     int
+	withContext bool
+}{
+	"Resolve":                 {args: 1, nameArg: 0},
+	"ResolveWithContext":      {args: 2, nameArg: 0, withContext: true},
+	"ResolveAs":               {args: 2, nameArg: 0},
+	"ResolveAsWithContext":    {args: 3, nameArg: 0, withContext: true},
+	"ResolveTyped":            {args: 2, nameArg: 1},
+	"ResolveTypedWithContext": {args: 3, nameArg: 1, withContext: true},
+}
+
+// unknownModule is reported when the calling file has no Module() definition nearby
+const unknownModule = "<unknown>"
+
 // ValidateEncapsulation scans codebase for encapsulation violations
 func ValidateEncapsulation(rootDir string, mode string) error {
-	violations := []Violation{}
-
 	fmt.Printf("Scanning Go files in %s for encapsulation violations...\n\n", rootDir)
 
+	violations, err := scanViolations(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	// Report violations
+	if len(violations) > 0 {
+		fmt.Printf("Found %d potential encapsulation violations:\n\n", len(violations))
+
+		// Group by source module so the report reads "who reaches into whom"
+		byModule := make(map[string][]Violation)
+		var modules []string
+		for _, v := range violations {
+			if _, seen := byModule[v.Module]; !seen {
+				modules = append(modules, v.Module)
+			}
+			byModule[v.Module] = append(byModule[v.Module], v)
+		}
+		sort.Strings(modules)
+
+		for _, module := range modules {
+			fmt.Printf("Module %s:\n", module)
+			for _, v := range byModule[module] {
+				relPath, _ := filepath.Rel(rootDir, v.File)
+				target := ""
+				if v.TargetModule != "" && v.TargetModule != v.Module {
+					target = fmt.Sprintf(" (provided by %s)", v.TargetModule)
+				}
+				fmt.Printf("  %s:%d: %s -> %s.%s('%s')%s\n", relPath, v.Line, v.Function, v.Receiver, v.Method, v.Service, target)
+			}
+			fmt.Println()
+		}
+
+		if mode == "strict" {
+			return fmt.Errorf("encapsulation violations detected in strict mode")
+		}
+	} else {
+		fmt.Println("✓ No potential encapsulation violations found")
+	}
+
+	return nil
+}
+
+// Violation represents a potential encapsulation violation
+type Violation struct {
+	File         string
+	Line         int
+	Function     string
+	Service      string
+	Method       string // e.g. Resolve, ResolveAsWithContext, ResolveTyped
+	Receiver     string // the container expression, or the package for ResolveTyped
+	WithContext  bool
+	Module       string // module the calling file belongs to
+	TargetModule string // module declaring a provider for Service, if found
+}
+
+// parsedFile is a Go file parsed for analysis
+type parsedFile struct {
+	path string
+	fset *token.FileSet
+	node *ast.File
+}
+
+// moduleDefinition is a Module() method and the module it builds
+type moduleDefinition struct {
+	name      string
+	receiver  string // type the Module() method is declared on
+	line      int
+	providers []string
+}
+
+// scanViolations parses every Go file under rootDir and returns each container resolution call
+func scanViolations(rootDir string) ([]Violation, error) {
+	var files []parsedFile
+
 	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -39,90 +132,207 @@ func ValidateEncapsulation(rootDir string, mode string) error {
 			return nil
 		}
 
-		// Scan for container.Resolve() calls
-		ast.Inspect(node, func(n ast.Node) bool {
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
+		files = append(files, parsedFile{path: path, fset: fset, node: node})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect module definitions per file and per package directory
+	modulesByFile := make(map[string][]moduleDefinition)
+	modulesByDir := make(map[string][]moduleDefinition)
+	providerModule := make(map[string]string)
+	for _, f := range files {
+		definitions := findModuleDefinitions(f)
+		modulesByFile[f.path] = definitions
+		dir := filepath.Dir(f.path)
+		modulesByDir[dir] = append(modulesByDir[dir], definitions...)
+		for _, definition := range definitions {
+			for _, provider := range definition.providers {
+				providerModule[provider] = definition.name
 			}
+		}
+	}
 
-			// Check if this is a Resolve method call
-			sel, ok := call.Fun.(*ast.SelectorExpr)
-			if !ok || sel.Sel.Name != "Resolve" {
+	var violations []Violation
+	for _, f := range files {
+		ast.Inspect(f.node, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
 				return true
 			}
 
-			// Extract service name from first argument
-			if len(call.Args) == 0 {
+			method, receiver, ok := resolveCall(call)
+			if !ok {
 				return true
 			}
+			spec := resolveMethods[method]
 
 			// Get the service name if it's a string literal
 			var serviceName string
-			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if lit, ok := call.Args[spec.nameArg].(*ast.BasicLit); ok && lit.Kind == token.STRING {
 				serviceName = strings.Trim(lit.Value, `"`)
 			} else {
 				// If not a string literal, we can't analyze statically
 				serviceName = "<dynamic>"
 			}
 
-			// Get the context line
-			position := fset.Position(call.Pos())
-			callPos := call.Pos()
-
-			violation := Violation{
-				File:     path,
-				Line:     position.Line,
-				Function: getFunctionName(node, fset, callPos),
-				Service:  serviceName,
-			}
-
-			violations = append(violations, violation)
+			position := f.fset.Position(call.Pos())
+			violations = append(violations, Violation{
+				File:         f.path,
+				Line:         position.Line,
+				Function:     getFunctionName(f.node, f.fset, call.Pos()),
+				Service:      serviceName,
+				Method:       method,
+				Receiver:     receiver,
+				WithContext:  spec.withContext,
+				Module:       callerModule(f, modulesByFile[f.path], modulesByDir[filepath.Dir(f.path)], call.Pos()),
+				TargetModule: providerModule[serviceName],
+			})
 			return true
 		})
+	}
 
-		return nil
-	})
+	return violations, nil
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to scan directory: %w", err)
+// resolveCall reports whether the call is a container resolution method
+// (or the ResolveTyped helpers) and returns the method name and receiver expression
+func resolveCall(call *ast.CallExpr) (string, string, bool) {
+	fun := call.Fun
+	// ResolveTyped[T](...) is an index expression around the function
+	if index, ok := fun.(*ast.IndexExpr); ok {
+		fun = index.X
 	}
 
-	// Report violations
-	if len(violations) > 0 {
-		fmt.Printf("Found %d potential encapsulation violations:\n\n", len(violations))
+	var method, receiver string
+	switch f := fun.(type) {
+	case *ast.SelectorExpr:
+		method, receiver = f.Sel.Name, types.ExprString(f.X)
+	case *ast.Ident:
+		method = f.Name
+	default:
+		return "", "", false
+	}
 
-		// Group by file for cleaner output
-		byFile := make(map[string][]Violation)
-		for _, v := range violations {
-			byFile[v.File] = append(byFile[v.File], v)
+	spec, ok := resolveMethods[method]
+	// Provider.Resolve(container, ctx) shares the name; the argument count tells them apart
+	if !ok || len(call.Args) != spec.args {
+		return "", "", false
+	}
+	return method, receiver, true
+}
+
+// findModuleDefinitions returns the modules built by Module() methods in the file,
+// naming each by the first NewModule/DefaultModule call in its body
+func findModuleDefinitions(f parsedFile) []moduleDefinition {
+	var definitions []moduleDefinition
+	for _, decl := range f.node.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != "Module" || fd.Body == nil {
+			continue
 		}
 
-		for file, fileViolations := range byFile {
-			relPath, _ := filepath.Rel(rootDir, file)
-			fmt.Printf("%s:\n", relPath)
-			for _, v := range fileViolations {
-				fmt.Printf("  Line %d: %s -> Resolve('%s')\n", v.Line, v.Function, v.Service)
+		definition := moduleDefinition{
+			receiver: receiverTypeName(fd),
+			line:     f.fset.Position(fd.Pos()).Line,
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
 			}
-			fmt.Println()
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+
+			name := callName(call)
+			value := strings.Trim(lit.Value, `"`)
+			switch {
+			case name == "NewModule" || name == "DefaultModule":
+				if definition.name == "" {
+					definition.name = value
+				}
+			case strings.HasPrefix(name, "New") && strings.HasSuffix(name, "Provider"):
+				definition.providers = append(definition.providers, value)
+			}
+			return true
+		})
+
+		if definition.name != "" {
+			definitions = append(definitions, definition)
 		}
+	}
+	return definitions
+}
 
-		if mode == "strict" {
-			return fmt.Errorf("encapsulation violations detected in strict mode")
+// callerModule picks the module of the calling method's receiver type, then the module whose
+// Module() definition is nearest the call in the same file, then the first module in the package
+func callerModule(f parsedFile, fileModules, dirModules []moduleDefinition, callPos token.Pos) string {
+	if receiver := enclosingReceiver(f.node, callPos); receiver != "" {
+		for _, definition := range dirModules {
+			if definition.receiver == receiver {
+				return definition.name
+			}
 		}
-	} else {
-		fmt.Println("✓ No potential encapsulation violations found")
 	}
 
-	return nil
+	line := f.fset.Position(callPos).Line
+	best, bestDistance := "", -1
+	for _, definition := range fileModules {
+		distance := line - definition.line
+		if distance < 0 {
+			distance = -distance
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = definition.name, distance
+		}
+	}
+	if best != "" {
+		return best
+	}
+	if len(dirModules) > 0 {
+		return dirModules[0].name
+	}
+	return unknownModule
 }
 
-// Violation represents a potential encapsulation violation
-type Violation struct {
-	File     string
-	Line     int
-	Function string
-	Service  string
+// enclosingReceiver returns the receiver type of the method containing the position, if any
+func enclosingReceiver(node *ast.File, pos token.Pos) string {
+	for _, decl := range node.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Pos() <= pos && pos <= fd.End() {
+			return receiverTypeName(fd)
+		}
+	}
+	return ""
+}
+
+// receiverTypeName returns the receiver type name of a method (without the pointer), or ""
+func receiverTypeName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+	recvType := fd.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	if ident, ok := recvType.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// callName returns the function or method name of a call
+func callName(call *ast.CallExpr) string {
+	switch f := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	case *ast.Ident:
+		return f.Name
+	}
+	return ""
 }
 
 // getFunctionName attempts to find the function containing the position
@@ -134,8 +344,8 @@ func getFunctionName(node *ast.File, fset *token.FileSet, callPos token.Pos) str
 			if fd.Pos() <= callPos && callPos <= fd.End() {
 				if fd.Recv != nil {
 					// Method
-					if ident, ok := fd.Recv.List[0].Type.(*ast.Ident); ok {
-						funcName = ident.Name + "." + fd.Name.Name
+					if recv := receiverTypeName(fd); recv != "" {
+						funcName = recv + "." + fd.Name.Name
 					} else {
 						funcName = "method:" + fd.Name.Name
 					}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ordersSource = `package orders
+
+type OrdersPlugin struct{}
+
+func (p *OrdersPlugin) Module() *core.Module {
+	return core.NewModule("orders", "1.0.0").
+		WithProviders(core.NewFactoryProvider("orderService", nil, core.Singleton))
+}
+
+func (p *OrdersPlugin) Routes(container core.DIContainer, rc *core.RequestContainer, mc *core.ModuleContainer) {
+	var target interface{}
+	users, _ := container.Resolve("userService")
+	_ = rc.ResolveWithContext("orderService", ctx)
+	_ = mc.ResolveAsWithContext("userService", ctx, &target)
+	svc, _ := core.ResolveTyped[*UserService](container, "userService")
+	_, _ = users, svc
+}
+
+type provider struct{}
+
+// Provider.Resolve(container, ctx) is not a container lookup
+func (p *provider) Build(container core.DIContainer) {
+	p.Resolve(container, ctx)
+}
+`
+
+const usersSource = `package users
+
+type UsersPlugin struct{}
+
+func (p *UsersPlugin) Module() *core.Module {
+	module := core.DefaultModule("users", "1.0.0")
+	module.Providers = []core.Provider{core.NewValueProvider("userService", nil)}
+	return module
+}
+`
+
+func TestScanViolations_DetectsResolutionCalls(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "orders"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "users"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "orders", "orders.go"), []byte(ordersSource), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "users", "users.go"), []byte(usersSource), 0644))
+
+	violations, err := scanViolations(root)
+	require.NoError(t, err)
+	require.Len(t, violations, 4)
+
+	type result struct {
+		Method       string
+		Receiver     string
+		Service      string
+		WithContext  bool
+		Module       string
+		TargetModule string
+	}
+	var got []result
+	for _, v := range violations {
+		assert.Equal(t, "OrdersPlugin.Routes", v.Function)
+		got = append(got, result{v.Method, v.Receiver, v.Service, v.WithContext, v.Module, v.TargetModule})
+	}
+
+	assert.Equal(t, []result{
+		{"Resolve", "container", "userService", false, "orders", "users"},
+		{"ResolveWithContext", "rc", "orderService", true, "orders", "orders"},
+		{"ResolveAsWithContext", "mc", "userService", true, "orders", "users"},
+		{"ResolveTyped", "core", "userService", false, "orders", "users"},
+	}, got)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
@@ -73,9 +74,7 @@ func (p *ModuleA) Routes(engine *gin.Engine) error {
 
 	router.GET(core.RouteConfig{Path: "/api/module-a/private"}, func(c *gin.Context, ctrl struct{}) {
 		// Get container from context
-		if rc, exists := c.Get("requestContainer"); exists {
-			requestContainer := rc.(*core.RequestContainer)
-
+		if requestContainer, exists := core.GetRequestContainer(c); exists {
 			// Try to resolve private service
 			if service, err := requestContainer.Resolve("privateService"); err == nil {
 				if privateSvc, ok := service.(*PrivateService); ok {
@@ -93,9 +92,7 @@ func (p *ModuleA) Routes(engine *gin.Engine) error {
 
 	router.GET(core.RouteConfig{Path: "/api/module-a/exported"}, func(c *gin.Context, ctrl struct{}) {
 		// Get container from context
-		if rc, exists := c.Get("requestContainer"); exists {
-			requestContainer := rc.(*core.RequestContainer)
-
+		if requestContainer, exists := core.GetRequestContainer(c); exists {
 			// Try to resolve exported service
 			if service, err := requestContainer.Resolve("exportedService"); err == nil {
 				if exportedSvc, ok := service.(*ExportedService); ok {
@@ -158,9 +155,7 @@ func (p *ModuleB) Routes(engine *gin.Engine) error {
 	// This route tries to access ModuleA's private service (should fail)
 	router.GET(core.RouteConfig{Path: "/api/module-b/try-private"}, func(c *gin.Context, ctrl struct{}) {
 		// Get container from context
-		if rc, exists := c.Get("requestContainer"); exists {
-			requestContainer := rc.(*core.RequestContainer)
-
+		if requestContainer, exists := core.GetRequestContainer(c); exists {
 			// Try to resolve ModuleA's private service
 			if _, err := requestContainer.Resolve("privateService"); err != nil {
 				c.JSON(http.StatusForbidden, gin.H{
@@ -178,9 +173,7 @@ func (p *ModuleB) Routes(engine *gin.Engine) error {
 	// This route accesses ModuleA's exported service (should succeed)
 	router.GET(core.RouteConfig{Path: "/api/module-b/access-exported"}, func(c *gin.Context, ctrl struct{}) {
 		// Get container from context
-		if rc, exists := c.Get("requestContainer"); exists {
-			requestContainer := rc.(*core.RequestContainer)
-
+		if requestContainer, exists := core.GetRequestContainer(c); exists {
 			// Try to resolve ModuleA's exported service
 			if service, err := requestContainer.Resolve("exportedService"); err == nil {
 				if exportedSvc, ok := service.(*ExportedService); ok {
@@ -262,6 +255,8 @@ func main() {
 		Mode:      gin.ReleaseMode,
 		UseLogger: true,
 		Port:      8080,
+		// Give every request its own container, scoped to the module of its route
+		RequestContainers: &core.RequestContainerOptions{},
 	})
 
 	// Register ModuleA (with private and exported services)
@@ -273,27 +268,11 @@ func main() {
 	// Register GlobalModule (bypasses encapsulation)
 	app.RegisterPlugin(&GlobalModule{})
 
-	// Add request container middleware
 	router := app.GetEngine()
-	router.Use(func(c *gin.Context) {
-		// Create a simple module container for demonstration
-		moduleContainer := core.NewModuleContainer(
-			core.DefaultModule("request-scope", "1.0.0"),
-			app.GetContainer(),
-		)
-
-		// Create request-scoped container
-		requestContainer := core.NewRequestContainer(moduleContainer)
-		c.Set("requestContainer", requestContainer)
-
-		c.Next()
-	})
 
 	// Add a root route to demonstrate global service access
 	router.GET("/api/global", func(c *gin.Context) {
-		if rc, exists := c.Get("requestContainer"); exists {
-			requestContainer := rc.(*core.RequestContainer)
-
+		if requestContainer, exists := core.GetRequestContainer(c); exists {
 			// Try to resolve global service
 			if service, err := requestContainer.Resolve("globalService"); err == nil {
 				if globalSvc, ok := service.(*GlobalService); ok {
@@ -340,5 +319,7 @@ func main() {
 	fmt.Println("  - Only exported services are accessible to other modules")
 	fmt.Println("  - Global modules bypass encapsulation")
 
-	app.Listen()
+	if err := app.Listen(); err != nil {
+		log.Fatal(err)
+	}
 }
\ No newline at end of file
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
 	"github.com/gin-gonic/gin"
@@ -88,14 +90,22 @@ func main() {
 		Mode:      gin.DebugMode,
 		UseLogger: true,
 		Port:      8080,
+		// Create a request container per request, initialized with the decorators below
+		RequestContainers: &core.RequestContainerOptions{},
 	})
 
 	// Type assert to DoffApp to access decorator methods
 	doffApp := app.(*core.DoffApp)
 
 	// Register global decorators
-	doffApp.DecorateRequest("correlationID", "global-correlation")
-	doffApp.DecorateReply("standardResponse", func(data interface{}) map[string]interface{} {
+	// Each request gets its own correlation ID unless the client sends one
+	doffApp.DecorateRequestFactory("correlationID", func(c *gin.Context) interface{} {
+		if id := c.GetHeader("X-Correlation-ID"); id != "" {
+			return id
+		}
+		return fmt.Sprintf("req-%d", time.Now().UnixNano())
+	})
+	core.RegisterReplyHelper(doffApp.GetDecoratorManager(), "standardResponse", func(data interface{}) map[string]interface{} {
 		return map[string]interface{}{
 			"success": true,
 			"data":    data,
@@ -103,38 +113,14 @@ func main() {
 		}
 	})
 
-	// Get router and add middleware for request container
 	router := app.GetEngine()
 
-	// Add request container middleware
+	// Register a request-scoped service in each request container
 	router.Use(func(c *gin.Context) {
-		// Create module container (using root as parent)
-		moduleContainer := app.GetContainer().CreateModuleScope(
-			core.DefaultModule("request-scope", "1.0.0"),
-		)
-
-		// Create request-scoped container
-		requestContainer := core.NewRequestContainer(moduleContainer)
-
-		// Decorate request with correlation ID from header or use default
-		corrID := c.GetHeader("X-Correlation-ID")
-		if corrID == "" {
-			if defCorrID, exists := doffApp.GetDecoratorManager().GetRequestDecorator("correlationID"); exists {
-				corrID = defCorrID.(string)
-			}
-		}
-		requestContainer.DecorateRequest("correlationID", corrID)
-
-		// Register request-scoped service
+		requestContainer, _ := core.GetRequestContainer(c)
+		corrID, _ := requestContainer.GetRequestData("correlationID")
 		requestContainer.DecorateRequest("requestScopedService",
-			NewRequestScopedService(corrID))
-
-		// Initialize decorators from app's decorator manager
-		doffApp.GetDecoratorManager().InitializeRequestContainer(requestContainer)
-		doffApp.GetDecoratorManager().InitializeReplyHelpers(requestContainer)
-
-		// Set request container in context
-		c.Set("requestContainer", requestContainer)
+			NewRequestScopedService(corrID.(string)))
 
 		c.Next()
 	})
@@ -142,12 +128,11 @@ func main() {
 	// Register routes that use scoped containers
 	router.GET("/demo", func(c *gin.Context) {
 		// Get request container
-		rc, exists := c.Get("requestContainer")
+		requestContainer, exists := core.GetRequestContainer(c)
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "request container not found"})
 			return
 		}
-		requestContainer := rc.(*core.RequestContainer)
 
 		// Get correlation ID from request decorators
 		if corrID, exists := requestContainer.GetRequestData("correlationID"); exists {
@@ -171,26 +156,23 @@ func main() {
 
 	router.GET("/standard-response", func(c *gin.Context) {
 		// Get request container
-		rc, exists := c.Get("requestContainer")
+		requestContainer, exists := core.GetRequestContainer(c)
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "request container not found"})
 			return
 		}
-		requestContainer := rc.(*core.RequestContainer)
 
 		// Use standard response decorator
-		if helper, exists := requestContainer.GetReplyHelper("standardResponse"); exists {
-			if responseFn, ok := helper.(func(interface{}) map[string]interface{}); ok {
-				response := responseFn(gin.H{
-					"message": "This response uses a decorator",
-					"timestamp": "2024-01-01T00:00:00Z",
-				})
-				c.JSON(http.StatusOK, response)
-				return
-			}
+		response, err := core.CallReplyHelper[interface{}, map[string]interface{}](requestContainer, "standardResponse", gin.H{
+			"message": "This response uses a decorator",
+			"timestamp": "2024-01-01T00:00:00Z",
+		})
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{"message": err.Error()})
+			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Standard response decorator not found"})
+		c.JSON(http.StatusOK, response)
 	})
 
 	router.POST("/decorate", func(c *gin.Context) {
@@ -205,12 +187,11 @@ func main() {
 		}
 
 		// Get request container and add custom decoration
-		rc, exists := c.Get("requestContainer")
+		requestContainer, exists := core.GetRequestContainer(c)
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "request container not found"})
 			return
 		}
-		requestContainer := rc.(*core.RequestContainer)
 
 		// Add custom request decoration
 		requestContainer.DecorateRequest(req.Key, req.Value)
@@ -234,12 +215,11 @@ func main() {
 	// Add route that uses module-scoped service
 	router.GET("/module-service", func(c *gin.Context) {
 		// Get request container
-		rc, exists := c.Get("requestContainer")
+		requestContainer, exists := core.GetRequestContainer(c)
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "request container not found"})
 			return
 		}
-		requestContainer := rc.(*core.RequestContainer)
 
 		// Try to resolve from request container (will check parent module container)
 		if service, err := requestContainer.Resolve("simpleService"); err == nil {
@@ -262,5 +242,7 @@ func main() {
 	fmt.Println("  POST /decorate - Add custom request decorations")
 	fmt.Println("  GET /module-service - Uses module-scoped services")
 
-	app.Listen()
+	if err := app.Listen(); err != nil {
+		log.Fatal(err)
+	}
 }
\ No newline at end of file
@@ -1,23 +1,23 @@
 package main
 
 import (
-	"context"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/plugins/logger"
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/plugins/request"
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/plugins/requestid"
 )
 
 func main() {
 	config := &core.AppOptions{
-		Name:      "User Service API",
-		Mode:      "debug",
-		UseLogger: true,
-		Port:      8080,
+		Name:            "User Service API",
+		Mode:            "debug",
+		UseLogger:       true,
+		Port:            8080,
+		ShutdownTimeout: 5 * time.Second,  // In-flight requests get 5 seconds to finish
+		RequestTimeout:  30 * time.Second, // Routes answer 504 after 30 seconds
 		Cors: &core.CorsOptions{
 			AllowOrigins:     []string{"*"},
 			AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -32,8 +32,7 @@ func main() {
 	// Register global decorators - need to cast to DoffApp to access Decorate methods
 	if doffApp, ok := app.(*core.DoffApp); ok {
 		doffApp.Decorate("apiVersion", "v1")
-		doffApp.DecorateRequest("requestTimeout", 30) // seconds
-		doffApp.DecorateReply("successResponse", func(data interface{}) map[string]interface{} {
+		core.RegisterReplyHelper(doffApp.GetDecoratorManager(), "successResponse", func(data interface{}) map[string]interface{} {
 			return map[string]interface{}{
 				"success": true,
 				"data":    data,
@@ -42,28 +41,16 @@ func main() {
 		})
 	}
 
-	// Register plugins
+	// Register plugins (request IDs first so the logger sees them)
+	app.RegisterPlugin(requestid.NewRequestIDPlugin())
 	app.RegisterPlugin(logger.NewLoggerPlugin())
 	app.RegisterPlugin(request.NewRequestAuthentication())
 	app.RegisterPlugin(NewUserPlugin())
 
-	// Start server in a goroutine
-	go func() {
-		app.Listen()
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	println("Shutting down server...")
-
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := app.Shutdown(ctx); err != nil {
-		println("Server forced to shutdown:", err.Error())
+	// Serve until SIGINT/SIGTERM, then shut down gracefully
+	if err := app.Run(); err != nil {
+		println("Server stopped with error:", err.Error())
+		os.Exit(1)
 	}
 
 	println("Server exiting")
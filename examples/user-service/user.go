@@ -42,7 +42,7 @@ func (s *userService) GetUser(id string) (*User, error) {
 	if user, exists := s.users[id]; exists {
 		return user, nil
 	}
-	return nil, fmt.Errorf("user with ID %s not found", id)
+	return nil, core.ErrNotFound.WithMessage(fmt.Sprintf("user with ID %s not found", id))
 }
 
 // CreateUser creates a new user
@@ -57,7 +57,7 @@ func (s *userService) CreateUser(user *User) (*User, error) {
 // UpdateUser updates an existing user
 func (s *userService) UpdateUser(id string, user *User) (*User, error) {
 	if _, exists := s.users[id]; !exists {
-		return nil, fmt.Errorf("user with ID %s not found", id)
+		return nil, core.ErrNotFound.WithMessage(fmt.Sprintf("user with ID %s not found", id))
 	}
 	user.ID = id
 	s.users[id] = user
@@ -67,7 +67,7 @@ func (s *userService) UpdateUser(id string, user *User) (*User, error) {
 // DeleteUser deletes a user by ID
 func (s *userService) DeleteUser(id string) error {
 	if _, exists := s.users[id]; !exists {
-		return fmt.Errorf("user with ID %s not found", id)
+		return core.ErrNotFound.WithMessage(fmt.Sprintf("user with ID %s not found", id))
 	}
 	delete(s.users, id)
 	return nil
@@ -99,7 +99,7 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 	id := c.Param("id")
 	user, err := ctrl.userService.GetUser(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		core.AbortWithError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -109,13 +109,13 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 func (ctrl *UserController) CreateUser(c *gin.Context) {
 	var user User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		core.AbortWithError(c, core.ErrBadRequest.WithMessage(err.Error()))
 		return
 	}
 
 	createdUser, err := ctrl.userService.CreateUser(&user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		core.AbortWithError(c, err)
 		return
 	}
 
@@ -127,13 +127,13 @@ func (ctrl *UserController) UpdateUser(c *gin.Context) {
 	id := c.Param("id")
 	var user User
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		core.AbortWithError(c, core.ErrBadRequest.WithMessage(err.Error()))
 		return
 	}
 
 	updatedUser, err := ctrl.userService.UpdateUser(id, &user)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		core.AbortWithError(c, err)
 		return
 	}
 
@@ -145,7 +145,7 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
 	err := ctrl.userService.DeleteUser(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		core.AbortWithError(c, err)
 		return
 	}
 
@@ -154,31 +154,23 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 
 // ListUsers handles GET /users
 func (ctrl *UserController) ListUsers(c *gin.Context) {
-	// Get request container from context
+	users, err := ctrl.userService.ListUsers()
+	if err != nil {
+		core.AbortWithError(c, err)
+		return
+	}
+
+	// Wrap the response with the successResponse helper from decorators
 	if rc, exists := c.Get("requestContainer"); exists {
 		requestContainer := rc.(*core.RequestContainer)
-
-		// Get the successResponse helper from decorators
-		if helper, exists := requestContainer.GetReplyHelper("successResponse"); exists {
-			if successFn, ok := helper.(func(interface{}) map[string]interface{}); ok {
-				users, err := ctrl.userService.ListUsers()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-					return
-				}
-				c.JSON(http.StatusOK, successFn(gin.H{"users": users}))
-				return
-			}
+		response, err := core.CallReplyHelper[interface{}, map[string]interface{}](requestContainer, "successResponse", gin.H{"users": users})
+		if err == nil {
+			c.JSON(http.StatusOK, response)
+			return
 		}
 	}
 
 	// Fallback to normal response
-	users, err := ctrl.userService.ListUsers()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{"users": users})
 }
 
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIError is an error with an HTTP status and a machine-readable code, rendered
+// as the standard JSON error envelope:
+//
+//	{"error": {"status": 404, "code": "not_found", "message": "User not found", "details": ...}}
+type APIError struct {
+	Status  int         `json:"status"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	Err     error       `json:"-"` // Underlying cause, never sent to the client
+}
+
+// NewAPIError creates an API error
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// Well-known API errors; use WithMessage, WithDetails or WithCause for specific cases,
+// e.g. AbortWithError(c, ErrNotFound.WithMessage("user not found"))
+var (
+	ErrBadRequest           = NewAPIError(http.StatusBadRequest, "bad_request", http.StatusText(http.StatusBadRequest))
+	ErrUnauthorized         = NewAPIError(http.StatusUnauthorized, "unauthorized", http.StatusText(http.StatusUnauthorized))
+	ErrForbidden            = NewAPIError(http.StatusForbidden, "forbidden", http.StatusText(http.StatusForbidden))
+	ErrNotFound             = NewAPIError(http.StatusNotFound, "not_found", http.StatusText(http.StatusNotFound))
+	ErrNotAcceptable        = NewAPIError(http.StatusNotAcceptable, "not_acceptable", http.StatusText(http.StatusNotAcceptable))
+	ErrConflict             = NewAPIError(http.StatusConflict, "conflict", http.StatusText(http.StatusConflict))
+	ErrPayloadTooLarge      = NewAPIError(http.StatusRequestEntityTooLarge, "payload_too_large", http.StatusText(http.StatusRequestEntityTooLarge))
+	ErrUnsupportedMediaType = NewAPIError(http.StatusUnsupportedMediaType, "unsupported_media_type", http.StatusText(http.StatusUnsupportedMediaType))
+	ErrInternal             = NewAPIError(http.StatusInternalServerError, "internal_error", http.StatusText(http.StatusInternalServerError))
+	ErrServiceUnavailable   = NewAPIError(http.StatusServiceUnavailable, "service_unavailable", http.StatusText(http.StatusServiceUnavailable))
+	ErrGatewayTimeout       = NewAPIError(http.StatusGatewayTimeout, "timeout", http.StatusText(http.StatusGatewayTimeout))
+)
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches API errors with the same status and code, so errors.Is(err, ErrNotFound)
+// holds for copies made with the With* methods
+func (e *APIError) Is(target error) bool {
+	other, ok := target.(*APIError)
+	return ok && other.Status == e.Status && other.Code == e.Code
+}
+
+// WithMessage returns a copy of the error with another message
+func (e *APIError) WithMessage(message string) *APIError {
+	copied := *e
+	copied.Message = message
+	return &copied
+}
+
+// WithDetails returns a copy of the error carrying details for the client
+func (e *APIError) WithDetails(details interface{}) *APIError {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// WithCause returns a copy of the error wrapping the underlying cause
+func (e *APIError) WithCause(err error) *APIError {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// ToAPIError maps any error to an API error: API errors in the chain are returned
+// as-is, dependency resolution timeouts map to 503, other timeouts to 504, oversized
+// bodies to 413, rejected content types to 415, and everything else to a 500 that
+// does not leak the error text
+func ToAPIError(err error) *APIError {
+	var apiErr *APIError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return ErrInternal
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.Is(err, ErrResolutionTimeout):
+		return ErrServiceUnavailable.WithCause(err)
+	case errors.Is(err, ErrRouteTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrGatewayTimeout.WithCause(err)
+	case errors.Is(err, ErrBodyTooLarge), errors.As(err, &maxBytesErr):
+		return ErrPayloadTooLarge.WithCause(err)
+	case errors.Is(err, ErrUnsupportedContentType):
+		return ErrUnsupportedMediaType.WithCause(err)
+	default:
+		return ErrInternal.WithCause(err)
+	}
+}
+
+// errorEnvelope returns the JSON body for an API error
+func errorEnvelope(apiErr *APIError) gin.H {
+	return gin.H{"error": apiErr}
+}
+
+// AbortWithError records err on the context (so OnError hooks see it), stops the
+// handler chain and responds with the JSON error envelope
+func AbortWithError(c *gin.Context, err error) {
+	if err == nil {
+		err = ErrInternal
+	}
+	c.Error(err)
+	respondWithError(c, err, ToAPIError(err))
+}
+
+// respondWithError stops the handler chain and responds to err with the error handler
+// of the module owning the matched route (see Module.WithOnError), or with the JSON
+// error envelope of apiErr when there is none or it writes nothing
+func respondWithError(c *gin.Context, err error, apiErr *APIError) {
+	if handler := moduleErrorHandler(c); handler != nil {
+		handler(c, err)
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+	}
+	c.AbortWithStatusJSON(apiErr.Status, errorEnvelope(apiErr))
+}
+
+// moduleErrorHandler returns the error handler of the module that registered the
+// matched route, found through the route registry
+func moduleErrorHandler(c *gin.Context) ModuleErrorHandler {
+	value, exists := c.Get("app")
+	if !exists {
+		return nil
+	}
+	app, ok := value.(*DoffApp)
+	if !ok || app.pluginManager == nil {
+		return nil
+	}
+	return app.pluginManager.moduleErrorHandler(c.Request.Method, c.FullPath())
+}
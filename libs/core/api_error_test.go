@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeErrorEnvelope(t *testing.T, recorder *httptest.ResponseRecorder) APIError {
+	t.Helper()
+	var body struct{ Error APIError }
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return body.Error
+}
+
+func TestToAPIError(t *testing.T) {
+	notFound := ErrNotFound.WithMessage("user not found")
+	assert.Same(t, notFound, ToAPIError(fmt.Errorf("lookup: %w", notFound)))
+	assert.True(t, errors.Is(notFound, ErrNotFound))
+	assert.False(t, errors.Is(notFound, ErrUnauthorized))
+
+	timeout := ToAPIError(context.DeadlineExceeded)
+	assert.Equal(t, http.StatusGatewayTimeout, timeout.Status)
+	assert.ErrorIs(t, timeout, context.DeadlineExceeded)
+
+	internal := ToAPIError(errors.New("db password is hunter2"))
+	assert.Equal(t, http.StatusInternalServerError, internal.Status)
+	assert.Equal(t, "Internal Server Error", internal.Message)
+}
+
+func TestAbortWithError(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+	app.GetEngine().GET("/users/:id", func(c *gin.Context) {
+		AbortWithError(c, ErrNotFound.WithMessage("user not found").WithDetails(gin.H{"id": c.Param("id")}))
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	apiErr := decodeErrorEnvelope(t, recorder)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, "not_found", apiErr.Code)
+	assert.Equal(t, "user not found", apiErr.Message)
+	assert.Equal(t, map[string]interface{}{"id": "42"}, apiErr.Details)
+
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], ErrNotFound)
+}
+
+func TestExecuteOnError_RendersUnwrittenErrors(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	app.GetEngine().GET("/denied", func(c *gin.Context) {
+		c.Error(ErrUnauthorized)
+	})
+	app.GetEngine().GET("/handled", func(c *gin.Context) {
+		c.Error(errors.New("logged only"))
+		c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/denied", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "unauthorized", decodeErrorEnvelope(t, recorder).Code)
+
+	// Responses written by the handler are left alone
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/handled", nil))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.JSONEq(t, `{"status":"accepted"}`, recorder.Body.String())
+}
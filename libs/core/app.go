@@ -2,27 +2,82 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"slices"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AppOptions struct {
-	Name          string         `json:"name"`
-	Mode          string         `json:"mode"`
-	Port          int16          `json:"port"`
-	Cors          any            `json:"cors,omitempty"`
-	UseLogger     bool           `json:"useLogger"`
-	Logger        Logger         `json:"logger,omitempty"`
-	Plugins       []PluginConfig `json:"plugins,omitempty"`
-	ConfigPath    string         `json:"configPath,omitempty"`
-	Authenticator any            `json:"authenticator,omitempty"`
+	Name            string         `json:"name"`
+	Mode            string         `json:"mode"`
+	Port            int16          `json:"port"`
+	Cors            any            `json:"cors,omitempty"`
+	UseLogger       bool           `json:"useLogger"`
+	Logger          Logger         `json:"logger,omitempty"`
+	Plugins         []PluginConfig `json:"plugins,omitempty"`
+	ConfigPath      string         `json:"configPath,omitempty"`
+	ConfigEnvPrefix string         `json:"configEnvPrefix,omitempty"` // Defaults to DOFFY_
+	ConfigEnvironment string       `json:"configEnvironment,omitempty"` // Overlay merged over the config file, e.g. "staging"; defaults to DOFFY_ENV
+	RequiredConfig  []string        `json:"requiredConfig,omitempty"` // Config keys that must be set, see ConfigManager.Require
+	ConfigSchema    map[string]Kind `json:"configSchema,omitempty"`   // Config keys that must be set and coercible to their Kind, see ConfigManager.ValidateSchema
+	LogLevel        LogLevel       `json:"logLevel,omitempty"`        // Minimum level of the default logger
+	LogFormat       LogFormat      `json:"logFormat,omitempty"`       // "text" (default) or "json"
+	Authenticator   any            `json:"authenticator,omitempty"`
+	HealthCheck     bool           `json:"healthCheck,omitempty"` // Registers /healthz and /readyz
+	OpenAPI         bool           `json:"openAPI,omitempty"`     // Serves the routes' OpenAPI document at /openapi.json
+	ShutdownTimeout time.Duration  `json:"shutdownTimeout,omitempty"` // Grace period for Run; defaults to DefaultShutdownTimeout
+	DisableRecovery bool           `json:"disableRecovery,omitempty"` // Let handler panics propagate instead of answering 500
+	RequestTimeout  time.Duration  `json:"requestTimeout,omitempty"`  // Default RouteConfig.Timeout; zero means no limit
+	// ResponseBodyLimit captures up to this many bytes of JSON response bodies into
+	// ResponseInfo.Body for OnResponse hooks; zero (the default) disables capture
+	ResponseBodyLimit int `json:"responseBodyLimit,omitempty"`
+	// RequestContainers gives every request a RequestContainer (see GetRequestContainer);
+	// nil (the default) leaves creating them to the app
+	RequestContainers *RequestContainerOptions `json:"-"`
+	// MaxBodySize caps request bodies at this many bytes, answering 413 when exceeded;
+	// zero (the default) means no limit
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
+	// MissingRequestContainer decides how the app's enhanced routers resolve handler
+	// parameters when no request container is in context; defaults to FallbackToContainer
+	MissingRequestContainer MissingRequestContainer `json:"-"`
+	// TrustedProxies lists the proxy IPs or CIDRs (e.g. "10.0.0.0/8") whose
+	// X-Forwarded-For and X-Real-IP headers are believed for the client IP (see ClientIP)
+	// Empty (the default) trusts no proxy, so the client IP is the connection's peer
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+	// NamingStrategy names the services resolved by type (handler and constructor
+	// parameters, RegisterByType, GetByType) in every container of the app; nil (the
+	// default) tries the full type name, then the short name (see NamingStrategyContainer)
+	NamingStrategy NamingStrategy `json:"-"`
+	// PrefixCollisions decides what registering a module whose prefix overlaps another
+	// module's does (e.g. "/api" and "/api/v1"); defaults to PrefixCollisionsAllowed
+	PrefixCollisions PrefixCollisionMode `json:"prefixCollisions,omitempty"`
+	// AsyncInitConcurrency bounds how many async providers are initialized at once;
+	// nil (the default) means DefaultAsyncInitConcurrency, and zero or negative means
+	// one at a time, in plugin order (see PluginManager.SetAsyncInitConcurrency)
+	AsyncInitConcurrency *int `json:"asyncInitConcurrency,omitempty"`
+	// DisabledRoutes lists routes answered with DisabledRouteStatus instead of being
+	// served, as "METHOD /path" by full path pattern (e.g. "GET /users/:id"); they
+	// can be enabled at runtime with EnableRoute (see DisableRoute)
+	DisabledRoutes []string `json:"disabledRoutes,omitempty"`
+	// DisabledRouteStatus is answered to disabled routes: http.StatusNotFound (the
+	// default) or http.StatusServiceUnavailable
+	DisabledRouteStatus int `json:"disabledRouteStatus,omitempty"`
 }
 
+// DefaultShutdownTimeout is how long Run waits for in-flight requests to drain
+const DefaultShutdownTimeout = 10 * time.Second
+
 type DoffServer interface {
-	Listen()
+	Listen() error
+	Run() error
 	Shutdown(ctx context.Context) error
 	RegisterPlugin(plugin Plugin) error
 	GetContainer() DIContainer
@@ -41,26 +96,65 @@ type DoffApp struct {
 	logger           Logger
 	container        DIContainer         // Root container
 	moduleContainers  map[string]*ModuleContainer  // Module-scoped containers
+	moduleMu          sync.Mutex                   // Guards moduleContainers
+	requestContainers *RequestContainerOptions     // Built-in request container middleware, nil when disabled
 	pluginManager    *PluginManager
 	httpServer       *http.Server
+	shutdownTimeout   time.Duration           // Grace period used by Run
+	requestTimeout    time.Duration           // Default route timeout (AppOptions.RequestTimeout)
+	maxBodySize       int64                   // Request body limit (AppOptions.MaxBodySize)
+	missingRequestContainer MissingRequestContainer // AppOptions.MissingRequestContainer
 	configManager     ConfigManager
+	configErr         error                   // Config load error, logged once the logger exists
+	serverErr         error                   // Server setup error (e.g. invalid TrustedProxies), returned on startup
 	decoratorManager  *DecoratorManager       // Decorator API
+	mounts            []mountedApp            // Apps served under a prefix, see Mount
+	routeToggles      *routeToggles           // Disabled routes, see DisableRoute
 }
 
-func (d *DoffApp) initServer() *DoffApp {
+func (d *DoffApp) initServer(recovery bool, trustedProxies []string) *DoffApp {
 	gin.SetMode(d.mode)
 	d.server = gin.New()
 
+	// gin trusts every proxy by default; trust only the configured ones
+	if err := d.server.SetTrustedProxies(trustedProxies); err != nil {
+		d.serverErr = fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	// Outermost, so panics in any framework or route middleware are recovered
+	if recovery {
+		d.server.Use(d.recoveryMiddleware())
+	}
+
 	// Add app and DI container to context
 	d.server.Use(func(c *gin.Context) {
 		c.Set("app", d)
 		c.Set("container", d.container)
+		defer d.disposeCreatedRequestContainer(c)
 		c.Next()
 	})
 
+	if d.requestContainers != nil {
+		d.server.Use(d.requestContainerMiddleware())
+	}
+
 	// Add lifecycle middleware
 	lifecycleManager := d.pluginManager.GetLifecycleManager()
 
+	// OnError/OnResponse wrap everything after this point, including OnRequest
+	d.server.Use(lifecycleManager.ResponseMiddleware())
+
+	// Before OnRequest, so hooks reading the body are capped too
+	if d.maxBodySize > 0 {
+		d.server.Use(bodyLimitHandler(d.maxBodySize))
+	}
+
+	// Disabled routes are answered before their module or hooks do any work
+	d.server.Use(d.routeToggles.middleware())
+
+	// Lazy modules initialize on the first request to one of their routes
+	d.server.Use(d.pluginManager.lazyModuleMiddleware())
+
 	d.server.Use(func(c *gin.Context) {
 		// Execute OnRequest hooks
 		lifecycleManager.ExecuteOnRequest(c)
@@ -73,20 +167,56 @@ func (d *DoffApp) initServer() *DoffApp {
 	return d
 }
 
-func (d *DoffApp) initConfig(configPath string) *DoffApp {
-	d.configManager = NewConfigManager()
-	if err := d.configManager.Load(configPath); err != nil {
-		// Can't log yet since logger might not be initialized
-		fmt.Printf("Failed to load configuration: %v\n", err)
+func (d *DoffApp) initConfig(configPath string, envPrefix string, environment string) *DoffApp {
+	var opts []ConfigOption
+	if envPrefix != "" {
+		opts = append(opts, WithEnvPrefix(envPrefix))
+	}
+	if environment != "" {
+		opts = append(opts, WithEnvironment(environment))
 	}
+
+	d.configManager = NewConfigManager(opts...)
+	// Can't log yet since logger might not be initialized; initLogger reports it
+	d.configErr = d.configManager.Load(configPath)
 	return d
 }
 
-func (d *DoffApp) initLogger(useLogger bool, customLogger Logger) *DoffApp {
+// validateConfig checks the required keys and schema of AppOptions, logging the problems
+func (d *DoffApp) validateConfig(required []string, schema map[string]Kind) error {
+	if len(required) == 0 && len(schema) == 0 {
+		return nil
+	}
+	err := errors.Join(d.configManager.Require(required...), d.configManager.ValidateSchema(schema))
+	if err != nil {
+		err = fmt.Errorf("invalid configuration: %w", err)
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelError,
+			Event:    "ConfigValidationError",
+			Messages: "Configuration does not match the required keys and schema",
+			Error:    err,
+		})
+	}
+	return err
+}
+
+func (d *DoffApp) initLogger(useLogger bool, customLogger Logger, loggerOptions LoggerOptions) *DoffApp {
 	if useLogger && customLogger != nil {
 		d.logger = customLogger
 	} else {
-		d.logger = DefaultLogger()
+		d.logger = DefaultLoggerWithOptions(loggerOptions)
+	}
+
+	// Route framework warnings (e.g. encapsulation violations) through the app logger
+	SetFrameworkLogger(d.logger)
+
+	if d.configErr != nil {
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelWarn,
+			Event:    "ConfigLoadError",
+			Messages: "Failed to load configuration",
+			Error:    d.configErr,
+		})
 	}
 
 	// Register logger in DI container
@@ -125,25 +255,95 @@ func (d *DoffApp) initAuthenticator(authenticator interface{}) *DoffApp {
 			return authenticator, nil
 		})
 	}
+
+	// Authenticate non-public routes with the configured authenticator
+	if auth, ok := authenticator.(Authenticator); ok && d.pluginManager != nil {
+		d.pluginManager.GetLifecycleManager().AddHook(NewAuthHook(auth))
+	}
 	return d
 }
 
-func (d *DoffApp) Listen() {
-	if d.logger == nil {
-		panic("logger is not initialized")
+// Validate verifies the plugin and module wiring, and the injected parameters of the
+// enhanced routes registered so far, without starting the server
+// It is safe to call from CI or health checks; see PluginManager.Validate
+func (d *DoffApp) Validate() error {
+	if d.pluginManager == nil {
+		return nil
 	}
+	return errors.Join(d.pluginManager.Validate(context.Background()), d.validateDisabledRoutes())
+}
 
-	addr := fmt.Sprintf(":%v", d.config.Port)
+// Init runs the OnReady hooks, initializes plugins and registers their routes without
+// listening, so the engine can serve requests in-process (see the testkit package)
+// Use it instead of Listen or Run, not before them
+func (d *DoffApp) Init() error {
+	return d.prepare()
+}
+
+// Listen initializes plugins, registers their routes and serves until the server is shut down
+// Startup and serve failures are returned; a clean Shutdown returns nil
+func (d *DoffApp) Listen() error {
+	if err := d.prepare(); err != nil {
+		return err
+	}
+	return d.serve()
+}
+
+// Run starts the server and blocks until SIGINT/SIGTERM, then shuts down gracefully
+// within AppOptions.ShutdownTimeout. Startup and serve failures are returned instead of panicking
+func (d *DoffApp) Run() error {
+	return d.RunContext(context.Background())
+}
+
+// RunContext is Run with an additional context; cancelling it shuts the server down like a signal
+func (d *DoffApp) RunContext(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := d.prepare(); err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- d.serve()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), d.shutdownTimeout)
+	defer cancel()
+
+	err := d.Shutdown(shutdownCtx)
+	if serveErr := <-serveErr; serveErr != nil {
+		return errors.Join(err, serveErr)
+	}
+	return err
+}
+
+// prepare runs OnReady hooks, initializes plugins, registers their routes and creates the HTTP server
+func (d *DoffApp) prepare() error {
+	if d.logger == nil {
+		return fmt.Errorf("logger is not initialized")
+	}
+	if d.serverErr != nil {
+		return d.serverErr
+	}
 
 	// Execute OnReady hooks (serial, blocks startup)
 	if d.pluginManager != nil {
 		if err := d.pluginManager.GetLifecycleManager().ExecuteOnReady(d); err != nil {
 			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
 				Event:    "OnReadyError",
 				Messages: "Failed to execute OnReady hooks",
 				Error:    err,
 			})
-			panic(err)
+			return err
 		}
 	}
 
@@ -151,35 +351,61 @@ func (d *DoffApp) Listen() {
 	if d.pluginManager != nil {
 		if err := d.pluginManager.InitializePlugins(); err != nil {
 			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
 				Event:    "PluginInitializationError",
 				Messages: "Failed to initialize plugins",
 				Error:    err,
 			})
-			panic(err)
+			return err
 		}
 
 		// Register plugin routes
 		if err := d.pluginManager.RegisterRoutes(d.server); err != nil {
 			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
 				Event:    "PluginRouteRegistrationError",
 				Messages: "Failed to register plugin routes",
 				Error:    err,
 			})
-			panic(err)
+			return err
+		}
+
+		// Duplicate routes were skipped during registration; refuse to serve with them
+		if err := d.pluginManager.RouteConflicts(); err != nil {
+			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
+				Event:    "RouteConflictError",
+				Messages: "Duplicate routes registered",
+				Error:    err,
+			})
+			return err
 		}
 	}
 
-	// Add CORS if configured
-	if d.config.Port != 0 {
-		// This will be handled by the CORS plugin
+	if err := d.prepareMounts(); err != nil {
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelError,
+			Event:    "MountedAppInitializationError",
+			Messages: "Failed to initialize mounted apps",
+			Error:    err,
+		})
+		return err
 	}
 
 	// Create HTTP server
 	d.httpServer = &http.Server{
-		Addr:    addr,
+		Addr:    fmt.Sprintf(":%v", d.config.Port),
 		Handler: d.server,
 	}
 
+	return nil
+}
+
+// serve listens on the configured port until the server is shut down
+// A clean shutdown (http.ErrServerClosed) returns nil
+func (d *DoffApp) serve() error {
+	addr := d.httpServer.Addr
+
 	payload := &LoggerItem{
 		Event:    "StartServer",
 		Messages: fmt.Sprintf("%s is starting.....", d.name),
@@ -203,8 +429,9 @@ func (d *DoffApp) Listen() {
 	}()
 
 	if err := d.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		panic(err)
+		return err
 	}
+	return nil
 }
 
 func (d *DoffApp) Shutdown(ctx context.Context) error {
@@ -224,12 +451,26 @@ func (d *DoffApp) Shutdown(ctx context.Context) error {
 	}
 
 	// Shutdown HTTP server
-	err := d.httpServer.Shutdown(ctx)
+	var err error
+	if d.httpServer != nil {
+		err = d.httpServer.Shutdown(ctx)
+	}
+
+	// Shut mounted apps down while the services they may share are still up
+	if mountErr := d.shutdownMounts(ctx); mountErr != nil {
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelError,
+			Event:    "MountedAppShutdownError",
+			Messages: "Error during mounted app shutdown",
+			Error:    mountErr,
+		})
+	}
 
 	// Execute OnClose hooks (final cleanup)
 	if d.pluginManager != nil {
 		if closeErr := d.pluginManager.GetLifecycleManager().ExecuteOnClose(); closeErr != nil {
 			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
 				Event:    "OnCloseError",
 				Messages: "Error during OnClose hooks",
 				Error:    closeErr,
@@ -240,6 +481,7 @@ func (d *DoffApp) Shutdown(ctx context.Context) error {
 	// Shutdown plugins
 	if pluginErr := d.pluginManager.ShutdownPlugins(); pluginErr != nil {
 		d.logger.Infor(&LoggerItem{
+			Level:    LevelError,
 			Event:    "PluginShutdownError",
 			Messages: "Error during plugin shutdown",
 			Error:    pluginErr,
@@ -253,6 +495,11 @@ func (d *DoffApp) RegisterPlugin(plugin Plugin) error {
 	return d.pluginManager.RegisterPlugin(plugin)
 }
 
+// UnregisterPlugin shuts a plugin down and removes it (see PluginManager.UnregisterPlugin)
+func (d *DoffApp) UnregisterPlugin(name string) error {
+	return d.pluginManager.UnregisterPlugin(name)
+}
+
 func (d *DoffApp) GetContainer() DIContainer {
 	return d.container
 }
@@ -271,6 +518,30 @@ func (d *DoffApp) GetRouter() *Router {
 	return NewRouter(d.server, d.container)
 }
 
+// GetRoutes returns every route registered through the app's routers
+// (method, full path, owning module and options), then those of the mounted apps
+func (d *DoffApp) GetRoutes() []RouteInfo {
+	return append(d.pluginManager.GetRoutes(), d.mountedRoutes()...)
+}
+
+// AllowedMethods returns the HTTP methods of the routes matching path, e.g. "/users/42"
+// for "/users/:id", whether registered through the app's routers or on the engine
+// Paths under a mounted app are answered by that app
+func (d *DoffApp) AllowedMethods(path string) []string {
+	if sub, subPath, mounted := d.mountFor(path); mounted {
+		return sub.AllowedMethods(subPath)
+	}
+
+	var methods []string
+	for _, route := range d.server.Routes() {
+		if !slices.Contains(methods, route.Method) && routePatternMatches(route.Path, path) {
+			methods = append(methods, route.Method)
+		}
+	}
+	slices.SortStableFunc(methods, func(a, b string) int { return methodRank(a) - methodRank(b) })
+	return methods
+}
+
 func CreateDoffApp(options *AppOptions) DoffServer {
 	app := &DoffApp{
 		name: options.Name,
@@ -280,27 +551,78 @@ func CreateDoffApp(options *AppOptions) DoffServer {
 		},
 		moduleContainers:  make(map[string]*ModuleContainer),
 		decoratorManager:  NewDecoratorManager(),
+		shutdownTimeout:   options.ShutdownTimeout,
+		requestTimeout:    options.RequestTimeout,
+		requestContainers: options.RequestContainers,
+		maxBodySize:       options.MaxBodySize,
+		routeToggles:      newRouteToggles(options.DisabledRouteStatus),
+
+		missingRequestContainer: options.MissingRequestContainer,
+	}
+	if app.shutdownTimeout <= 0 {
+		app.shutdownTimeout = DefaultShutdownTimeout
 	}
 
 	// Initialize configuration first
-	app.initConfig(options.ConfigPath)
+	app.initConfig(options.ConfigPath, options.ConfigEnvPrefix, options.ConfigEnvironment)
 
 	// Initialize DI container and plugin manager
 	app.initDIContainer()
+	app.pluginManager.SetPrefixCollisionMode(options.PrefixCollisions)
+	app.pluginManager.SetPluginConfigs(options.Plugins)
+	if options.AsyncInitConcurrency != nil {
+		app.pluginManager.SetAsyncInitConcurrency(*options.AsyncInitConcurrency)
+	}
+	if options.NamingStrategy != nil {
+		app.container.(NamingStrategyContainer).SetNamingStrategy(options.NamingStrategy)
+	}
 
 	// Initialize logger
-	app.initLogger(options.UseLogger, options.Logger)
+	app.initLogger(options.UseLogger, options.Logger, LoggerOptions{
+		Level:  options.LogLevel,
+		Format: options.LogFormat,
+	})
 
 	// Initialize authenticator
 	app.initAuthenticator(options.Authenticator)
 
 	// Initialize server
-	app.initServer()
+	app.pluginManager.GetLifecycleManager().SetResponseBodyLimit(options.ResponseBodyLimit)
+	app.initServer(!options.DisableRecovery, options.TrustedProxies)
+
+	// Fail fast on missing or mistyped config: startup returns the problems
+	if err := app.validateConfig(options.RequiredConfig, options.ConfigSchema); err != nil {
+		app.serverErr = errors.Join(app.serverErr, err)
+	}
+
+	// Routes are registered later; Validate reports entries matching none
+	for _, entry := range options.DisabledRoutes {
+		method, path, err := parseRouteKey(entry)
+		if err != nil {
+			app.serverErr = errors.Join(app.serverErr, err)
+			continue
+		}
+		app.routeToggles.set(method+":"+path, true)
+	}
+
+	if options.HealthCheck {
+		app.registerHealthRoutes()
+	}
+	if options.OpenAPI {
+		app.registerOpenAPIRoute()
+	}
 
 	// Register CORS plugin if configured
 	if options.Cors != nil {
 		corsPlugin := NewCorsPlugin(options.Cors)
-		app.RegisterPlugin(corsPlugin)
+		if err := app.RegisterPlugin(corsPlugin); err != nil {
+			app.logger.Infor(&LoggerItem{
+				Level:    LevelError,
+				Event:    "CorsPluginError",
+				Messages: "Failed to register CORS plugin",
+				Error:    err,
+			})
+		}
 	}
 
 	return app
@@ -321,11 +643,33 @@ func (d *DoffApp) DecorateRequest(name string, defaultValue interface{}) error {
 	return d.decoratorManager.DecorateRequest(name, defaultValue)
 }
 
+// DecorateRequestFactory registers a request-scoped decorator computed per request
+// The factory must be safe to call concurrently
+func (d *DoffApp) DecorateRequestFactory(name string, factory RequestDecoratorFactory) error {
+	return d.decoratorManager.DecorateRequestFactory(name, factory)
+}
+
 // DecorateReply registers a reply helper function
 func (d *DoffApp) DecorateReply(name string, fn interface{}) error {
 	return d.decoratorManager.DecorateReply(name, fn)
 }
 
+// OverrideDecorate registers or replaces an instance-level decorator, e.g. to replace
+// a default provided by a plugin
+func (d *DoffApp) OverrideDecorate(name string, value interface{}) {
+	d.decoratorManager.OverrideDecorate(name, value)
+}
+
+// OverrideDecorateRequest registers or replaces a request-scoped decorator default value
+func (d *DoffApp) OverrideDecorateRequest(name string, defaultValue interface{}) {
+	d.decoratorManager.OverrideDecorateRequest(name, defaultValue)
+}
+
+// OverrideDecorateReply registers or replaces a reply helper function
+func (d *DoffApp) OverrideDecorateReply(name string, fn interface{}) {
+	d.decoratorManager.OverrideDecorateReply(name, fn)
+}
+
 // GetDecoratorManager returns the decorator manager
 func (d *DoffApp) GetDecoratorManager() *DecoratorManager {
 	return d.decoratorManager
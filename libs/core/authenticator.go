@@ -1,11 +1,122 @@
 package core
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
 
 type Authenticator interface {
 	Authenticate(ctx context.Context, token string) (bool, error)
 	Assert(ctx context.Context, token string) (bool, error)
 }
 
+// ClaimsAuthenticator is an Authenticator that also returns the parsed token claims
+// The auth hook prefers ParseClaims when available so the claims reach handlers via AuthResult
+type ClaimsAuthenticator interface {
+	Authenticator
+	ParseClaims(ctx context.Context, token string) (interface{}, error)
+}
+
+// AuthResultKey is the key under which the AuthResult is stored in the gin context
+// and the request container
+const AuthResultKey = "auth"
+
+// AuthResult is the outcome of authenticating a request
+type AuthResult struct {
+	Token         string
+	Authenticated bool
+	Claims        interface{} // set when the authenticator is a ClaimsAuthenticator
+}
+
+// GetAuthResult returns the AuthResult stored by the auth hook
+func GetAuthResult(c *gin.Context) (*AuthResult, bool) {
+	value, exists := c.Get(AuthResultKey)
+	if !exists {
+		return nil, false
+	}
+	result, ok := value.(*AuthResult)
+	return result, ok
+}
+
+// IsPublicRoute reports whether the matched route was registered with IsAuth: false
+func IsPublicRoute(c *gin.Context) bool {
+	app, exists := c.Get("app")
+	if !exists {
+		return false
+	}
+	doffApp, ok := app.(*DoffApp)
+	if !ok || doffApp.pluginManager == nil {
+		return false
+	}
+	return doffApp.pluginManager.IsPublicRoute(c.Request.Method, c.FullPath())
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(c *gin.Context) string {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// AuthHook authenticates every request to a non-public route with the registered Authenticator
+type AuthHook struct {
+	authenticator Authenticator
+}
+
+// NewAuthHook creates an auth hook for the given authenticator
+func NewAuthHook(authenticator Authenticator) *AuthHook {
+	return &AuthHook{
+		authenticator: authenticator,
+	}
+}
+
+// OnRequest implements LifecycleHook
+// Unmatched routes are skipped so they still 404 (and CORS preflights pass through)
+func (h *AuthHook) OnRequest(c *gin.Context) {
+	if c.FullPath() == "" || IsPublicRoute(c) {
+		return
+	}
+
+	token := bearerToken(c)
+	if token == "" {
+		AbortWithError(c, ErrUnauthorized)
+		return
+	}
+
+	result := &AuthResult{
+		Token:         token,
+		Authenticated: true,
+	}
+
+	if claimsAuthenticator, ok := h.authenticator.(ClaimsAuthenticator); ok {
+		claims, err := claimsAuthenticator.ParseClaims(c.Request.Context(), token)
+		if err != nil {
+			AbortWithError(c, ErrUnauthorized.WithCause(err))
+			return
+		}
+		result.Claims = claims
+	} else {
+		authenticated, err := h.authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil || !authenticated {
+			AbortWithError(c, ErrUnauthorized.WithCause(err))
+			return
+		}
+	}
+	c.Set(AuthResultKey, result)
+	if requestContainer, ok := GetRequestContainer(c); ok {
+		requestContainer.DecorateRequest(AuthResultKey, result)
+	}
+}
+
+// PreHandler implements LifecycleHook
+func (h *AuthHook) PreHandler(c *gin.Context) {}
 
+// OnResponse implements LifecycleHook
+func (h *AuthHook) OnResponse(c *gin.Context, response interface{}) {}
 
+// OnError implements LifecycleHook
+func (h *AuthHook) OnError(c *gin.Context, err error) {}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthenticator accepts a fixed token and fails on "broken"
+type fakeAuthenticator struct {
+	validToken string
+	calls      []string
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context, token string) (bool, error) {
+	a.calls = append(a.calls, token)
+	if token == "broken" {
+		return false, errors.New("token store unavailable")
+	}
+	return token == a.validToken, nil
+}
+
+func (a *fakeAuthenticator) Assert(ctx context.Context, token string) (bool, error) {
+	return a.Authenticate(ctx, token)
+}
+
+func newAuthTestApp(t *testing.T, authenticator Authenticator) *DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := CreateDoffApp(&AppOptions{
+		Name:          "auth-test",
+		Mode:          gin.TestMode,
+		Authenticator: authenticator,
+	}).(*DoffApp)
+
+	public := false
+	router := app.GetRouter()
+	router.GET(RouteConfig{Path: "/public", IsAuth: &public}, func(c *gin.Context, container DIContainer) {
+		c.Status(http.StatusOK)
+	})
+	router.GET(RouteConfig{Path: "/private"}, func(c *gin.Context, container DIContainer) {
+		result, ok := GetAuthResult(c)
+		require.True(t, ok)
+		c.String(http.StatusOK, result.Token)
+	})
+	return app
+}
+
+func serveWithToken(app *DoffApp, path, authorization string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthHook_AuthenticatesPrivateRoutes(t *testing.T) {
+	authenticator := &fakeAuthenticator{validToken: "secret"}
+	app := newAuthTestApp(t, authenticator)
+
+	recorder := serveWithToken(app, "/private", "Bearer secret")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "secret", recorder.Body.String())
+
+	// Scheme is case-insensitive
+	recorder = serveWithToken(app, "/private", "bearer secret")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, []string{"secret", "secret"}, authenticator.calls)
+}
+
+func TestAuthHook_RejectsUnauthenticatedRequests(t *testing.T) {
+	authenticator := &fakeAuthenticator{validToken: "secret"}
+	app := newAuthTestApp(t, authenticator)
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"missing header", ""},
+		{"wrong scheme", "Basic secret"},
+		{"invalid token", "Bearer wrong"},
+		{"authenticator error", "Bearer broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveWithToken(app, "/private", tt.authorization)
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			assert.JSONEq(t, `{"error":{"status":401,"code":"unauthorized","message":"Unauthorized"}}`, recorder.Body.String())
+		})
+	}
+}
+
+func TestAuthHook_SkipsPublicAndUnknownRoutes(t *testing.T) {
+	authenticator := &fakeAuthenticator{validToken: "secret"}
+	app := newAuthTestApp(t, authenticator)
+
+	recorder := serveWithToken(app, "/public", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = serveWithToken(app, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	assert.Empty(t, authenticator.calls)
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentTypesOption restricts a route's request bodies to the listed media types,
+// answering 415 otherwise, e.g.
+// RouteConfig{Options: map[string]interface{}{core.ContentTypesOption: []string{"application/json"}}}
+const ContentTypesOption = "contentTypes"
+
+// ErrBodyTooLarge is reported to OnError hooks when a request body exceeds AppOptions.MaxBodySize
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// ErrUnsupportedContentType is reported to OnError hooks when a request body's
+// Content-Type is not allowed by the route's ContentTypesOption
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// bodyLimitHandler caps request bodies at limit bytes. Bodies declaring a larger
+// Content-Length are rejected before the route runs; others are wrapped with
+// http.MaxBytesReader, and a route that hits the limit without answering gets the 413
+func bodyLimitHandler(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, limit)}
+		c.Request.Body = body
+
+		c.Next()
+
+		if body.exceeded && !c.Writer.Written() {
+			abortBodyTooLarge(c, limit)
+		}
+	}
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	AbortWithError(c, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit))
+}
+
+// limitedBody records whether reading hit the http.MaxBytesReader limit
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// routeContentTypes returns the media types allowed by the route's ContentTypesOption
+func routeContentTypes(config RouteConfig) []string {
+	switch types := config.Options[ContentTypesOption].(type) {
+	case []string:
+		return types
+	case string:
+		return []string{types}
+	}
+	return nil
+}
+
+// contentTypeHandler answers 415 to requests with a body whose media type is not
+// one of allowed; requests without a body pass
+func contentTypeHandler(allowed []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		contentType := c.GetHeader("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err == nil {
+			for _, candidate := range allowed {
+				if strings.EqualFold(mediaType, candidate) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		AbortWithError(c, fmt.Errorf("%w: '%s' (allowed: %s)", ErrUnsupportedContentType, contentType, strings.Join(allowed, ", ")))
+	}
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBodyLimitTestApp creates an app capping bodies at limit bytes and records OnError hooks
+func newBodyLimitTestApp(t *testing.T, limit int64) (*DoffApp, *[]error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{Name: "body-limit-test", Mode: gin.TestMode, MaxBodySize: limit}).(*DoffApp)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+	return app, &hookErrs
+}
+
+func TestMaxBodySize_RejectsOversizeBodies(t *testing.T) {
+	app, hookErrs := newBodyLimitTestApp(t, 16)
+	app.GetRouter().POST(RouteConfig{Path: "/echo"}, func(c *gin.Context, container DIContainer) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+	app.GetRouter().POST(RouteConfig{Path: "/orders", SchemaValidator: &createUserSchema{}}, func(c *gin.Context, container DIContainer) {
+		t.Fatal("handler must not run for an oversize body")
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{"within limit", "/echo", "small", false, http.StatusOK},
+		{"declared length", "/echo", strings.Repeat("x", 32), false, http.StatusRequestEntityTooLarge},
+		{"unknown length", "/echo", strings.Repeat("x", 32), true, http.StatusRequestEntityTooLarge},
+		{"schema validation", "/orders", `{"name":"` + strings.Repeat("x", 32) + `"}`, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*hookErrs = nil
+			request := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			if tt.chunked {
+				request.ContentLength = -1
+			}
+
+			recorder := httptest.NewRecorder()
+			app.GetEngine().ServeHTTP(recorder, request)
+			assert.Equal(t, tt.status, recorder.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, recorder.Body.String())
+				assert.Empty(t, *hookErrs)
+				return
+			}
+			assert.Contains(t, recorder.Body.String(), `"code":"payload_too_large"`)
+			require.Len(t, *hookErrs, 1)
+			assert.Equal(t, http.StatusRequestEntityTooLarge, ToAPIError((*hookErrs)[0]).Status)
+		})
+	}
+}
+
+func TestContentTypesOption_RejectsMismatchedContentType(t *testing.T) {
+	app, hookErrs := newBodyLimitTestApp(t, 0)
+	app.GetRouter().POST(RouteConfig{
+		Path:    "/orders",
+		Options: map[string]interface{}{ContentTypesOption: []string{"application/json"}},
+	}, func(c *gin.Context, container DIContainer) {
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(body, contentType string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(`{}`, "application/json; charset=utf-8").Code)
+	assert.Equal(t, http.StatusNoContent, serve("", "").Code, "requests without a body pass")
+	assert.Empty(t, *hookErrs)
+
+	recorder := serve("<order/>", "application/xml")
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"code":"unsupported_media_type"`)
+	require.Len(t, *hookErrs, 1)
+	assert.ErrorIs(t, (*hookErrs)[0], ErrUnsupportedContentType)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, serve(`{}`, "").Code)
+}
@@ -0,0 +1,13 @@
+package core
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP returns the IP of the client behind the request. X-Forwarded-For and
+// X-Real-IP are only believed when the connection comes from one of
+// AppOptions.TrustedProxies, skipping trusted hops; otherwise the connection's peer
+// address is returned, as any client can set those headers
+func ClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveClientIP(t *testing.T, trustedProxies []string) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := CreateDoffApp(&AppOptions{
+		Name:           "client-ip-test",
+		Mode:           gin.TestMode,
+		TrustedProxies: trustedProxies,
+	}).(*DoffApp)
+	require.NoError(t, app.Init())
+	app.GetEngine().GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, ClientIP(c))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	request.RemoteAddr = "10.0.0.7:51000"
+	request.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.9")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder.Body.String()
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	// No proxy is trusted by default, so forwarded headers are ignored
+	assert.Equal(t, "10.0.0.7", serveClientIP(t, nil))
+
+	// The rightmost untrusted hop is the client
+	assert.Equal(t, "203.0.113.9", serveClientIP(t, []string{"10.0.0.0/8"}))
+
+	assert.Equal(t, "10.0.0.7", serveClientIP(t, []string{"192.168.1.1"}))
+}
+
+func TestClientIP_InvalidTrustedProxiesFailStartup(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{
+		Name:           "client-ip-test",
+		Mode:           gin.TestMode,
+		TrustedProxies: []string{"not-an-ip"},
+	}).(*DoffApp)
+
+	err := app.Init()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid trusted proxies")
+}
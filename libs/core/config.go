@@ -1,12 +1,21 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
 )
 
 // ConfigManager manages application configuration
@@ -17,28 +26,86 @@ type ConfigManager interface {
 	GetInt(key string) int
 	GetBool(key string) bool
 	GetFloat(key string) float64
+	// GetStringSlice and GetIntSlice read a JSON/YAML array, or a comma-separated
+	// string such as an environment variable's "a, b"
+	GetStringSlice(key string) []string
+	GetIntSlice(key string) []int
+	// Set overrides a key in code; the value takes precedence over the config file
+	// and environment and is kept when the file is reloaded
 	Set(key string, value interface{})
 	Has(key string) bool
+	// Source returns where a key's value came from, for debugging: the config file
+	// that provided it, "env:<VARIABLE>" for environment overrides, or "" when the
+	// key was Set in code or does not exist
+	Source(key string) string
 	Unmarshal(target interface{}) error
+	// Require fails, listing every key that is not set, e.g. Require("database.host")
+	Require(keys ...string) error
+	// ValidateSchema fails, listing every key that is not set or whose value is not
+	// coercible to its Kind, e.g. ValidateSchema(map[string]Kind{"server.port": KindInt})
+	ValidateSchema(schema map[string]Kind) error
+	// Watch reloads the loaded config file whenever it changes and signals on the
+	// returned channel after each successful reload; the channel closes when ctx is done
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// DefaultEnvPrefix is the prefix of environment variables that override config keys
+// e.g. DOFFY_DATABASE_HOST overrides "database.host"
+const DefaultEnvPrefix = "DOFFY_"
+
+// ConfigOption configures a ConfigManager
+type ConfigOption func(*configManager)
+
+// WithEnvPrefix sets the environment variable prefix used for overrides (default DOFFY_)
+func WithEnvPrefix(prefix string) ConfigOption {
+	return func(cm *configManager) {
+		cm.envPrefix = prefix
+	}
+}
+
+// WithEnvironment selects the overlay file Load merges over the config file, e.g.
+// "staging" overlays config.staging.json on config.json; defaults to the <prefix>ENV
+// environment variable (DOFFY_ENV), and no overlay when both are empty
+func WithEnvironment(environment string) ConfigOption {
+	return func(cm *configManager) {
+		cm.environment = environment
+	}
 }
 
 // configManager implements ConfigManager
 type configManager struct {
-	data map[string]interface{}
+	mu          sync.RWMutex
+	data        map[string]interface{}
+	sources     map[string]string      // Key to the file or environment variable that provided it
+	overrides   map[string]interface{} // Values Set in code, reapplied on every reload
+	path        string                 // Config file loaded by Load, watched by Watch
+	overlay     string                 // Environment overlay of path, optional
+	envPrefix   string
+	environment string
 }
 
 // NewConfigManager creates a new configuration manager
-func NewConfigManager() ConfigManager {
-	return &configManager{
-		data: make(map[string]interface{}),
+func NewConfigManager(opts ...ConfigOption) ConfigManager {
+	cm := &configManager{
+		data:      make(map[string]interface{}),
+		sources:   make(map[string]string),
+		overrides: make(map[string]interface{}),
+		envPrefix: DefaultEnvPrefix,
+	}
+	for _, opt := range opts {
+		opt(cm)
 	}
+	return cm
 }
 
 // Load loads configuration from a file
 func (cm *configManager) Load(configPath string) error {
 	if configPath == "" {
 		// Try to load default config files
-		for _, path := range []string{"config.json", "config/config.json"} {
+		for _, path := range []string{
+			"config.json", "config.yaml", "config.yml",
+			"config/config.json", "config/config.yaml", "config/config.yml",
+		} {
 			if _, err := os.Stat(path); err == nil {
 				configPath = path
 				break
@@ -51,27 +118,250 @@ func (cm *configManager) Load(configPath string) error {
 		return cm.loadFromEnv()
 	}
 
-	// Read config file
+	environment := cm.environment
+	if environment == "" {
+		environment = os.Getenv(cm.envPrefix + "ENV")
+	}
+
+	cm.mu.Lock()
+	cm.path = configPath
+	cm.overlay = overlayPath(configPath, environment)
+	cm.mu.Unlock()
+
+	return cm.reload()
+}
+
+// overlayPath returns the environment's overlay of a config file, e.g.
+// config/config.staging.yaml for config/config.yaml, or "" without an environment
+func overlayPath(configPath, environment string) string {
+	if environment == "" {
+		return ""
+	}
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + environment + ext
+}
+
+// reload re-reads the config file and its overlay and swaps in the new values in one
+// step, so concurrent readers see either the old or the new configuration
+// Overlay values take precedence over the file's, environment variables over both, and
+// values Set in code over everything; a missing overlay is skipped
+func (cm *configManager) reload() error {
+	cm.mu.RLock()
+	configPath, overlay := cm.path, cm.overlay
+	cm.mu.RUnlock()
+
+	values, err := cm.readConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+	sources := make(map[string]string, len(values))
+	for key := range values {
+		sources[key] = configPath
+	}
+
+	if overlay != "" {
+		overlayValues, err := cm.readConfigFile(overlay)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return err
+		default:
+			mergeConfig(values, sources, overlayValues, overlay)
+		}
+	}
+
+	if err := cm.applyEnvOverrides(values, sources); err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	cm.applyOverrides(values, sources)
+	cm.data = values
+	cm.sources = sources
+	cm.mu.Unlock()
+
+	return nil
+}
+
+// readConfigFile reads and flattens a config file
+func (cm *configManager) readConfigFile(configPath string) (map[string]interface{}, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	// An empty file is usually a writer that truncated but has not written yet
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, fmt.Errorf("config file '%s' is empty", configPath)
 	}
 
-	// Parse JSON
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
+	// Parse JSON or YAML depending on the file extension
+	config, err := parseConfigFile(configPath, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", configPath, err)
+	}
+	return cm.flatten(config), nil
+}
+
+// mergeConfig deep-merges the flattened overlay into values: overlay keys replace
+// the same keys, and also any key they shadow, e.g. "cache" set to a scalar drops
+// "cache.ttl", so the result can still be nested for Unmarshal
+func mergeConfig(values map[string]interface{}, sources map[string]string, overlay map[string]interface{}, source string) {
+	for key := range overlay {
+		for existing := range values {
+			if strings.HasPrefix(existing, key+".") || strings.HasPrefix(key, existing+".") {
+				delete(values, existing)
+				delete(sources, existing)
+			}
+		}
+	}
+	for key, value := range overlay {
+		values[key] = value
+		sources[key] = source
+	}
+}
+
+// Watch reloads the config file on change using fsnotify
+// Notifications are coalesced: a reader that falls behind sees a single pending signal
+// and reads the latest values. A change that fails to parse keeps the previous values
+func (cm *configManager) Watch(ctx context.Context) (<-chan struct{}, error) {
+	cm.mu.RLock()
+	configPath, overlay := cm.path, cm.overlay
+	cm.mu.RUnlock()
+
+	if configPath == "" {
+		return nil, fmt.Errorf("no config file loaded to watch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Watch the directory so editors that replace the file (rename + create) are seen
+	if err := watcher.Add(filepath.Dir(configPath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	changes := make(chan struct{}, 1)
+	// The overlay shares the file's directory, so one watch covers both
+	targets := []string{filepath.Clean(configPath)}
+	if overlay != "" {
+		targets = append(targets, filepath.Clean(overlay))
 	}
 
-	// Flatten nested config
-	cm.data = cm.flatten(config)
+	go func() {
+		defer close(changes)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !slices.Contains(targets, filepath.Clean(event.Name)) || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+				if err := cm.reload(); err != nil {
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+// parseConfigFile decodes a config file as YAML (.yaml/.yml) or JSON (anything else)
+func parseConfigFile(configPath string, data []byte) (map[string]interface{}, error) {
+	var config map[string]interface{}
+
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		// Match the value types encoding/json produces so Get* behave the same
+		return normalizeYAML(config).(map[string]interface{}), nil
+	default:
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+}
 
-	// Override with environment variables
-	return cm.loadFromEnv()
+// normalizeYAML converts YAML-decoded values to their JSON equivalents:
+// numbers become float64 and map keys become strings
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalizeYAML(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYAML(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normalizeYAML(item)
+		}
+		return result
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return v
+	}
 }
 
 // loadFromEnv loads configuration from environment variables
 func (cm *configManager) loadFromEnv() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	err := cm.applyEnvOverrides(cm.data, cm.sources)
+	cm.applyOverrides(cm.data, cm.sources)
+	return err
+}
+
+// applyOverrides copies the values Set in code into values; callers hold cm.mu
+func (cm *configManager) applyOverrides(values map[string]interface{}, sources map[string]string) {
+	for key, value := range cm.overrides {
+		values[key] = value
+		delete(sources, key)
+	}
+}
+
+// applyEnvOverrides copies prefixed environment variables into values
+// Values overriding an existing key are converted to that key's type; new keys keep
+// the raw string, so values like "01234" or long numeric IDs are not corrupted
+// Each applied variable is recorded in sources
+func (cm *configManager) applyEnvOverrides(values map[string]interface{}, sources map[string]string) error {
+	var errs []error
+
 	for _, env := range os.Environ() {
 		parts := strings.SplitN(env, "=", 2)
 		if len(parts) != 2 {
@@ -82,15 +372,63 @@ func (cm *configManager) loadFromEnv() error {
 		value := parts[1]
 
 		// Only process environment variables with a specific prefix
-		if strings.HasPrefix(key, "DOFFY_") {
-			configKey := strings.TrimPrefix(key, "DOFFY_")
+		if strings.HasPrefix(key, cm.envPrefix) {
+			configKey := strings.TrimPrefix(key, cm.envPrefix)
 			configKey = strings.ToLower(configKey)
 			configKey = strings.ReplaceAll(configKey, "_", ".")
-			cm.data[configKey] = value
+
+			typed, err := coerceEnvValue(value, values[configKey])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("environment variable '%s' cannot override '%s': %w", key, configKey, err))
+				continue
+			}
+			values[configKey] = typed
+			sources[configKey] = "env:" + key
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// coerceEnvValue converts an environment value to the type of the value it overrides
+func coerceEnvValue(raw string, existing interface{}) (interface{}, error) {
+	switch existing.(type) {
+	case bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("expected a bool, got '%s'", raw)
+		}
+		return value, nil
+	case int:
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got '%s'", raw)
+		}
+		return value, nil
+	case float64:
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got '%s'", raw)
+		}
+		return value, nil
+	case []interface{}:
+		// JSON array, or a comma-separated list of strings
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			var list []interface{}
+			if err := json.Unmarshal([]byte(raw), &list); err != nil {
+				return nil, fmt.Errorf("expected a JSON array: %w", err)
+			}
+			return list, nil
+		}
+		items := strings.Split(raw, ",")
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = strings.TrimSpace(item)
+		}
+		return list, nil
+	default:
+		return raw, nil
+	}
 }
 
 // flatten flattens a nested map
@@ -114,11 +452,16 @@ func (cm *configManager) flatten(m map[string]interface{}) map[string]interface{
 
 // Get returns a configuration value
 func (cm *configManager) Get(key string) interface{} {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.data[key]
 }
 
 // GetString returns a configuration value as string
 func (cm *configManager) GetString(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if value, exists := cm.data[key]; exists {
 		return fmt.Sprintf("%v", value)
 	}
@@ -127,6 +470,9 @@ func (cm *configManager) GetString(key string) string {
 
 // GetInt returns a configuration value as int
 func (cm *configManager) GetInt(key string) int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if value, exists := cm.data[key]; exists {
 		switch v := value.(type) {
 		case int:
@@ -144,6 +490,9 @@ func (cm *configManager) GetInt(key string) int {
 
 // GetBool returns a configuration value as bool
 func (cm *configManager) GetBool(key string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if value, exists := cm.data[key]; exists {
 		switch v := value.(type) {
 		case bool:
@@ -161,6 +510,9 @@ func (cm *configManager) GetBool(key string) bool {
 
 // GetFloat returns a configuration value as float64
 func (cm *configManager) GetFloat(key string) float64 {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if value, exists := cm.data[key]; exists {
 		switch v := value.(type) {
 		case float64:
@@ -176,21 +528,118 @@ func (cm *configManager) GetFloat(key string) float64 {
 	return 0
 }
 
-// Set sets a configuration value
+// GetStringSlice returns a configuration value as a string slice: the items of an
+// array, or the trimmed, non-empty items of a comma-separated string
+func (cm *configManager) GetStringSlice(key string) []string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	items := configListItems(cm.data[key])
+	if items == nil {
+		return nil
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, fmt.Sprintf("%v", item))
+	}
+	return result
+}
+
+// GetIntSlice returns a configuration value as an int slice, read like
+// GetStringSlice; items that are not integers are skipped
+func (cm *configManager) GetIntSlice(key string) []int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	items := configListItems(cm.data[key])
+	if items == nil {
+		return nil
+	}
+	result := make([]int, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case int:
+			result = append(result, v)
+		case float64:
+			if v == float64(int(v)) {
+				result = append(result, int(v))
+			}
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				result = append(result, i)
+			}
+		}
+	}
+	return result
+}
+
+// configListItems returns the items of a list value; a string is parsed as a JSON
+// array when it looks like one, otherwise split on commas, and any other scalar is
+// a single item
+func configListItems(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items
+	case []int:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items
+	case string:
+		var items []interface{}
+		if strings.HasPrefix(strings.TrimSpace(v), "[") && json.Unmarshal([]byte(v), &items) == nil {
+			return items
+		}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	default:
+		return []interface{}{v}
+	}
+}
+
+// Set sets a configuration value and remembers it for later reloads
 func (cm *configManager) Set(key string, value interface{}) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	cm.data[key] = value
+	cm.overrides[key] = value
+	delete(cm.sources, key)
 }
 
 // Has checks if a configuration key exists
 func (cm *configManager) Has(key string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	_, exists := cm.data[key]
 	return exists
 }
 
+// Source returns the file or environment variable that provided a key's value
+func (cm *configManager) Source(key string) string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.sources[key]
+}
+
 // Unmarshal unmarshals the configuration into a struct
 func (cm *configManager) Unmarshal(target interface{}) error {
 	// Convert flat map to nested map
+	cm.mu.RLock()
 	nested := cm.nest(cm.data)
+	cm.mu.RUnlock()
 
 	data, err := json.Marshal(nested)
 	if err != nil {
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Kind is the type a config value must be coercible to, see ValidateSchema
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+	KindBool   Kind = "bool"
+	KindFloat  Kind = "float"
+)
+
+// Config validation errors
+var (
+	// ErrConfigKeyMissing is returned for a required config key that is not set
+	ErrConfigKeyMissing = newError("required config key is missing")
+	// ErrConfigKeyType is returned for a config value the typed getter of its Kind
+	// cannot read, which would otherwise silently return the zero value
+	ErrConfigKeyType = newError("config key has the wrong type")
+)
+
+// Require reports every key that is not set, joined
+func (cm *configManager) Require(keys ...string) error {
+	schema := make(map[string]Kind, len(keys))
+	for _, key := range keys {
+		schema[key] = ""
+	}
+	return cm.ValidateSchema(schema)
+}
+
+// ValidateSchema reports every key of schema that is not set, or whose value is not
+// coercible to its Kind the way the typed getters read it (e.g. "8080" is an int, "yes"
+// is not a bool), joined in key order; an empty Kind only requires the key
+func (cm *configManager) ValidateSchema(schema map[string]Kind) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var errs []error
+	for _, key := range keys {
+		value, exists := cm.data[key]
+		if !exists {
+			errs = append(errs, fmt.Errorf("%w: '%s'", ErrConfigKeyMissing, key))
+			continue
+		}
+		kind := schema[key]
+		if kind == "" {
+			continue
+		}
+		ok, err := coercible(value, kind)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config key '%s': %w", key, err))
+		} else if !ok {
+			errs = append(errs, fmt.Errorf("%w: '%s' is %#v, not %s", ErrConfigKeyType, key, value, kind))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// coercible reports whether value reads as kind
+func coercible(value interface{}, kind Kind) (bool, error) {
+	switch kind {
+	case KindString:
+		switch value.(type) {
+		case string, bool, int, float64:
+			return true, nil
+		}
+		return false, nil
+	case KindInt:
+		switch v := value.(type) {
+		case int:
+			return true, nil
+		case float64:
+			return v == math.Trunc(v), nil
+		case string:
+			_, err := strconv.Atoi(v)
+			return err == nil, nil
+		}
+		return false, nil
+	case KindBool:
+		switch v := value.(type) {
+		case bool:
+			return true, nil
+		case string:
+			_, err := strconv.ParseBool(v)
+			return err == nil, nil
+		}
+		return false, nil
+	case KindFloat:
+		switch v := value.(type) {
+		case float64, int:
+			return true, nil
+		case string:
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil, nil
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown kind '%s'", kind)
+	}
+}
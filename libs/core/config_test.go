@@ -0,0 +1,472 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a config file into a temporary directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+const yamlConfig = `
+app:
+  name: doffy
+  debug: true
+database:
+  host: localhost
+  port: 5432
+  timeout: 2.5
+  replicas:
+    - db-1
+    - db-2
+`
+
+const jsonConfig = `{
+  "app": {"name": "doffy", "debug": true},
+  "database": {"host": "localhost", "port": 5432, "timeout": 2.5, "replicas": ["db-1", "db-2"]}
+}`
+
+type testDatabaseConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Timeout  float64  `json:"timeout"`
+	Replicas []string `json:"replicas"`
+}
+
+type testAppConfig struct {
+	App struct {
+		Name  string `json:"name"`
+		Debug bool   `json:"debug"`
+	} `json:"app"`
+	Database testDatabaseConfig `json:"database"`
+}
+
+func TestConfigManager_LoadYAML(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.yml"} {
+		t.Run(name, func(t *testing.T) {
+			cm := NewConfigManager()
+			require.NoError(t, cm.Load(writeConfigFile(t, name, yamlConfig)))
+
+			assert.Equal(t, "localhost", cm.GetString("database.host"))
+			assert.Equal(t, 5432, cm.GetInt("database.port"))
+			assert.Equal(t, 2.5, cm.GetFloat("database.timeout"))
+			assert.True(t, cm.GetBool("app.debug"))
+			assert.Equal(t, "doffy", cm.GetString("app.name"))
+			assert.False(t, cm.Has("database"))
+		})
+	}
+}
+
+func TestConfigManager_YAMLMatchesJSON(t *testing.T) {
+	yamlManager := NewConfigManager()
+	require.NoError(t, yamlManager.Load(writeConfigFile(t, "config.yaml", yamlConfig)))
+
+	jsonManager := NewConfigManager()
+	require.NoError(t, jsonManager.Load(writeConfigFile(t, "config.json", jsonConfig)))
+
+	for _, key := range []string{"app.name", "app.debug", "database.host", "database.port", "database.timeout", "database.replicas"} {
+		assert.Equal(t, jsonManager.Get(key), yamlManager.Get(key), "key %s", key)
+	}
+
+	var fromYAML, fromJSON testAppConfig
+	require.NoError(t, yamlManager.Unmarshal(&fromYAML))
+	require.NoError(t, jsonManager.Unmarshal(&fromJSON))
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.Equal(t, []string{"db-1", "db-2"}, fromYAML.Database.Replicas)
+}
+
+func TestConfigManager_YAMLEnvOverride(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_HOST", "db.internal")
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.yml", yamlConfig)))
+
+	assert.Equal(t, "db.internal", cm.GetString("database.host"))
+	assert.Equal(t, 5432, cm.GetInt("database.port"))
+}
+
+func TestConfigManager_InvalidYAML(t *testing.T) {
+	cm := NewConfigManager()
+	err := cm.Load(writeConfigFile(t, "config.yaml", "database: [unclosed"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse config file")
+}
+
+// replaceConfigFile atomically replaces a config file the way editors and deploy tools do
+func replaceConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o644))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// waitForReload waits for a reload notification from Watch
+func waitForReload(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case _, ok := <-changes:
+		require.True(t, ok, "changes channel closed unexpectedly")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+	}
+}
+
+func TestConfigManager_WatchReloadsOnChange(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_USER", "service")
+
+	path := writeConfigFile(t, "config.yaml", "database:\n  host: localhost\n  port: 5432\n")
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := cm.Watch(ctx)
+	require.NoError(t, err)
+
+	// Concurrent readers must always see a complete configuration
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					assert.True(t, cm.Has("database.host"))
+					assert.Equal(t, "service", cm.GetString("database.user"))
+				}
+			}
+		}()
+	}
+
+	replaceConfigFile(t, path, "database:\n  host: db.internal\n  port: 6543\n")
+	waitForReload(t, changes)
+
+	close(stop)
+	wg.Wait()
+
+	assert.Equal(t, "db.internal", cm.GetString("database.host"))
+	assert.Equal(t, 6543, cm.GetInt("database.port"))
+	// Environment overrides are re-applied after each reload
+	assert.Equal(t, "service", cm.GetString("database.user"))
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		for ok {
+			_, ok = <-changes
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("changes channel was not closed after cancel")
+	}
+}
+
+func TestConfigManager_WatchKeepsValuesOnInvalidFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"feature": {"enabled": true}}`)
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := cm.Watch(ctx)
+	require.NoError(t, err)
+
+	replaceConfigFile(t, path, `{"feature": `)
+	replaceConfigFile(t, path, `{"feature": {"enabled": false}}`)
+	waitForReload(t, changes)
+
+	assert.False(t, cm.GetBool("feature.enabled"))
+}
+
+func TestConfigManager_WatchReloadsInPlaceWrites(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"feature": {"enabled": true}}`)
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := cm.Watch(ctx)
+	require.NoError(t, err)
+
+	// os.WriteFile truncates first; the empty intermediate state must not be applied
+	require.NoError(t, os.WriteFile(path, []byte(`{"feature": {"enabled": false}}`), 0o644))
+	waitForReload(t, changes)
+
+	assert.True(t, cm.Has("feature.enabled"))
+	assert.False(t, cm.GetBool("feature.enabled"))
+}
+
+func TestConfigManager_WatchKeepsSetValues(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_USER", "service")
+
+	path := writeConfigFile(t, "config.yaml", "database:\n  host: localhost\n  port: 5432\n")
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(path))
+	cm.Set("database.port", 7000)
+	cm.Set("database.user", "admin")
+	cm.Set("feature.enabled", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := cm.Watch(ctx)
+	require.NoError(t, err)
+
+	replaceConfigFile(t, path, "database:\n  host: db.internal\n  port: 6543\n")
+	waitForReload(t, changes)
+
+	assert.Equal(t, "db.internal", cm.GetString("database.host"))
+	// Values Set in code win over the reloaded file and the environment
+	assert.Equal(t, 7000, cm.GetInt("database.port"))
+	assert.Equal(t, "admin", cm.GetString("database.user"))
+	assert.True(t, cm.GetBool("feature.enabled"))
+	assert.Empty(t, cm.Source("database.port"))
+}
+
+func TestConfigManager_WatchWithoutFile(t *testing.T) {
+	cm := NewConfigManager()
+	_, err := cm.Watch(context.Background())
+	assert.EqualError(t, err, "no config file loaded to watch")
+}
+
+func TestConfigManager_NewEnvKeysKeepRawStrings(t *testing.T) {
+	t.Setenv("DOFFY_FEATURE_ENABLED", "true")
+	t.Setenv("DOFFY_WORKERS", "8")
+	t.Setenv("DOFFY_RATIO", "0.75")
+	t.Setenv("DOFFY_BRANCH_CODE", "01234")
+	t.Setenv("DOFFY_ACCOUNT_ID", "1234567890123456789012")
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(""))
+
+	// Keys without a typed value to match are not guessed at
+	assert.Equal(t, "true", cm.Get("feature.enabled"))
+	assert.Equal(t, "8", cm.Get("workers"))
+	assert.Equal(t, "01234", cm.Get("branch.code"))
+	assert.Equal(t, "1234567890123456789012", cm.Get("account.id"))
+
+	// The typed getters still parse them
+	assert.True(t, cm.GetBool("feature.enabled"))
+	assert.Equal(t, 8, cm.GetInt("workers"))
+	assert.Equal(t, 0.75, cm.GetFloat("ratio"))
+}
+
+func TestConfigManager_EnvOverridesKeepFileTypes(t *testing.T) {
+	t.Setenv("DOFFY_APP_DEBUG", "false")
+	t.Setenv("DOFFY_DATABASE_PORT", "6543")
+	t.Setenv("DOFFY_DATABASE_HOST", "10")
+	t.Setenv("DOFFY_DATABASE_REPLICAS", "db-3, db-4")
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.json", jsonConfig)))
+
+	assert.Equal(t, false, cm.Get("app.debug"))
+	assert.Equal(t, float64(6543), cm.Get("database.port"))
+	// A string key stays a string even when the override looks numeric
+	assert.Equal(t, "10", cm.Get("database.host"))
+
+	var config testAppConfig
+	require.NoError(t, cm.Unmarshal(&config))
+	assert.Equal(t, 6543, config.Database.Port)
+	assert.Equal(t, []string{"db-3", "db-4"}, config.Database.Replicas)
+}
+
+func TestConfigManager_EnvOverrideTypeMismatch(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_PORT", "not-a-port")
+
+	cm := NewConfigManager()
+	err := cm.Load(writeConfigFile(t, "config.json", jsonConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "environment variable 'DOFFY_DATABASE_PORT' cannot override 'database.port'")
+}
+
+func TestConfigManager_CustomEnvPrefix(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_HOST", "ignored")
+	t.Setenv("ACME_DATABASE_HOST", "acme-db")
+
+	cm := NewConfigManager(WithEnvPrefix("ACME_"))
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.yaml", yamlConfig)))
+
+	assert.Equal(t, "acme-db", cm.GetString("database.host"))
+}
+
+func TestConfigManager_SlicesFromFile(t *testing.T) {
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.json", `{
+  "cors": {"origins": ["https://a.example", "https://b.example"]},
+  "server": {"ports": [8080, 8081]}
+}`)))
+
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cm.GetStringSlice("cors.origins"))
+	assert.Equal(t, []int{8080, 8081}, cm.GetIntSlice("server.ports"))
+	assert.Equal(t, []string{"8080", "8081"}, cm.GetStringSlice("server.ports"))
+	assert.Nil(t, cm.GetStringSlice("missing"))
+	assert.Nil(t, cm.GetIntSlice("missing"))
+}
+
+func TestConfigManager_SlicesFromEnv(t *testing.T) {
+	t.Setenv("DOFFY_CORS_ORIGINS", "https://a.example, https://b.example,")
+	t.Setenv("DOFFY_SERVER_PORTS", "8080,8081, nope")
+	t.Setenv("DOFFY_SERVER_PORT", "9090")
+	t.Setenv("DOFFY_SERVER_HOSTS", `["a.internal", "b.internal"]`)
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(""))
+
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cm.GetStringSlice("cors.origins"))
+	assert.Equal(t, []int{8080, 8081}, cm.GetIntSlice("server.ports"))
+	// A single value is a one-item list
+	assert.Equal(t, []int{9090}, cm.GetIntSlice("server.port"))
+	assert.Equal(t, []string{"a.internal", "b.internal"}, cm.GetStringSlice("server.hosts"))
+}
+
+func TestConfigManager_EnvironmentOverlay(t *testing.T) {
+	t.Setenv("DOFFY_DATABASE_USER", "service")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	overlay := filepath.Join(dir, "config.staging.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "database": {"host": "localhost", "port": 5432, "user": "dev", "replicas": ["db-1", "db-2"]},
+  "cache": {"ttl": 60}
+}`), 0o644))
+	require.NoError(t, os.WriteFile(overlay, []byte(`{
+  "database": {"host": "db.staging", "replicas": ["db-3"]},
+  "cache": "disabled"
+}`), 0o644))
+
+	cm := NewConfigManager(WithEnvironment("staging"))
+	require.NoError(t, cm.Load(path))
+
+	// The overlay wins over the base file, environment variables over both
+	assert.Equal(t, "db.staging", cm.GetString("database.host"))
+	assert.Equal(t, 5432, cm.GetInt("database.port"))
+	assert.Equal(t, "service", cm.GetString("database.user"))
+	assert.Equal(t, []string{"db-3"}, cm.GetStringSlice("database.replicas"))
+	// A scalar in the overlay replaces the base's nested keys
+	assert.Equal(t, "disabled", cm.GetString("cache"))
+	assert.False(t, cm.Has("cache.ttl"))
+
+	assert.Equal(t, overlay, cm.Source("database.host"))
+	assert.Equal(t, path, cm.Source("database.port"))
+	assert.Equal(t, "env:DOFFY_DATABASE_USER", cm.Source("database.user"))
+	assert.Empty(t, cm.Source("missing"))
+
+	var config testAppConfig
+	require.NoError(t, cm.Unmarshal(&config))
+	assert.Equal(t, "db.staging", config.Database.Host)
+}
+
+func TestConfigManager_EnvironmentFromEnvVariable(t *testing.T) {
+	t.Setenv("DOFFY_ENV", "prod")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("app:\n  debug: true\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.yaml"), []byte("app:\n  debug: false\n"), 0o644))
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(path))
+	assert.False(t, cm.GetBool("app.debug"))
+}
+
+func TestConfigManager_MissingOverlayIsNotFatal(t *testing.T) {
+	path := writeConfigFile(t, "config.json", jsonConfig)
+
+	cm := NewConfigManager(WithEnvironment("staging"))
+	require.NoError(t, cm.Load(path))
+	assert.Equal(t, "localhost", cm.GetString("database.host"))
+	assert.Equal(t, path, cm.Source("database.host"))
+
+	// An overlay that exists but is invalid is still an error
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "config.staging.json"), []byte(`{"database": `), 0o644))
+	err := cm.Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config.staging.json")
+}
+
+func TestConfigManager_RequireReportsMissingKeys(t *testing.T) {
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.json", jsonConfig)))
+
+	assert.NoError(t, cm.Require("app.name", "database.host", "database.replicas"))
+
+	err := cm.Require("database.host", "database.user", "cache.url")
+	require.ErrorIs(t, err, ErrConfigKeyMissing)
+	assert.Contains(t, err.Error(), "'database.user'")
+	assert.Contains(t, err.Error(), "'cache.url'")
+	assert.NotContains(t, err.Error(), "'database.host'")
+}
+
+func TestConfigManager_ValidateSchemaReportsEveryProblem(t *testing.T) {
+	t.Setenv("DOFFY_WORKERS", "8")
+	t.Setenv("DOFFY_FEATURE_ENABLED", "yes")
+
+	cm := NewConfigManager()
+	require.NoError(t, cm.Load(writeConfigFile(t, "config.json", jsonConfig)))
+	cm.Set("server.port", "8080")
+
+	assert.NoError(t, cm.ValidateSchema(map[string]Kind{
+		"app.name":         KindString,
+		"app.debug":        KindBool,
+		"database.port":    KindInt,
+		"database.timeout": KindFloat,
+		"server.port":      KindInt, // Strings are coerced like GetInt does
+		"workers":          KindInt,
+		"database.host":    "",
+	}))
+
+	err := cm.ValidateSchema(map[string]Kind{
+		"database.timeout":  KindInt,
+		"database.replicas": KindString,
+		"feature.enabled":   KindBool,
+		"app.name":          KindFloat,
+		"cache.url":         KindString,
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrConfigKeyType)
+	assert.ErrorIs(t, err, ErrConfigKeyMissing)
+	// One line per problem, in key order
+	assert.Equal(t, []string{
+		`config key has the wrong type: 'app.name' is "doffy", not float`,
+		`required config key is missing: 'cache.url'`,
+		`config key has the wrong type: 'database.replicas' is []interface {}{"db-1", "db-2"}, not string`,
+		`config key has the wrong type: 'database.timeout' is 2.5, not int`,
+		`config key has the wrong type: 'feature.enabled' is "yes", not bool`,
+	}, strings.Split(err.Error(), "\n"))
+
+	assert.ErrorContains(t, cm.ValidateSchema(map[string]Kind{"app.name": "date"}), "config key 'app.name': unknown kind 'date'")
+}
+
+func TestCreateDoffApp_FailsFastOnInvalidConfig(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{
+		Name:           "config-test",
+		Mode:           gin.TestMode,
+		ConfigPath:     writeConfigFile(t, "config.json", jsonConfig),
+		RequiredConfig: []string{"database.user"},
+		ConfigSchema:   map[string]Kind{"database.port": KindInt, "app.name": KindBool},
+	}).(*DoffApp)
+
+	err := app.Init()
+	require.ErrorIs(t, err, ErrConfigKeyMissing)
+	assert.ErrorIs(t, err, ErrConfigKeyType)
+	assert.Contains(t, err.Error(), "invalid configuration")
+	assert.Contains(t, err.Error(), "'database.user'")
+	assert.Contains(t, err.Error(), "'app.name'")
+	assert.NotContains(t, err.Error(), "'database.port'")
+}
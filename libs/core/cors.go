@@ -1,6 +1,11 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -9,6 +14,10 @@ import (
 
 // CorsOptions defines CORS configuration
 type CorsOptions struct {
+	// AllowOrigins entries are matched against the request's Origin header:
+	// "*" (any origin, not allowed with AllowCredentials), exact origins,
+	// subdomain wildcards like "https://*.example.com", or regular expressions
+	// starting with "^" like "^https://pr-[0-9]+\.preview\.example\.com$"
 	AllowOrigins     []string
 	AllowMethods     []string
 	AllowHeaders     []string
@@ -41,6 +50,10 @@ func (p *CorsPlugin) Version() string {
 
 // Register registers the CORS service with the DI container
 func (p *CorsPlugin) Register(container DIContainer) error {
+	if err := NewCorsService(p.options).Validate(); err != nil {
+		return err
+	}
+
 	return container.RegisterSingleton("corsService", func(c DIContainer) (interface{}, error) {
 		return NewCorsService(p.options), nil
 	})
@@ -55,7 +68,48 @@ func (p *CorsPlugin) Hooks() []LifecycleHook {
 
 // CorsService provides CORS functionality
 type CorsService struct {
-	options *CorsOptions
+	options  *CorsOptions
+	matchers []originMatcher
+	errs     []error // Invalid AllowOrigins entries, reported by Validate
+}
+
+// originMatcher matches a request Origin against one AllowOrigins entry
+type originMatcher func(origin string) bool
+
+// compileOriginMatcher builds the matcher for an AllowOrigins entry
+func compileOriginMatcher(pattern string, allowCredentials bool) (originMatcher, error) {
+	switch {
+	case pattern == "*":
+		if allowCredentials {
+			return nil, fmt.Errorf("cors origin '*' cannot be used with AllowCredentials")
+		}
+		return func(origin string) bool { return true }, nil
+
+	case strings.HasPrefix(pattern, "^"):
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("cors origin pattern '%s' is invalid: %w", pattern, err)
+		}
+		return re.MatchString, nil
+
+	case strings.Contains(pattern, "://*."):
+		// Subdomain wildcard: "https://*.example.com" matches "https://api.example.com"
+		// (and deeper subdomains) but not "https://example.com" itself
+		scheme, host, _ := strings.Cut(strings.ToLower(pattern), "://*.")
+		prefix := scheme + "://"
+		suffix := "." + host
+		return func(origin string) bool {
+			origin = strings.ToLower(origin)
+			if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+				return false
+			}
+			subdomain := strings.TrimSuffix(strings.TrimPrefix(origin, prefix), suffix)
+			return subdomain != "" && !strings.ContainsAny(subdomain, "/:@")
+		}, nil
+
+	default:
+		return func(origin string) bool { return strings.EqualFold(origin, pattern) }, nil
+	}
 }
 
 // NewCorsService creates a new CORS service
@@ -119,15 +173,83 @@ func NewCorsService(options interface{}) *CorsService {
 		}
 	}
 
-	return &CorsService{
+	service := &CorsService{
 		options: defaultOptions,
 	}
+	for _, pattern := range defaultOptions.AllowOrigins {
+		matcher, err := compileOriginMatcher(pattern, defaultOptions.AllowCredentials)
+		if err != nil {
+			// Invalid entries never match; Validate reports them
+			service.errs = append(service.errs, err)
+			continue
+		}
+		service.matchers = append(service.matchers, matcher)
+	}
+
+	return service
+}
+
+// Validate reports AllowOrigins entries that were rejected
+func (s *CorsService) Validate() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid cors options: %w", errors.Join(s.errs...))
+}
+
+// IsOriginAllowed reports whether the origin matches any AllowOrigins entry
+func (s *CorsService) IsOriginAllowed(origin string) bool {
+	for _, matches := range s.matchers {
+		if matches(origin) {
+			return true
+		}
+	}
+	return false
 }
 
 // Handle handles the CORS middleware
+// A matching Origin is echoed back as Access-Control-Allow-Origin; requests from other
+// origins get no CORS headers, and their preflight requests are rejected with 403
+// Within an app, preflight requests answer with the AllowMethods registered for the
+// requested path, or 404 when no route matches it
 func (s *CorsService) Handle(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", strings.Join(s.options.AllowOrigins, ","))
-	c.Header("Access-Control-Allow-Methods", strings.Join(s.options.AllowMethods, ","))
+	if s.apply(c) {
+		c.Next()
+	}
+}
+
+// apply sets the CORS headers of Handle and aborts preflight and rejected preflight
+// requests, reporting whether the request goes on to its handler
+// It does not call c.Next, so that CorsHook runs before the later OnRequest hooks
+func (s *CorsService) apply(c *gin.Context) bool {
+	// Responses differ per Origin, so caches must key on it
+	c.Writer.Header().Add("Vary", "Origin")
+
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		// Not a cross-origin request
+		return true
+	}
+
+	if !s.IsOriginAllowed(origin) {
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.AbortWithStatus(http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+
+	allowMethods := s.options.AllowMethods
+	if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+		var routed bool
+		if allowMethods, routed = s.preflightMethods(c); !routed {
+			c.AbortWithStatus(http.StatusNotFound)
+			return false
+		}
+	}
+
+	c.Header("Access-Control-Allow-Origin", origin)
+	c.Header("Access-Control-Allow-Methods", strings.Join(allowMethods, ","))
 	c.Header("Access-Control-Allow-Headers", strings.Join(s.options.AllowHeaders, ","))
 	c.Header("Access-Control-Expose-Headers", strings.Join(s.options.ExposeHeaders, ","))
 	if s.options.AllowCredentials {
@@ -137,10 +259,37 @@ func (s *CorsService) Handle(c *gin.Context) {
 
 	if c.Request.Method == "OPTIONS" {
 		c.AbortWithStatus(204)
-		return
+		return false
 	}
 
-	c.Next()
+	return true
+}
+
+// preflightMethods returns the AllowMethods registered for the request path by the
+// app's routes, reporting false when no route matches it; outside an app every
+// configured method is returned
+func (s *CorsService) preflightMethods(c *gin.Context) ([]string, bool) {
+	app, exists := c.Get("app")
+	if !exists {
+		return s.options.AllowMethods, true
+	}
+	doffApp, ok := app.(*DoffApp)
+	if !ok || doffApp.server == nil {
+		return s.options.AllowMethods, true
+	}
+
+	registered := doffApp.AllowedMethods(c.Request.URL.Path)
+	if len(registered) == 0 {
+		return nil, false
+	}
+
+	var methods []string
+	for _, method := range s.options.AllowMethods {
+		if slices.Contains(registered, strings.ToUpper(method)) {
+			methods = append(methods, method)
+		}
+	}
+	return methods, true
 }
 
 // CorsHook implements the LifecycleHook interface for CORS
@@ -152,18 +301,17 @@ func NewCorsHook() *CorsHook {
 }
 
 // OnRequest implements the LifecycleHook interface
+// It only sets headers and aborts preflight requests; the app runs the later hooks
+// and the handler
 func (h *CorsHook) OnRequest(c *gin.Context) {
 	// Get CORS service from container
 	corsService, err := c.MustGet("container").(DIContainer).Resolve("corsService")
 	if err != nil {
-		c.Next()
 		return
 	}
 
 	if service, ok := corsService.(*CorsService); ok {
-		service.Handle(c)
-	} else {
-		c.Next()
+		service.apply(c)
 	}
 }
 
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveCors runs a request with the given Origin through the CORS service
+func serveCors(t *testing.T, options *CorsOptions, method, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	service := NewCorsService(options)
+	engine := gin.New()
+	engine.Use(service.Handle)
+	engine.Any("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := httptest.NewRequest(method, "/resource", nil)
+	if origin != "" {
+		request.Header.Set("Origin", origin)
+	}
+	if method == http.MethodOptions {
+		request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	}
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCorsService_OriginMatching(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allowed bool
+	}{
+		{"exact match", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"exact match is case-insensitive", []string{"https://App.Example.com"}, "https://app.example.com", true},
+		{"exact mismatch", []string{"https://app.example.com"}, "https://evil.com", false},
+		{"wildcard any", []string{"*"}, "https://anything.dev", true},
+		{"subdomain wildcard", []string{"https://*.example.com"}, "https://api.example.com", true},
+		{"nested subdomain wildcard", []string{"https://*.example.com"}, "https://eu.api.example.com", true},
+		{"subdomain wildcard excludes apex", []string{"https://*.example.com"}, "https://example.com", false},
+		{"subdomain wildcard checks scheme", []string{"https://*.example.com"}, "http://api.example.com", false},
+		{"subdomain wildcard rejects suffix trick", []string{"https://*.example.com"}, "https://api.example.com.evil.com", false},
+		{"regex match", []string{`^https://pr-[0-9]+\.preview\.example\.com$`}, "https://pr-42.preview.example.com", true},
+		{"regex mismatch", []string{`^https://pr-[0-9]+\.preview\.example\.com$`}, "https://pr-x.preview.example.com", false},
+		{"second entry matches", []string{"https://a.com", "https://b.com"}, "https://b.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveCors(t, &CorsOptions{AllowOrigins: tt.origins}, http.MethodGet, tt.origin)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Contains(t, recorder.Header().Values("Vary"), "Origin")
+			if tt.allowed {
+				assert.Equal(t, tt.origin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			} else {
+				assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
+
+func TestCorsService_Preflight(t *testing.T) {
+	options := &CorsOptions{AllowOrigins: []string{"https://*.example.com"}}
+
+	recorder := serveCors(t, options, http.MethodOptions, "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	recorder = serveCors(t, options, http.MethodOptions, "https://evil.com")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCorsService_NoOriginHeader(t *testing.T) {
+	recorder := serveCors(t, &CorsOptions{AllowOrigins: []string{"*"}}, http.MethodGet, "")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCorsService_CredentialsRejectWildcard(t *testing.T) {
+	options := &CorsOptions{
+		AllowOrigins:     []string{"*", "https://app.example.com"},
+		AllowCredentials: true,
+	}
+
+	service := NewCorsService(options)
+	err := service.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cors origin '*' cannot be used with AllowCredentials")
+
+	// The wildcard never matches; explicitly listed origins still do
+	recorder := serveCors(t, options, http.MethodGet, "https://other.dev")
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	recorder = serveCors(t, options, http.MethodGet, "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+
+	// Registering the plugin fails fast on the invalid configuration
+	assert.Error(t, NewCorsPlugin(options).Register(NewDIContainer()))
+}
+
+func TestCorsService_InvalidRegex(t *testing.T) {
+	service := NewCorsService(&CorsOptions{AllowOrigins: []string{"^https://(unclosed"}})
+
+	err := service.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cors origin pattern '^https://(unclosed' is invalid")
+	assert.False(t, service.IsOriginAllowed("https://unclosed"))
+}
+
+func TestCorsService_PreflightReflectsRegisteredMethods(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	require.NoError(t, app.RegisterPlugin(NewCorsPlugin(&CorsOptions{AllowOrigins: []string{"https://app.example.com"}})))
+
+	handler := func(c *gin.Context, container DIContainer) { c.Status(http.StatusOK) }
+	app.GetRouter().POST(RouteConfig{Path: "/items/:id"}, handler)
+	app.GetRouter().GET(RouteConfig{Path: "/items/:id"}, handler)
+	app.GetEngine().DELETE("/legacy/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	preflight := func(path string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodOptions, path, nil)
+		request.Header.Set("Origin", "https://app.example.com")
+		request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := preflight("/items/42")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "GET,POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+
+	recorder = preflight("/legacy/a/b")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "DELETE", recorder.Header().Get("Access-Control-Allow-Methods"))
+
+	assert.Equal(t, http.StatusNotFound, preflight("/items").Code)
+	assert.Equal(t, http.StatusNotFound, preflight("/missing").Code)
+}
+
+func TestCorsHook_RunsBeforeLaterHooks(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	require.NoError(t, app.RegisterPlugin(NewCorsPlugin(&CorsOptions{AllowOrigins: []string{"https://app.example.com"}})))
+
+	var order []string
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnRequestHook(func(c *gin.Context) {
+		order = append(order, "later-hook")
+	}))
+	app.GetRouter().GET(RouteConfig{Path: "/items"}, func(c *gin.Context, container DIContainer) {
+		order = append(order, "handler")
+		c.Status(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/items", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, []string{"later-hook", "handler"}, order)
+}
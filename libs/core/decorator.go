@@ -2,13 +2,20 @@ package core
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
+
+	"github.com/gin-gonic/gin"
 )
 
+// RequestDecoratorFactory computes a request decorator's default value for each request
+// Factories are called concurrently from request goroutines and must be safe for that
+type RequestDecoratorFactory func(c *gin.Context) interface{}
+
 // DecoratorManager manages application-level decorators
 type DecoratorManager struct {
 	instanceDecorators map[string]interface{}
-	requestDecorators  map[string]interface{}  // Default values
+	requestDecorators  map[string]interface{}  // Default values or RequestDecoratorFactory
 	replyDecorators    map[string]interface{}
 	mu                 sync.RWMutex
 }
@@ -48,6 +55,15 @@ func (dm *DecoratorManager) DecorateRequest(name string, defaultValue interface{
 	return nil
 }
 
+// DecorateRequestFactory registers a request-scoped decorator whose default value is
+// computed per request (e.g. a fresh correlation ID) instead of shared by all requests
+func (dm *DecoratorManager) DecorateRequestFactory(name string, factory RequestDecoratorFactory) error {
+	if factory == nil {
+		return fmt.Errorf("request decorator '%s' factory cannot be nil", name)
+	}
+	return dm.DecorateRequest(name, factory)
+}
+
 // DecorateReply registers a reply helper function
 func (dm *DecoratorManager) DecorateReply(name string, fn interface{}) error {
 	dm.mu.Lock()
@@ -61,6 +77,60 @@ func (dm *DecoratorManager) DecorateReply(name string, fn interface{}) error {
 	return nil
 }
 
+// OverrideDecorate registers or replaces an instance-level decorator
+func (dm *DecoratorManager) OverrideDecorate(name string, value interface{}) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.instanceDecorators[name] = value
+}
+
+// OverrideDecorateRequest registers or replaces a request-scoped decorator default value
+// The value may be a RequestDecoratorFactory
+func (dm *DecoratorManager) OverrideDecorateRequest(name string, defaultValue interface{}) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.requestDecorators[name] = defaultValue
+}
+
+// OverrideDecorateReply registers or replaces a reply helper function
+func (dm *DecoratorManager) OverrideDecorateReply(name string, fn interface{}) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.replyDecorators[name] = fn
+}
+
+// RegisterReplyHelper registers a reply helper whose signature is checked at compile time
+// Call it with CallReplyHelper using the same In and Out types
+func RegisterReplyHelper[In, Out any](dm *DecoratorManager, name string, fn func(In) Out) error {
+	if fn == nil {
+		return fmt.Errorf("reply helper '%s' cannot be nil", name)
+	}
+	return dm.DecorateReply(name, fn)
+}
+
+// CallReplyHelper calls the request's reply helper with in
+// Returns the zero value of Out and a descriptive error when the helper is missing
+// or was registered with a different signature
+func CallReplyHelper[In, Out any](rc *RequestContainer, name string, in In) (Out, error) {
+	var zero Out
+
+	if rc == nil {
+		return zero, fmt.Errorf("cannot call reply helper '%s': request container is nil", name)
+	}
+
+	helper, exists := rc.GetReplyHelper(name)
+	if !exists {
+		return zero, fmt.Errorf("reply helper '%s' is not registered", name)
+	}
+
+	fn, ok := helper.(func(In) Out)
+	if !ok {
+		return zero, fmt.Errorf("reply helper '%s' is of type %T, not %s", name, helper, reflect.TypeOf(fn))
+	}
+
+	return fn(in), nil
+}
+
 // GetInstanceDecorator retrieves an instance decorator
 func (dm *DecoratorManager) GetInstanceDecorator(name string) (interface{}, bool) {
 	dm.mu.RLock()
@@ -70,6 +140,7 @@ func (dm *DecoratorManager) GetInstanceDecorator(name string) (interface{}, bool
 }
 
 // GetRequestDecorator retrieves a request decorator default value
+// For factory decorators the RequestDecoratorFactory itself is returned
 func (dm *DecoratorManager) GetRequestDecorator(name string) (interface{}, bool) {
 	dm.mu.RLock()
 	defer dm.mu.RUnlock()
@@ -86,13 +157,24 @@ func (dm *DecoratorManager) GetReplyDecorator(name string) (interface{}, bool) {
 }
 
 // InitializeRequestContainer initializes request decorators in container
+// Factory decorators are called with a nil *gin.Context; use
+// InitializeRequestContainerWithContext when serving a request
 func (dm *DecoratorManager) InitializeRequestContainer(rc *RequestContainer) {
+	dm.InitializeRequestContainerWithContext(nil, rc)
+}
+
+// InitializeRequestContainerWithContext initializes request decorators in container,
+// calling factory decorators with the request's context
+func (dm *DecoratorManager) InitializeRequestContainerWithContext(c *gin.Context, rc *RequestContainer) {
 	dm.mu.RLock()
 	defer dm.mu.RUnlock()
 
 	for name, defaultValue := range dm.requestDecorators {
 	// Only set if not already present
 		if _, exists := rc.GetRequestData(name); !exists {
+			if factory, ok := defaultValue.(RequestDecoratorFactory); ok {
+				defaultValue = factory(c)
+			}
 			rc.DecorateRequest(name, defaultValue)
 		}
 	}
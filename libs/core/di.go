@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Lifetime defines the lifetime of a service in the DI container
@@ -43,25 +47,100 @@ type DIContainer interface {
 	RegisterProviderTransient(provider Provider) error
 	RegisterProviderScoped(provider Provider) error
 
+	// Group methods register providers under a named group (e.g. "healthChecks")
+	// and resolve every member, parent container members first, in registration order
+	RegisterProviderInGroup(group string, provider Provider) error
+	ResolveGroup(group string) ([]interface{}, error)
+	ResolveGroupWithContext(group string, ctx context.Context) ([]interface{}, error)
+
+	// Override methods replace an existing registration and drop its cached instance;
+	// intended for swapping real services with fakes in tests
+	OverrideProvider(provider Provider) error
+	Override(name string, factory Factory, lifetime Lifetime) error
+
 	// Resolution methods
 	Resolve(name string) (interface{}, error)
 	ResolveWithContext(name string, ctx context.Context) (interface{}, error)
 	ResolveAs(name string, target interface{}) error
 	ResolveAsWithContext(name string, ctx context.Context, target interface{}) error
 
+	// ResolveOptional resolves a service that may not be configured: found is false,
+	// with a nil error, only when the name is registered nowhere the container looks;
+	// a registered service that fails to build returns found and its error
+	ResolveOptional(name string) (service interface{}, found bool, err error)
+	ResolveOptionalWithContext(name string, ctx context.Context) (service interface{}, found bool, err error)
+
 	// Utility methods
 	Has(name string) bool
 	CreateScope() DIContainer
 
 	// Module-scoped container creation
 	CreateModuleScope(module *Module) DIContainer
+
+	// Dispose closes cached singletons implementing Disposable in reverse creation order
+	Dispose() error
+}
+
+// ServiceRemover is implemented by containers that can drop registrations; the
+// default container does, and PluginManager.UnregisterPlugin relies on it
+type ServiceRemover interface {
+	// Unregister removes a service registered directly on the container, disposing
+	// its cached singleton instance
+	Unregister(name string) error
+}
+
+// Disposable is implemented by services that hold resources to release on shutdown
+type Disposable interface {
+	Close() error
 }
 
 // diContainer is the default implementation of DIContainer
 type diContainer struct {
-	services map[string]*ServiceDefinition
-	mu       sync.RWMutex
-	parent   DIContainer // For scoped containers
+	services    map[string]*ServiceDefinition
+	mu          sync.RWMutex
+	parent      DIContainer // For scoped containers
+	disposables []disposableEntry // Singletons to close, in creation order
+	scope       *resolutionScope  // Scoped instance cache; set for CreateScope and request containers
+	groups      map[string][]string // Group name -> member service names in registration order
+	stats       atomic.Pointer[resolutionStats] // Set while resolution stats are enabled
+	naming      NamingStrategy // Names types are resolved by; nil uses the parent's, or the default lookup
+	interfaces  map[reflect.Type][]string // Interface -> services declared to implement it, see BindInterface
+}
+
+// cloneForValidation copies the container's registrations, interface bindings and
+// naming strategy without their cached instances (see throwawayContainer); stats
+// stay off, so validation does not count as resolutions
+func (c *diContainer) cloneForValidation() DIContainer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := &diContainer{
+		services: make(map[string]*ServiceDefinition, len(c.services)),
+		parent:   c.parent,
+		naming:   c.naming,
+	}
+	for name, service := range c.services {
+		clone.services[name] = &ServiceDefinition{Provider: service.Provider}
+	}
+	for group, names := range c.groups {
+		if clone.groups == nil {
+			clone.groups = make(map[string][]string)
+		}
+		clone.groups[group] = append([]string(nil), names...)
+	}
+	for iface, names := range c.interfaces {
+		if clone.interfaces == nil {
+			clone.interfaces = make(map[reflect.Type][]string)
+		}
+		clone.interfaces[iface] = append([]string(nil), names...)
+	}
+	return clone
+}
+
+// disposableEntry records a created singleton that must be closed on shutdown
+type disposableEntry struct {
+	name     string
+	instance Disposable
 }
 
 // NewDIContainer creates a new dependency injection container
@@ -82,8 +161,21 @@ func (c *diContainer) Register(name string, factory Factory, lifetime Lifetime)
 	return c.RegisterProvider(provider)
 }
 
+// Provider errors
+var (
+	// ErrProviderNil is returned when registering a nil provider
+	ErrProviderNil = newError("provider cannot be nil")
+	// ErrDependencyNotRegistered is reported for a dependency a provider declares
+	// (see DependencyProvider) that is not registered in its scope
+	ErrDependencyNotRegistered = newError("declared dependency is not registered")
+)
+
 // RegisterProvider registers a provider (new primary method)
 func (c *diContainer) RegisterProvider(provider Provider) error {
+	if isNilValue(provider) {
+		return ErrProviderNil
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -92,8 +184,8 @@ func (c *diContainer) RegisterProvider(provider Provider) error {
 		return fmt.Errorf("service '%s' is already registered", name)
 	}
 
-	if provider == nil {
-		return fmt.Errorf("provider cannot be nil")
+	if err := validateProvider(provider); err != nil {
+		return err
 	}
 
 	c.services[name] = &ServiceDefinition{
@@ -103,20 +195,157 @@ func (c *diContainer) RegisterProvider(provider Provider) error {
 	return nil
 }
 
+// RegisterProviderInGroup registers the provider like RegisterProvider and adds it to the group
+// Members keep their own names, so they can also be resolved (or overridden) individually
+func (c *diContainer) RegisterProviderInGroup(group string, provider Provider) error {
+	if err := c.RegisterProvider(provider); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.groups == nil {
+		c.groups = make(map[string][]string)
+	}
+	c.groups[group] = append(c.groups[group], provider.GetName())
+
+	return nil
+}
+
+// ResolveGroup resolves every member of the group
+func (c *diContainer) ResolveGroup(group string) ([]interface{}, error) {
+	return c.ResolveGroupWithContext(group, context.Background())
+}
+
+// ResolveGroupWithContext resolves every member of the group, honoring each member's lifetime
+// An unknown group resolves to an empty slice
+func (c *diContainer) ResolveGroupWithContext(group string, ctx context.Context) ([]interface{}, error) {
+	return resolveGroup(c, c, group, ctx)
+}
+
+// resolveGroup resolves the members registered on base through self (the outermost container
+// type, so module decorators and request scoping apply), after the members of base's parent
+func resolveGroup(self DIContainer, base *diContainer, group string, ctx context.Context) ([]interface{}, error) {
+	ctx = base.enterScope(ctx)
+	instances := []interface{}{}
+
+	if base.parent != nil {
+		parentInstances, err := base.parent.ResolveGroupWithContext(group, ctx)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, parentInstances...)
+	}
+
+	base.mu.RLock()
+	names := append([]string(nil), base.groups[group]...)
+	base.mu.RUnlock()
+
+	for _, name := range names {
+		instance, err := self.ResolveWithContext(name, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve '%s' in group '%s': %w", name, group, err)
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// OverrideProvider replaces the registration of provider's name with provider
+// A service registered only in a parent container is shadowed in this container
+// Cached singleton instances of the replaced registration are dropped, not disposed
+func (c *diContainer) OverrideProvider(provider Provider) error {
+	if isNilValue(provider) {
+		return ErrProviderNil
+	}
+
+	if err := validateProvider(provider); err != nil {
+		return err
+	}
+
+	name := provider.GetName()
+	if !c.Has(name) {
+		return fmt.Errorf("service '%s' is not registered", name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services[name] = &ServiceDefinition{
+		Provider: provider,
+	}
+
+	return nil
+}
+
+// Override replaces an existing registration with a factory (see OverrideProvider)
+func (c *diContainer) Override(name string, factory Factory, lifetime Lifetime) error {
+	return c.OverrideProvider(&FactoryProvider{
+		Name:     name,
+		Factory:  factory,
+		Lifetime: lifetime,
+	})
+}
+
+// Unregister removes a service registered on this container, also from its groups
+// and interface bindings
+// A cached singleton implementing Disposable is closed; the close error is returned
+func (c *diContainer) Unregister(name string) error {
+	c.mu.Lock()
+	if _, exists := c.services[name]; !exists {
+		c.mu.Unlock()
+		return fmt.Errorf("service '%s' is not registered", name)
+	}
+	delete(c.services, name)
+
+	for group, members := range c.groups {
+		c.groups[group] = slices.DeleteFunc(slices.Clone(members), func(member string) bool { return member == name })
+	}
+	for iface, names := range c.interfaces {
+		c.interfaces[iface] = slices.DeleteFunc(slices.Clone(names), func(bound string) bool { return bound == name })
+	}
+
+	var disposable Disposable
+	c.disposables = slices.DeleteFunc(c.disposables, func(entry disposableEntry) bool {
+		if entry.name == name {
+			disposable = entry.instance
+			return true
+		}
+		return false
+	})
+	c.mu.Unlock()
+
+	if disposable != nil {
+		if err := disposable.Close(); err != nil {
+			return fmt.Errorf("failed to dispose service '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
 // RegisterProviderSingleton registers a singleton provider
 func (c *diContainer) RegisterProviderSingleton(provider Provider) error {
+	if isNilValue(provider) {
+		return ErrProviderNil
+	}
 	// Create a wrapper provider with Singleton lifetime
 	return c.RegisterProvider(&singletonLifetimeWrapper{Provider: provider})
 }
 
 // RegisterProviderTransient registers a transient provider
 func (c *diContainer) RegisterProviderTransient(provider Provider) error {
+	if isNilValue(provider) {
+		return ErrProviderNil
+	}
 	// Create a wrapper provider with Transient lifetime
 	return c.RegisterProvider(&transientLifetimeWrapper{Provider: provider})
 }
 
 // RegisterProviderScoped registers a scoped provider
 func (c *diContainer) RegisterProviderScoped(provider Provider) error {
+	if isNilValue(provider) {
+		return ErrProviderNil
+	}
 	// Create a wrapper provider with Scoped lifetime
 	return c.RegisterProvider(&scopedLifetimeWrapper{Provider: provider})
 }
@@ -143,52 +372,162 @@ func (c *diContainer) Resolve(name string) (interface{}, error) {
 
 // ResolveWithContext enables async resolution
 func (c *diContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
-	c.mu.RLock()
-	service, exists := c.services[name]
-	c.mu.RUnlock()
+	ctx = c.enterScope(ctx)
 
+	service, exists := c.lookupService(name)
 	if !exists {
 		// Check parent container if this is a scoped container
 		if c.parent != nil {
-			if parentWithCtx, ok := c.parent.(*diContainer); ok {
-				return parentWithCtx.ResolveWithContext(name, ctx)
-			}
-			return c.parent.Resolve(name)
+			return c.parent.ResolveWithContext(name, ctx)
 		}
-		return nil, fmt.Errorf("service '%s' is not registered", name)
+		return nil, &ServiceNotRegisteredError{Name: name}
+	}
+
+	return c.resolveService(c, name, service, ctx)
+}
+
+// ResolveOptional resolves a service, reporting whether it is registered
+func (c *diContainer) ResolveOptional(name string) (interface{}, bool, error) {
+	return c.ResolveOptionalWithContext(name, context.Background())
+}
+
+// ResolveOptionalWithContext is ResolveOptional with a context
+func (c *diContainer) ResolveOptionalWithContext(name string, ctx context.Context) (interface{}, bool, error) {
+	return resolveOptional(c, name, ctx)
+}
+
+// resolveOptional resolves name through container, telling a name nothing registered
+// apart from a service failing to build, e.g. because one of its dependencies is missing
+func resolveOptional(container DIContainer, name string, ctx context.Context) (interface{}, bool, error) {
+	service, err := container.ResolveWithContext(name, ctx)
+	var notRegistered *ServiceNotRegisteredError
+	if errors.As(err, &notRegistered) && notRegistered.Name == name {
+		return nil, false, nil
 	}
+	return service, true, err
+}
 
+// resolveService builds or reuses an instance of a service registered on this
+// container according to its lifetime; owner is the container its provider resolves
+// dependencies through. Resolutions are recorded when resolution stats are enabled
+func (c *diContainer) resolveService(owner DIContainer, name string, service *ServiceDefinition, ctx context.Context) (interface{}, error) {
+	stats := c.stats.Load()
+	if stats == nil {
+		instance, _, err := c.buildService(owner, name, service, ctx)
+		return instance, err
+	}
+
+	start := time.Now()
+	instance, cached, err := c.buildService(owner, name, service, ctx)
+	stats.record(name, cached, time.Since(start), err)
+	return instance, err
+}
+
+// buildService resolves a service according to its lifetime, reporting whether a
+// cached singleton instance was returned
+func (c *diContainer) buildService(owner DIContainer, name string, service *ServiceDefinition, ctx context.Context) (interface{}, bool, error) {
 	provider := service.Provider
 
 	switch provider.GetLifetime() {
 	case Singleton:
-		if service.Instance != nil {
-			return service.Instance, nil
+		if instance := c.cachedInstance(service); instance != nil {
+			return instance, true, nil
 		}
 
 		// Create singleton instance
-		instance, err := provider.Resolve(c, ctx)
+		instance, err := resolveProvider(owner, name, provider, ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create singleton service '%s': %w", name, err)
+			return nil, false, fmt.Errorf("failed to create singleton service '%s': %w", name, err)
 		}
 
 		// Store the instance
-		c.mu.Lock()
-		service.Instance = instance
-		c.mu.Unlock()
-
-		return instance, nil
+		return c.storeSingleton(name, service, instance), false, nil
 
 	case Transient:
-		return provider.Resolve(c, ctx)
+		instance, err := resolveProvider(owner, name, provider, ctx)
+		return instance, false, err
 
 	case Scoped:
-		// For scoped services, always create a new instance in the current scope
-		return provider.Resolve(c, ctx)
+		instance, err := resolveScoped(owner, name, provider, ctx)
+		return instance, false, err
 
 	default:
-		return nil, fmt.Errorf("unknown lifetime for service '%s'", name)
+		return nil, false, fmt.Errorf("unknown lifetime for service '%s'", name)
+	}
+}
+
+// lookupService returns the definition registered directly on this container
+func (c *diContainer) lookupService(name string) (*ServiceDefinition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	service, exists := c.services[name]
+	return service, exists
+}
+
+// cachedInstance returns the cached instance of a service, if any
+func (c *diContainer) cachedInstance(service *ServiceDefinition) interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return service.Instance
+}
+
+// storeSingleton caches a created singleton and tracks it for disposal
+// If another caller stored an instance first, that instance wins and the losing
+// instance is closed, since nothing else would ever dispose it
+func (c *diContainer) storeSingleton(name string, service *ServiceDefinition, instance interface{}) interface{} {
+	c.mu.Lock()
+	if winner := service.Instance; winner != nil {
+		c.mu.Unlock()
+		if disposable, ok := instance.(Disposable); ok && !sameInstance(instance, winner) {
+			// The loser was never handed out, so a close error has nobody to report to
+			_ = disposable.Close()
+		}
+		return winner
 	}
+	defer c.mu.Unlock()
+
+	service.Instance = instance
+	if disposable, ok := instance.(Disposable); ok {
+		c.disposables = append(c.disposables, disposableEntry{name: name, instance: disposable})
+	}
+
+	return instance
+}
+
+// sameInstance reports whether a and b are the same value, e.g. a factory returning
+// a shared instance, without panicking on uncomparable types
+func sameInstance(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.ValueOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// trackDisposable registers an instance to close when the container is disposed
+func (c *diContainer) trackDisposable(name string, instance interface{}) {
+	if disposable, ok := instance.(Disposable); ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.disposables = append(c.disposables, disposableEntry{name: name, instance: disposable})
+	}
+}
+
+// Dispose closes tracked singletons in reverse creation order
+// All close errors are collected and returned together
+func (c *diContainer) Dispose() error {
+	c.mu.Lock()
+	entries := c.disposables
+	c.disposables = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := entries[i].instance.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to dispose service '%s': %w", entries[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // ResolveAs resolves a service and assigns it to the target pointer
@@ -202,15 +541,25 @@ func (c *diContainer) ResolveAsWithContext(name string, ctx context.Context, tar
 	if err != nil {
 		return err
 	}
+	return assignResolved(name, instance, target)
+}
 
+// assignResolved assigns a resolved instance to the target pointer; containers
+// overriding ResolveWithContext use it to implement ResolveAsWithContext
+func assignResolved(name string, instance interface{}, target interface{}) error {
 	targetValue := reflect.ValueOf(target)
 	if targetValue.Kind() != reflect.Ptr {
 		return errors.New("target must be a pointer")
 	}
 
+	targetType := targetValue.Elem().Type()
+	if instance == nil {
+		return fmt.Errorf("service '%s' resolved to nil, cannot assign to %s", name, targetType)
+	}
+
 	instanceValue := reflect.ValueOf(instance)
-	if !instanceValue.Type().AssignableTo(targetValue.Elem().Type()) {
-		return fmt.Errorf("service '%s' cannot be assigned to target type", name)
+	if !instanceValue.Type().AssignableTo(targetType) {
+		return fmt.Errorf("service '%s' of type %s cannot be assigned to target type %s", name, instanceValue.Type(), targetType)
 	}
 
 	targetValue.Elem().Set(instanceValue)
@@ -230,12 +579,176 @@ func (c *diContainer) Has(name string) bool {
 	return exists
 }
 
-// CreateScope creates a new scoped container
+// CreateScope creates a new scoped container (a *ScopedContainer)
+// Scoped services resolved through it are built once and reused until the scope is
+// closed; Close (or Dispose) closes the Disposable ones
 func (c *diContainer) CreateScope() DIContainer {
-	return &diContainer{
-		services: make(map[string]*ServiceDefinition),
-		parent:   c,
+	return NewScopedContainer(c)
+}
+
+// resolutionScope caches Scoped instances for one request or ScopedContainer
+type resolutionScope struct {
+	mu        sync.Mutex
+	instances map[scopedInstanceKey]interface{}
+	container *diContainer // disposes the scope's instances
+}
+
+// scopedInstanceKey identifies a Scoped service by the container that registered it
+type scopedInstanceKey struct {
+	owner DIContainer
+	name  string
+}
+
+// scopeKey is the context key for the innermost resolution scope
+type scopeKey struct{}
+
+func newResolutionScope(container *diContainer) *resolutionScope {
+	return &resolutionScope{
+		instances: make(map[scopedInstanceKey]interface{}),
+		container: container,
+	}
+}
+
+// enterScope makes the container's scope the active one for the resolution, if it has one
+func (c *diContainer) enterScope(ctx context.Context) context.Context {
+	if c.scope == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeKey{}, c.scope)
+}
+
+// resolveScoped builds a Scoped service once per active scope
+// Scoped services resolved outside any scope are built fresh every time
+func resolveScoped(owner DIContainer, name string, provider Provider, ctx context.Context) (interface{}, error) {
+	scope, _ := ctx.Value(scopeKey{}).(*resolutionScope)
+	if scope == nil {
+		return resolveProvider(owner, name, provider, ctx)
+	}
+
+	key := scopedInstanceKey{owner: owner, name: name}
+	scope.mu.Lock()
+	instance, exists := scope.instances[key]
+	scope.mu.Unlock()
+	if exists {
+		return instance, nil
+	}
+
+	// Build outside the lock so the factory can resolve other Scoped services
+	instance, err := resolveProvider(owner, name, provider, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scoped service '%s': %w", name, err)
+	}
+
+	scope.mu.Lock()
+	if existing, exists := scope.instances[key]; exists {
+		scope.mu.Unlock()
+		if disposable, ok := instance.(Disposable); ok && !sameInstance(instance, existing) {
+			// The loser was never handed out, so a close error has nobody to report to
+			_ = disposable.Close()
+		}
+		return existing, nil
 	}
+	defer scope.mu.Unlock()
+	scope.instances[key] = instance
+	scope.container.trackDisposable(name, instance)
+
+	return instance, nil
+}
+
+// CircularDependencyError reports a provider that (indirectly) depends on itself
+type CircularDependencyError struct {
+	Chain []string // Service names in resolution order, ending with the repeated name
+}
+
+func (e *CircularDependencyError) Error() string {
+	return fmt.Sprintf("circular dependency: %s", strings.Join(e.Chain, " -> "))
+}
+
+// ServiceNotRegisteredError is returned when resolving a name no container in the
+// chain has registered; a dependency missing while building a service surfaces as
+// one too, naming the dependency
+type ServiceNotRegisteredError struct {
+	Name   string
+	Module string // The module container resolving it, if any
+}
+
+func (e *ServiceNotRegisteredError) Error() string {
+	if e.Module != "" {
+		return fmt.Sprintf("service '%s' is not registered in module '%s'", e.Name, e.Module)
+	}
+	return fmt.Sprintf("service '%s' is not registered", e.Name)
+}
+
+// resolutionKey is the context key for the in-progress resolution chain
+type resolutionKey struct{}
+
+// resolutionFrame is one service being resolved in the current call chain
+type resolutionFrame struct {
+	container DIContainer
+	name      string
+	prev      *resolutionFrame
+}
+
+// resolveProvider runs a provider while tracking the resolution chain in the context
+// The chain lives in an immutable context value, so it unwinds automatically
+// when a factory returns or panics
+func resolveProvider(container DIContainer, name string, provider Provider, ctx context.Context) (interface{}, error) {
+	frame, _ := ctx.Value(resolutionKey{}).(*resolutionFrame)
+
+	for f := frame; f != nil; f = f.prev {
+		if f.container == container && f.name == name {
+			return nil, &CircularDependencyError{Chain: append(frame.chain(), name)}
+		}
+	}
+
+	ctx = context.WithValue(ctx, resolutionKey{}, &resolutionFrame{
+		container: container,
+		name:      name,
+		prev:      frame,
+	})
+
+	return provider.Resolve(&boundContainer{DIContainer: container, ctx: ctx}, ctx)
+}
+
+// chain returns the service names from the outermost resolution to this frame
+func (f *resolutionFrame) chain() []string {
+	var names []string
+	for current := f; current != nil; current = current.prev {
+		names = append([]string{current.name}, names...)
+	}
+	return names
+}
+
+// boundContainer is handed to factories so that nested Resolve calls
+// keep the resolution chain of the service being built
+// Router handlers get one bound to the request context, so their Resolve calls
+// see its cancellation and deadline
+type boundContainer struct {
+	DIContainer
+	ctx context.Context
+}
+
+func (b *boundContainer) Resolve(name string) (interface{}, error) {
+	return b.DIContainer.ResolveWithContext(name, b.ctx)
+}
+
+func (b *boundContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
+	if ctx.Value(resolutionKey{}) == nil {
+		ctx = context.WithValue(ctx, resolutionKey{}, b.ctx.Value(resolutionKey{}))
+	}
+	return b.DIContainer.ResolveWithContext(name, ctx)
+}
+
+func (b *boundContainer) ResolveAs(name string, target interface{}) error {
+	return b.DIContainer.ResolveAsWithContext(name, b.ctx, target)
+}
+
+func (b *boundContainer) ResolveOptional(name string) (interface{}, bool, error) {
+	return resolveOptional(b, name, b.ctx)
+}
+
+func (b *boundContainer) ResolveOptionalWithContext(name string, ctx context.Context) (interface{}, bool, error) {
+	return resolveOptional(b, name, ctx)
 }
 
 // Lifetime wrapper providers for RegisterProviderSingleton/Transient/Scoped
@@ -248,6 +761,11 @@ func (w *singletonLifetimeWrapper) GetLifetime() Lifetime {
 	return Singleton
 }
 
+func (w *singletonLifetimeWrapper) IsEager() bool {
+	eager, ok := w.Provider.(EagerProvider)
+	return ok && eager.IsEager()
+}
+
 type transientLifetimeWrapper struct {
 	Provider
 }
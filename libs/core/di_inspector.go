@@ -0,0 +1,204 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// String returns the lifetime name, e.g. "singleton"
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the lifetime by name, e.g. in ServiceInfo JSON
+func (l Lifetime) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// ServiceInfo describes a registered service, as listed by ContainerInspector.ListServices
+type ServiceInfo struct {
+	Name     string   `json:"name"`
+	Lifetime Lifetime `json:"lifetime"`
+	Async    bool     `json:"async"`
+	Depth    int      `json:"depth"` // 0 for the inspected container, 1 for its parent, and so on
+	// DependsOn lists the services the provider declares it resolves (see DependencyProvider)
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// ResolutionStats holds the resolutions of one service while stats are enabled
+// Latency includes building the service's dependencies
+type ResolutionStats struct {
+	Resolutions  int64         `json:"resolutions"`
+	CacheHits    int64         `json:"cacheHits"` // Singleton resolutions served from the cache
+	Errors       int64         `json:"errors"`
+	TotalLatency time.Duration `json:"totalLatency"`
+	MaxLatency   time.Duration `json:"maxLatency"`
+}
+
+// AverageLatency returns the mean latency of the recorded resolutions
+func (s ResolutionStats) AverageLatency() time.Duration {
+	if s.Resolutions == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Resolutions)
+}
+
+// ContainerInspector is implemented by containers that can describe their services
+// for debugging, e.g. from an admin endpoint; the default container does
+//
+//	inspector := app.GetContainer().(core.ContainerInspector)
+//	inspector.EnableResolutionStats(true)
+type ContainerInspector interface {
+	// ListServices returns the services resolvable through the container, its own
+	// first, then those of its parents that it does not shadow, each sorted by name
+	ListServices() []ServiceInfo
+
+	// EnableResolutionStats turns recording of resolutions on or off; stats are kept by
+	// the container a service is registered on, and disabling them drops the counts
+	EnableResolutionStats(enabled bool)
+
+	// GetResolutionStats returns the recorded stats by service name, for the container
+	// and its parents, the nearest registration winning
+	GetResolutionStats() map[string]ResolutionStats
+
+	// ValidateDependencies reports every dependency the container's providers declare
+	// (see DependencyProvider) that neither it nor its parents register
+	ValidateDependencies() error
+}
+
+// resolutionStats records resolutions by service name
+type resolutionStats struct {
+	mu       sync.Mutex
+	services map[string]*ResolutionStats
+}
+
+func (s *resolutionStats) record(name string, cached bool, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, exists := s.services[name]
+	if !exists {
+		stats = &ResolutionStats{}
+		s.services[name] = stats
+	}
+	stats.Resolutions++
+	if cached {
+		stats.CacheHits++
+	}
+	if err != nil {
+		stats.Errors++
+	}
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
+
+func (s *resolutionStats) snapshot() map[string]ResolutionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]ResolutionStats, len(s.services))
+	for name, stats := range s.services {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
+
+// EnableResolutionStats turns recording of resolutions on or off
+// Enabling stats that are already on keeps the recorded counts
+func (c *diContainer) EnableResolutionStats(enabled bool) {
+	if !enabled {
+		c.stats.Store(nil)
+		return
+	}
+	c.stats.CompareAndSwap(nil, &resolutionStats{services: make(map[string]*ResolutionStats)})
+}
+
+// GetResolutionStats returns the recorded stats of the container and its parents
+func (c *diContainer) GetResolutionStats() map[string]ResolutionStats {
+	result := make(map[string]ResolutionStats)
+	if inspector, ok := c.parent.(ContainerInspector); ok {
+		for name, stats := range inspector.GetResolutionStats() {
+			result[name] = stats
+		}
+	}
+
+	if stats := c.stats.Load(); stats != nil {
+		for name, serviceStats := range stats.snapshot() {
+			result[name] = serviceStats
+		}
+	}
+	return result
+}
+
+// ListServices returns the services of the container, then the parents' it does not shadow
+func (c *diContainer) ListServices() []ServiceInfo {
+	c.mu.RLock()
+	services := make([]ServiceInfo, 0, len(c.services))
+	seen := make(map[string]bool, len(c.services))
+	for name, service := range c.services {
+		services = append(services, ServiceInfo{
+			Name:      name,
+			Lifetime:  service.Provider.GetLifetime(),
+			Async:     service.Provider.IsAsync(),
+			DependsOn: providerDependencies(service.Provider),
+		})
+		seen[name] = true
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	if inspector, ok := c.parent.(ContainerInspector); ok {
+		for _, info := range inspector.ListServices() {
+			if seen[info.Name] {
+				continue
+			}
+			info.Depth++
+			services = append(services, info)
+		}
+	}
+	return services
+}
+
+// ValidateDependencies reports the declared dependencies of the container's own
+// providers that are not registered, sorted by service name
+func (c *diContainer) ValidateDependencies() error {
+	c.mu.RLock()
+	declared := make(map[string][]string)
+	for name, service := range c.services {
+		if dependencies := providerDependencies(service.Provider); len(dependencies) > 0 {
+			declared[name] = dependencies
+		}
+	}
+	c.mu.RUnlock()
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		for _, dependency := range declared[name] {
+			if !c.Has(dependency) {
+				errs = append(errs, fmt.Errorf("%w: service '%s' depends on '%s'", ErrDependencyNotRegistered, name, dependency))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
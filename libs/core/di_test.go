@@ -0,0 +1,607 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDIContainer_CircularDependency(t *testing.T) {
+	container := NewDIContainer()
+
+	container.RegisterSingleton("A", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("B")
+	})
+	container.RegisterTransient("B", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("A")
+	})
+
+	_, err := container.Resolve("A")
+	require.Error(t, err)
+
+	var cycleErr *CircularDependencyError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []string{"A", "B", "A"}, cycleErr.Chain)
+	assert.Contains(t, err.Error(), "circular dependency: A -> B -> A")
+}
+
+func TestDIContainer_CircularDependencyAcrossScopes(t *testing.T) {
+	root := NewDIContainer()
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), root)
+
+	moduleContainer.RegisterSingleton("service", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("repository")
+	})
+	root.RegisterSingleton("repository", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("repository")
+	})
+
+	_, err := moduleContainer.Resolve("service")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "circular dependency: service -> repository -> repository")
+}
+
+func TestDIContainer_SameNameInParentIsNotCycle(t *testing.T) {
+	root := NewDIContainer()
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), root)
+
+	root.RegisterSingleton("config", func(c DIContainer) (interface{}, error) {
+		return "root-config", nil
+	})
+	// Module-level service wraps the parent service with the same name
+	moduleContainer.RegisterProvider(NewAsyncProvider("config", func(c DIContainer, ctx context.Context) (interface{}, error) {
+		parent, err := root.ResolveWithContext("config", ctx)
+		if err != nil {
+			return nil, err
+		}
+		return "module:" + parent.(string), nil
+	}, Singleton))
+
+	value, err := moduleContainer.Resolve("config")
+	require.NoError(t, err)
+	assert.Equal(t, "module:root-config", value)
+}
+
+func TestDIContainer_ResolutionChainClearedAfterPanic(t *testing.T) {
+	container := NewDIContainer()
+	shouldPanic := true
+
+	container.RegisterTransient("flaky", func(c DIContainer) (interface{}, error) {
+		if shouldPanic {
+			panic("factory exploded")
+		}
+		return "ok", nil
+	})
+
+	assert.Panics(t, func() {
+		container.Resolve("flaky")
+	})
+
+	shouldPanic = false
+	value, err := container.Resolve("flaky")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", value)
+}
+
+// disposableService records when it is closed
+type disposableService struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (s *disposableService) Close() error {
+	*s.closed = append(*s.closed, s.name)
+	return s.err
+}
+
+// countingDisposable counts how often instances of it are closed
+type countingDisposable struct {
+	closed *atomic.Int32
+}
+
+func (d *countingDisposable) Close() error {
+	d.closed.Add(1)
+	return nil
+}
+
+func TestDIContainer_ConcurrentSingletonClosesLosingInstance(t *testing.T) {
+	container := NewDIContainer()
+
+	var built, closed atomic.Int32
+	bothBuilding := make(chan struct{})
+	container.RegisterSingleton("pool", func(c DIContainer) (interface{}, error) {
+		if built.Add(1) == 2 {
+			close(bothBuilding)
+		}
+		<-bothBuilding
+		return &countingDisposable{closed: &closed}, nil
+	})
+
+	var wg sync.WaitGroup
+	instances := make([]interface{}, 2)
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instance, err := container.Resolve("pool")
+			assert.NoError(t, err)
+			instances[i] = instance
+		}()
+	}
+	wg.Wait()
+
+	assert.Same(t, instances[0], instances[1])
+	assert.Equal(t, int32(2), built.Load())
+	assert.Equal(t, int32(1), closed.Load(), "the losing instance is closed")
+
+	require.NoError(t, container.Dispose())
+	assert.Equal(t, int32(2), closed.Load(), "the winning instance is closed on dispose")
+}
+
+func TestDIContainer_DisposeReverseOrder(t *testing.T) {
+	container := NewDIContainer()
+	var closed []string
+
+	container.RegisterSingleton("db", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "db", closed: &closed}, nil
+	})
+	container.RegisterSingleton("repository", func(c DIContainer) (interface{}, error) {
+		if _, err := c.Resolve("db"); err != nil {
+			return nil, err
+		}
+		return &disposableService{name: "repository", closed: &closed}, nil
+	})
+	container.RegisterTransient("transient", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "transient", closed: &closed}, nil
+	})
+
+	_, err := container.Resolve("repository")
+	require.NoError(t, err)
+	_, err = container.Resolve("transient")
+	require.NoError(t, err)
+
+	require.NoError(t, container.Dispose())
+	assert.Equal(t, []string{"repository", "db"}, closed)
+
+	// Disposing twice does not close instances again
+	require.NoError(t, container.Dispose())
+	assert.Len(t, closed, 2)
+}
+
+func TestDIContainer_DisposeCollectsErrors(t *testing.T) {
+	container := NewDIContainer()
+	var closed []string
+	errFirst := errors.New("flush failed")
+	errSecond := errors.New("connection reset")
+
+	container.RegisterSingleton("first", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "first", closed: &closed, err: errFirst}, nil
+	})
+	container.RegisterSingleton("second", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "second", closed: &closed, err: errSecond}, nil
+	})
+
+	container.Resolve("first")
+	container.Resolve("second")
+
+	err := container.Dispose()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errSecond)
+	assert.Equal(t, []string{"second", "first"}, closed)
+}
+
+func TestDIContainer_CreateScopeCachesScopedServices(t *testing.T) {
+	container := NewDIContainer()
+	var closed []string
+	container.RegisterScoped("session", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "session", closed: &closed}, nil
+	})
+
+	scope := container.CreateScope()
+	first, err := scope.Resolve("session")
+	require.NoError(t, err)
+	second, err := scope.Resolve("session")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := container.CreateScope().Resolve("session")
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	// Disposing the scope closes its scoped instances only
+	require.NoError(t, scope.Dispose())
+	assert.Equal(t, []string{"session"}, closed)
+}
+
+func TestDIContainer_ConcurrentScopedClosesLosingInstance(t *testing.T) {
+	container := NewDIContainer()
+
+	var built, closed atomic.Int32
+	bothBuilding := make(chan struct{})
+	container.RegisterScoped("session", func(c DIContainer) (interface{}, error) {
+		if built.Add(1) == 2 {
+			close(bothBuilding)
+		}
+		<-bothBuilding
+		return &countingDisposable{closed: &closed}, nil
+	})
+
+	scope := container.CreateScope()
+	var wg sync.WaitGroup
+	instances := make([]interface{}, 2)
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instance, err := scope.Resolve("session")
+			assert.NoError(t, err)
+			instances[i] = instance
+		}()
+	}
+	wg.Wait()
+
+	assert.Same(t, instances[0], instances[1])
+	assert.Equal(t, int32(2), built.Load())
+	assert.Equal(t, int32(1), closed.Load(), "the losing instance is closed")
+
+	require.NoError(t, scope.Dispose())
+	assert.Equal(t, int32(2), closed.Load(), "the winning instance is closed on dispose")
+}
+
+func TestDIContainer_OverrideProvider(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterSingleton("db", func(c DIContainer) (interface{}, error) {
+		return "postgres", nil
+	}))
+
+	// Cache the real instance first
+	db, err := container.Resolve("db")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", db)
+
+	require.NoError(t, container.OverrideProvider(NewValueProvider("db", "fake-db")))
+	db, err = container.Resolve("db")
+	require.NoError(t, err)
+	assert.Equal(t, "fake-db", db)
+
+	builds := 0
+	require.NoError(t, container.Override("db", func(c DIContainer) (interface{}, error) {
+		builds++
+		return "transient-db", nil
+	}, Transient))
+	container.Resolve("db")
+	container.Resolve("db")
+	assert.Equal(t, 2, builds)
+
+	err = container.Override("missing", func(c DIContainer) (interface{}, error) {
+		return nil, nil
+	}, Singleton)
+	assert.EqualError(t, err, "service 'missing' is not registered")
+	assert.Error(t, container.OverrideProvider(nil))
+}
+
+func TestDIContainer_OverrideHonoredByModuleAndRequestContainers(t *testing.T) {
+	root := NewDIContainer()
+	require.NoError(t, root.RegisterSingleton("mailer", func(c DIContainer) (interface{}, error) {
+		return "smtp", nil
+	}))
+
+	moduleContainer := NewModuleContainer(DefaultModule("users", "1.0.0"), root)
+	require.NoError(t, moduleContainer.RegisterSingleton("userService", func(c DIContainer) (interface{}, error) {
+		mailer, err := c.Resolve("mailer")
+		if err != nil {
+			return nil, err
+		}
+		return "users via " + mailer.(string), nil
+	}))
+
+	requestContainer := moduleContainer.CreateRequestScope()
+	service, err := requestContainer.Resolve("userService")
+	require.NoError(t, err)
+	assert.Equal(t, "users via smtp", service)
+
+	// Swap the root dependency and the module service after the app is built
+	require.NoError(t, root.OverrideProvider(NewValueProvider("mailer", "fake-mailer")))
+	require.NoError(t, moduleContainer.Override("userService", func(c DIContainer) (interface{}, error) {
+		mailer, err := c.Resolve("mailer")
+		if err != nil {
+			return nil, err
+		}
+		return "fake users via " + mailer.(string), nil
+	}, Singleton))
+
+	service, err = moduleContainer.CreateRequestScope().Resolve("userService")
+	require.NoError(t, err)
+	assert.Equal(t, "fake users via fake-mailer", service)
+
+	// Overriding a parent's service from a child shadows it only in the child
+	require.NoError(t, moduleContainer.OverrideProvider(NewValueProvider("mailer", "module-mailer")))
+	mailer, err := moduleContainer.Resolve("mailer")
+	require.NoError(t, err)
+	assert.Equal(t, "module-mailer", mailer)
+	mailer, err = root.Resolve("mailer")
+	require.NoError(t, err)
+	assert.Equal(t, "fake-mailer", mailer)
+}
+
+func TestDIContainer_ResolveGroup(t *testing.T) {
+	container := NewDIContainer()
+
+	transientBuilds := 0
+	require.NoError(t, container.RegisterProviderInGroup("handlers", NewValueProvider("auditHandler", "audit")))
+	require.NoError(t, container.RegisterProviderInGroup("handlers", NewFactoryProvider("mailHandler", func(c DIContainer) (interface{}, error) {
+		transientBuilds++
+		return &TestService{Value: "mail"}, nil
+	}, Transient)))
+	require.NoError(t, container.RegisterProviderInGroup("handlers", NewFactoryProvider("metricsHandler", func(c DIContainer) (interface{}, error) {
+		return &TestService{Value: "metrics"}, nil
+	}, Singleton)))
+
+	first, err := container.ResolveGroup("handlers")
+	require.NoError(t, err)
+	require.Len(t, first, 3)
+	assert.Equal(t, "audit", first[0])
+	assert.Equal(t, "mail", first[1].(*TestService).Value)
+	assert.Equal(t, "metrics", first[2].(*TestService).Value)
+
+	second, err := container.ResolveGroup("handlers")
+	require.NoError(t, err)
+	assert.NotSame(t, first[1], second[1], "transient members are rebuilt")
+	assert.Same(t, first[2], second[2], "singleton members are cached")
+	assert.Equal(t, 2, transientBuilds)
+
+	// Members are regular registrations too
+	metrics, err := container.Resolve("metricsHandler")
+	require.NoError(t, err)
+	assert.Same(t, first[2], metrics)
+
+	empty, err := container.ResolveGroup("unknown")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// Names stay unique across groups
+	assert.Error(t, container.RegisterProviderInGroup("other", NewValueProvider("auditHandler", "again")))
+}
+
+func TestDIContainer_ResolveGroupAcrossModuleScopes(t *testing.T) {
+	root := NewDIContainer()
+	require.NoError(t, root.RegisterProviderInGroup("readiness", NewValueProvider("rootCheck", "root")))
+
+	moduleContainer := NewModuleContainer(DefaultModule("orders", "1.0.0"), root)
+	require.NoError(t, moduleContainer.RegisterProviderInGroup("readiness", NewFactoryProvider("ordersCheck", func(c DIContainer) (interface{}, error) {
+		return &TestService{Value: "orders"}, nil
+	}, Scoped)))
+
+	siblingContainer := NewModuleContainer(DefaultModule("billing", "1.0.0"), root)
+
+	members, err := moduleContainer.ResolveGroup("readiness")
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "root", members[0])
+	assert.Equal(t, "orders", members[1].(*TestService).Value)
+
+	// Sibling modules only see their own and inherited members
+	members, err = siblingContainer.ResolveGroup("readiness")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"root"}, members)
+
+	// Scoped members are shared within a request and rebuilt across requests
+	request := moduleContainer.CreateRequestScope()
+	first, err := request.ResolveGroup("readiness")
+	require.NoError(t, err)
+	second, err := request.ResolveGroup("readiness")
+	require.NoError(t, err)
+	assert.Same(t, first[1], second[1])
+
+	other, err := moduleContainer.CreateRequestScope().ResolveGroup("readiness")
+	require.NoError(t, err)
+	assert.NotSame(t, first[1], other[1])
+}
+
+func TestDIContainer_ResolutionStats(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterSingleton("db", func(c DIContainer) (interface{}, error) {
+		return "postgres", nil
+	}))
+	require.NoError(t, container.RegisterTransient("repo", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("db")
+	}))
+	require.NoError(t, container.RegisterTransient("broken", func(c DIContainer) (interface{}, error) {
+		return nil, errors.New("boom")
+	}))
+
+	inspector := container.(ContainerInspector)
+
+	// Nothing is recorded until stats are enabled
+	_, err := container.Resolve("db")
+	require.NoError(t, err)
+	assert.Empty(t, inspector.GetResolutionStats())
+
+	inspector.EnableResolutionStats(true)
+	for i := 0; i < 3; i++ {
+		_, err := container.Resolve("repo")
+		require.NoError(t, err)
+	}
+	_, err = container.Resolve("broken")
+	require.Error(t, err)
+
+	stats := inspector.GetResolutionStats()
+	assert.Equal(t, int64(3), stats["repo"].Resolutions)
+	assert.Zero(t, stats["repo"].CacheHits)
+	assert.Equal(t, int64(3), stats["db"].Resolutions)
+	assert.Equal(t, int64(3), stats["db"].CacheHits)
+	assert.Equal(t, int64(1), stats["broken"].Errors)
+	assert.GreaterOrEqual(t, stats["repo"].TotalLatency, stats["repo"].MaxLatency)
+
+	// Resolving through a scope records on the container the service is registered on
+	_, err = container.CreateScope().Resolve("db")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), inspector.GetResolutionStats()["db"].CacheHits)
+
+	inspector.EnableResolutionStats(false)
+	assert.Empty(t, inspector.GetResolutionStats())
+}
+
+func TestDIContainer_ListServices(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterSingleton("db", func(c DIContainer) (interface{}, error) {
+		return "postgres", nil
+	}))
+	require.NoError(t, container.RegisterProvider(NewAsyncProvider("cache", func(c DIContainer, ctx context.Context) (interface{}, error) {
+		return "redis", nil
+	}, Singleton)))
+
+	// The module's "db" shadows the root one
+	moduleContainer := container.CreateModuleScope(NewModule("users", "1.0.0"))
+	require.NoError(t, moduleContainer.RegisterProvider(NewValueProvider("db", "sqlite")))
+	require.NoError(t, moduleContainer.RegisterScoped("repo", func(c DIContainer) (interface{}, error) {
+		return "repo", nil
+	}))
+
+	assert.Equal(t, []ServiceInfo{
+		{Name: "db", Lifetime: Singleton},
+		{Name: "repo", Lifetime: Scoped},
+		{Name: "cache", Lifetime: Singleton, Async: true, Depth: 1},
+	}, moduleContainer.(ContainerInspector).ListServices())
+}
+
+func TestDIContainer_ResolveOptional(t *testing.T) {
+	root := NewDIContainer()
+	require.NoError(t, root.RegisterProvider(NewValueProvider("mailer", "smtp")))
+	require.NoError(t, root.RegisterSingleton("broken", func(c DIContainer) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	}))
+	require.NoError(t, root.RegisterSingleton("needsPayments", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("payments")
+	}))
+
+	moduleContainer := NewModuleContainer(DefaultModule("users", "1.0.0"), root)
+	require.NoError(t, moduleContainer.Decorate("region", "eu"))
+	requestContainer := moduleContainer.CreateRequestScope()
+	requestContainer.DecorateRequest("userID", 42)
+	scope := NewScopedContainer(root)
+
+	containers := map[string]DIContainer{
+		"base":    root,
+		"module":  moduleContainer,
+		"request": requestContainer,
+		"scoped":  scope,
+		"bound":   &boundContainer{DIContainer: requestContainer, ctx: context.Background()},
+	}
+	for name, container := range containers {
+		t.Run(name, func(t *testing.T) {
+			service, found, err := container.ResolveOptional("mailer")
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "smtp", service)
+
+			// Not configured
+			service, found, err = container.ResolveOptional("payments")
+			assert.NoError(t, err)
+			assert.False(t, found)
+			assert.Nil(t, service)
+
+			// Configured but broken, including by a missing dependency
+			_, found, err = container.ResolveOptional("broken")
+			assert.True(t, found)
+			assert.ErrorContains(t, err, "connection refused")
+			_, found, err = container.ResolveOptional("needsPayments")
+			assert.True(t, found)
+			var notRegistered *ServiceNotRegisteredError
+			require.ErrorAs(t, err, &notRegistered)
+			assert.Equal(t, "payments", notRegistered.Name)
+		})
+	}
+
+	// Decorators and request data count as registered
+	region, found, err := requestContainer.ResolveOptional("region")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "eu", region)
+	userID, found, err := requestContainer.ResolveOptionalWithContext("userID", context.Background())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 42, userID)
+
+	// A closed scope fails rather than reporting the service missing
+	require.NoError(t, scope.Close())
+	_, found, err = scope.ResolveOptional("payments")
+	assert.True(t, found)
+	assert.ErrorIs(t, err, ErrScopeClosed)
+}
+
+func TestDIContainer_RegisterNilProvider(t *testing.T) {
+	container := NewDIContainer()
+
+	assert.NotPanics(t, func() {
+		assert.ErrorIs(t, container.RegisterProvider(nil), ErrProviderNil)
+		assert.ErrorIs(t, container.RegisterProvider((*ClassProvider)(nil)), ErrProviderNil)
+		assert.ErrorIs(t, container.RegisterProviderSingleton(nil), ErrProviderNil)
+		assert.ErrorIs(t, container.RegisterProviderTransient(nil), ErrProviderNil)
+		assert.ErrorIs(t, container.RegisterProviderScoped(nil), ErrProviderNil)
+		assert.ErrorIs(t, container.RegisterProviderInGroup("handlers", nil), ErrProviderNil)
+		assert.ErrorIs(t, container.OverrideProvider(nil), ErrProviderNil)
+		assert.ErrorIs(t, container.OverrideProvider((*ValueProvider)(nil)), ErrProviderNil)
+	})
+}
+
+func TestDIContainer_ValidateDependencies(t *testing.T) {
+	root := NewDIContainer()
+	require.NoError(t, root.RegisterProvider(NewValueProvider("db", "postgres")))
+
+	moduleContainer := root.CreateModuleScope(NewModule("users", "1.0.0"))
+	repository := NewFactoryProvider("userRepository", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("db")
+	}, Singleton)
+	repository.DependsOn = []string{"db"}
+	service := NewFactoryProvider("userService", func(c DIContainer) (interface{}, error) {
+		return c.Resolve("mailer")
+	}, Singleton)
+	service.DependsOn = []string{"userRepository", "mailer"}
+	require.NoError(t, moduleContainer.RegisterProvider(repository))
+	require.NoError(t, moduleContainer.RegisterProviderTransient(service))
+
+	inspector := moduleContainer.(ContainerInspector)
+	err := inspector.ValidateDependencies()
+	require.ErrorIs(t, err, ErrDependencyNotRegistered)
+	assert.Equal(t, "declared dependency is not registered: service 'userService' depends on 'mailer'", err.Error())
+
+	services := inspector.ListServices()
+	require.Len(t, services, 3)
+	assert.Equal(t, []string{"db"}, services[0].DependsOn)
+	assert.Equal(t, []string{"userRepository", "mailer"}, services[1].DependsOn)
+
+	require.NoError(t, root.RegisterProvider(NewValueProvider("mailer", "smtp")))
+	assert.NoError(t, inspector.ValidateDependencies())
+}
+
+func TestDIContainer_ValidatesWrappedValueProviders(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterProvider(NewValueProvider("service", &TestService{})))
+
+	for name, register := range map[string]func(provider Provider) error{
+		"RegisterProvider":          container.RegisterProvider,
+		"RegisterProviderSingleton": container.RegisterProviderSingleton,
+		"RegisterProviderTransient": container.RegisterProviderTransient,
+		"RegisterProviderScoped":    container.RegisterProviderScoped,
+		"OverrideProvider":          container.OverrideProvider,
+	} {
+		serviceName := "service"
+		if name != "OverrideProvider" {
+			serviceName = "nil" + name
+		}
+		err := register(NewValueProvider(serviceName, (*TestService)(nil)))
+		assert.ErrorContains(t, err, "value provider '"+serviceName+"' has a nil value", name)
+	}
+
+	service, err := container.Resolve("service")
+	require.NoError(t, err)
+	assert.NotNil(t, service)
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	readSeekerType   = reflect.TypeOf((*io.ReadSeeker)(nil)).Elem()
+	downloadInfoType = reflect.TypeOf(DownloadInfo{})
+)
+
+// DownloadInfo describes the content a Download handler returns
+type DownloadInfo struct {
+	Name        string    // File name offered to the client; also used to detect the content type
+	ModTime     time.Time // Last modification, for Last-Modified and conditional requests; zero to omit
+	ContentType string    // Overrides the type detected from Name or the content
+	Inline      bool      // Display in the browser instead of saving as an attachment
+}
+
+// Download registers a GET route serving a file with automatic controller injection
+// The handler takes *gin.Context followed by the dependencies to inject and returns
+// the content and its description,
+// e.g. func(c *gin.Context, files *FileController) (io.ReadSeeker, core.DownloadInfo)
+// It can authorize the request first: when it aborts the context or returns a nil
+// reader, nothing is served. Range requests are honored for resumable downloads, and
+// the content is closed after serving if it is an io.Closer
+func (r *EnhancedRouter) Download(config RouteConfig, handler interface{}) {
+	prefixedPath := r.applyPrefix(config.Path)
+	config.Path = prefixedPath
+	config.Method = http.MethodGet
+	mustValidateDownloadHandler(config, handler)
+
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.GET(prefixedPath, routeHandlers(config, r.withDownload(handler))...)
+}
+
+// Download registers a file download route in the group with automatic controller injection
+func (rg *EnhancedRouterGroup) Download(config RouteConfig, handler interface{}) {
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodGet
+	mustValidateDownloadHandler(config, handler)
+
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.GET(path, routeHandlers(config, rg.router.withDownload(handler))...)
+}
+
+// mustValidateDownloadHandler panics when the route's handler is not a valid
+// download handler
+func mustValidateDownloadHandler(config RouteConfig, handler interface{}) {
+	mustValidateHandler(config, handler)
+
+	handlerType := reflect.TypeOf(handler)
+	if handlerType.NumOut() != 2 || handlerType.Out(0) != readSeekerType || handlerType.Out(1) != downloadInfoType {
+		panic(fmt.Errorf("%s %s: %w: %s must return (io.ReadSeeker, core.DownloadInfo)",
+			config.Method, config.Path, ErrInvalidHandler, handlerType))
+	}
+}
+
+// withDownload resolves the handler's dependencies, calls the handler and serves the
+// content it returns
+func (r *EnhancedRouter) withDownload(handler interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handlerValue := reflect.ValueOf(handler)
+		handlerType := handlerValue.Type()
+
+		args := make([]reflect.Value, handlerType.NumIn())
+		args[0] = reflect.ValueOf(c)
+		if !r.resolveHandlerArgs(c, handlerType, args, 1) || !runPreHandlerHooks(c) {
+			return
+		}
+
+		results := handlerValue.Call(args)
+		content, _ := results[0].Interface().(io.ReadSeeker)
+		if closer, ok := content.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if c.IsAborted() || content == nil {
+			return
+		}
+
+		serveDownload(c, content, results[1].Interface().(DownloadInfo))
+	}
+}
+
+// serveDownload writes content with its download headers; http.ServeContent sets
+// Content-Length and Accept-Ranges, and answers Range and conditional requests
+func serveDownload(c *gin.Context, content io.ReadSeeker, info DownloadInfo) {
+	disposition := "attachment"
+	if info.Inline {
+		disposition = "inline"
+	}
+	if info.Name != "" {
+		if formatted := mime.FormatMediaType(disposition, map[string]string{"filename": info.Name}); formatted != "" {
+			disposition = formatted
+		}
+	}
+	c.Header("Content-Disposition", disposition)
+	if info.ContentType != "" {
+		c.Header("Content-Type", info.ContentType)
+	}
+
+	http.ServeContent(c.Writer, c.Request, info.Name, info.ModTime, content)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDownloadTestApp(t *testing.T) *DoffApp {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouterWithPrefix(app.GetEngine(), app.GetContainer(), "/files")
+	router.Download(RouteConfig{Path: ":name"}, func(c *gin.Context, controller *routeTestController) (io.ReadSeeker, DownloadInfo) {
+		if c.Param("name") != "report.txt" {
+			c.AbortWithStatus(http.StatusForbidden)
+			return nil, DownloadInfo{}
+		}
+		return strings.NewReader("0123456789"), DownloadInfo{Name: "report.txt"}
+	})
+	return app
+}
+
+func TestEnhancedRouter_DownloadServesFullContent(t *testing.T) {
+	app := newDownloadTestApp(t)
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/files/report.txt", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "0123456789", recorder.Body.String())
+	assert.Equal(t, "10", recorder.Header().Get("Content-Length"))
+	assert.Equal(t, "bytes", recorder.Header().Get("Accept-Ranges"))
+	assert.Equal(t, `attachment; filename=report.txt`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestEnhancedRouter_DownloadServesRange(t *testing.T) {
+	app := newDownloadTestApp(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/files/report.txt", nil)
+	request.Header.Set("Range", "bytes=2-5")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusPartialContent, recorder.Code)
+	assert.Equal(t, "2345", recorder.Body.String())
+	assert.Equal(t, "4", recorder.Header().Get("Content-Length"))
+	assert.Equal(t, "bytes 2-5/10", recorder.Header().Get("Content-Range"))
+}
+
+func TestEnhancedRouter_DownloadHandlerCanRefuse(t *testing.T) {
+	app := newDownloadTestApp(t)
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/files/secret.txt", nil))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+	assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+}
+
+func TestEnhancedRouter_DownloadRejectsInvalidHandler(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+
+	assert.Panics(t, func() {
+		router.Download(RouteConfig{Path: "/file"}, func(c *gin.Context, controller *routeTestController) {})
+	})
+}
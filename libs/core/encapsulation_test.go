@@ -370,4 +370,110 @@ func TestModuleContainerResolveWithContext(t *testing.T) {
 	})
 	_, err = childContainer.ResolveWithContext("private", ctx)
 	assert.Error(t, err)
-}
\ No newline at end of file
+}
+
+// TestEncapsulationViolationCounts tests the per-pair violation counters
+func TestEncapsulationViolationCounts(t *testing.T) {
+	originalMode := GetEncapsulationMode()
+	originalFile := encapsulationViolationLogger
+	defer func() {
+		SetEncapsulationMode(originalMode)
+		SetEncapsulationViolationLogger(originalFile)
+		ResetEncapsulationViolations()
+	}()
+
+	devNull, err := os.Open(os.DevNull)
+	assert.NoError(t, err)
+	defer devNull.Close()
+	SetEncapsulationViolationLogger(devNull)
+	ResetEncapsulationViolations()
+
+	// Disabled mode does not detect anything
+	SetEncapsulationMode(EncapsulationDisabled)
+	CheckEncapsulationViolation("orders", "billing", "invoiceRepo")
+	assert.Empty(t, GetEncapsulationViolations())
+
+	SetEncapsulationMode(EncapsulationWarn)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			CheckEncapsulationViolation("orders", "billing", "invoiceRepo")
+		}()
+	}
+	wg.Wait()
+
+	// Enforce mode still counts the rejected access
+	SetEncapsulationMode(EncapsulationEnforce)
+	CheckEncapsulationViolation("users", "billing", "invoiceRepo")
+
+	violations := GetEncapsulationViolations()
+	assert.Equal(t, map[string]int{
+		"orders->billing:invoiceRepo": 50,
+		"users->billing:invoiceRepo":  1,
+	}, violations)
+
+	// The returned map is a copy
+	violations["orders->billing:invoiceRepo"] = 0
+	assert.Equal(t, 50, GetEncapsulationViolations()["orders->billing:invoiceRepo"])
+
+	ResetEncapsulationViolations()
+	assert.Empty(t, GetEncapsulationViolations())
+}
+
+// TestReExportThroughImportChain tests a facade module passing an imported service through
+func TestReExportThroughImportChain(t *testing.T) {
+	originalMode := GetEncapsulationMode()
+	defer func() {
+		SetEncapsulationMode(originalMode)
+		ResetEncapsulationViolations()
+	}()
+	SetEncapsulationMode(EncapsulationEnforce)
+
+	newStringProvider := func(name string) Provider {
+		return NewFactoryProvider(name, func(container DIContainer) (interface{}, error) {
+			return name + "-value", nil
+		}, Singleton)
+	}
+
+	// database provides and exports "db"; facade re-exports it; app imports facade
+	database := NewModule("database", "1.0.0").
+		WithProviders(newStringProvider("db"), newStringProvider("pool")).
+		WithExports("db")
+	facade := NewModule("facade", "1.0.0").
+		WithImports(database).
+		WithExports("db")
+	app := NewModule("app", "1.0.0").WithImports(facade)
+
+	graph := NewModuleGraph()
+	assert.NoError(t, graph.AddModule(database))
+	assert.NoError(t, graph.AddModule(facade))
+	assert.NoError(t, graph.AddModule(app))
+	assert.Equal(t, database, facade.ReExportSource("db"))
+
+	assert.NoError(t, graph.ValidateExportAccess(app, "db"))
+	assert.Error(t, graph.ValidateExportAccess(app, "pool"))
+
+	// Only names the import exports can be re-exported
+	leaky := NewModule("leaky", "1.0.0").WithImports(database).WithExports("pool")
+	err := leaky.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exported provider 'pool' not found in module 'leaky'")
+
+	// The grandchild resolves the re-exported service through the chain
+	databaseContainer := NewModuleContainer(database, NewDIContainer())
+	for _, provider := range database.Providers {
+		assert.NoError(t, databaseContainer.RegisterProvider(provider))
+	}
+	facadeContainer := NewModuleContainer(facade, databaseContainer)
+	appContainer := NewModuleContainer(app, facadeContainer)
+
+	ctx := context.Background()
+	service, err := appContainer.ResolveWithContext("db", ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "db-value", service)
+
+	_, err = appContainer.ResolveWithContext("pool", ctx)
+	assert.Error(t, err)
+}
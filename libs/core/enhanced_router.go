@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -12,10 +14,15 @@ import (
 // ControllerFunc represents a function that receives an injected controller
 type ControllerFunc[T any] func(c *gin.Context, controller T)
 
+// ErrInvalidHandler is wrapped by the panic of route methods given a handler
+// withController cannot call
+var ErrInvalidHandler = newError("invalid route handler")
+
 // EnhancedRouter provides automatic controller injection with module prefix support
 type EnhancedRouter struct {
 	*Router
 	modulePrefix string // Current module's prefix for auto-prefixing
+	missingRequestContainer MissingRequestContainer // Policy when no request container is in context
 }
 
 // NewEnhancedRouter creates a new enhanced router
@@ -64,274 +71,414 @@ func (r *EnhancedRouter) applyPrefix(path string) string {
 func (r *EnhancedRouter) GET(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodGet
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.GET(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.GET(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // POST registers a POST route with automatic controller injection
 func (r *EnhancedRouter) POST(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodPost
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.POST(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.POST(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // PUT registers a PUT route with automatic controller injection
 func (r *EnhancedRouter) PUT(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodPut
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.PUT(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.PUT(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // PATCH registers a PATCH route with automatic controller injection
 func (r *EnhancedRouter) PATCH(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodPatch
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.PATCH(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.PATCH(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // DELETE registers a DELETE route with automatic controller injection
 func (r *EnhancedRouter) DELETE(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodDelete
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.DELETE(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.DELETE(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // OPTIONS registers an OPTIONS route with automatic controller injection
 func (r *EnhancedRouter) OPTIONS(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodOptions
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.OPTIONS(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.OPTIONS(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // HEAD registers a HEAD route with automatic controller injection
 func (r *EnhancedRouter) HEAD(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = http.MethodHead
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.HEAD(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.HEAD(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // Any registers a route that matches all HTTP methods with automatic controller injection
 func (r *EnhancedRouter) Any(config RouteConfig, handler interface{}) {
 	prefixedPath := r.applyPrefix(config.Path)
 	config.Path = prefixedPath
+	config.Method = MethodAny
+	mustValidateHandler(config, handler)
 
-	r.triggerOnRoute(&config)
-	r.engine.Any(prefixedPath, r.withController(handler))
+	if !r.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	r.engine.Any(prefixedPath, routeHandlers(config, r.withController(handler))...)
 }
 
 // Group creates a new route group with enhanced capabilities
 func (r *EnhancedRouter) Group(relativePath string, handlers ...gin.HandlerFunc) *EnhancedRouterGroup {
-	fullPrefix := r.applyPrefix(relativePath)
-	group := r.engine.Group(relativePath, handlers...)
-
+	// The gin group carries the module prefix, so its base path is the routes' full prefix
 	return &EnhancedRouterGroup{
-		group:       group,
-		router:      r,
-		groupPrefix: fullPrefix,  // Track full prefix for this group
+		group:  r.engine.Group(r.applyPrefix(relativePath), handlers...),
+		router: r,
 	}
 }
 
 // withController creates a middleware that automatically injects the controller
+// The handler's first parameter must be *gin.Context; every other parameter is
+// resolved from the request container (or the router's container) by type,
+// e.g. func(c *gin.Context, users *UserController, logger *Logger)
+// An unregistered struct parameter is bound from the path parameters instead, e.g.
+// func(c *gin.Context, users *UserController, params struct{ ID string `uri:"id"` })
+// Parameters resolve with c.Request.Context(), so a cancelled request also
+// cancels the async providers building them
 func (r *EnhancedRouter) withController(handler interface{}) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Route methods validate at registration; this guards handlers wired up
+		// some other way
+		if err := validateHandler(handler); err != nil {
+			AbortWithError(c, ErrInternal.WithMessage("Invalid handler signature").WithCause(err))
+			return
+		}
+
 		// Get handler value and type
 		handlerValue := reflect.ValueOf(handler)
 		handlerType := handlerValue.Type()
 
-		// Check if it's a function with the right signature
-		if handlerType.Kind() != reflect.Func || handlerType.NumIn() != 2 {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid handler signature",
-			})
+		args := make([]reflect.Value, handlerType.NumIn())
+		args[0] = reflect.ValueOf(c)
+		if !r.resolveHandlerArgs(c, handlerType, args, 1) || !runPreHandlerHooks(c) {
 			return
 		}
 
-		// Get controller type from the handler's second parameter
-		controllerType := handlerType.In(1)
+		// Call the handler with injected dependencies
+		handlerValue.Call(args)
+	}
+}
+
+// validateHandler checks the handler is a func withController can call: *gin.Context
+// first, then at least one parameter to inject
+func validateHandler(handler interface{}) error {
+	handlerValue := reflect.ValueOf(handler)
+	if !handlerValue.IsValid() || (handlerValue.Kind() == reflect.Func && handlerValue.IsNil()) {
+		return fmt.Errorf("%w: handler is nil", ErrInvalidHandler)
+	}
+
+	handlerType := handlerValue.Type()
+	if handlerType.Kind() != reflect.Func {
+		return fmt.Errorf("%w: %s is not a func", ErrInvalidHandler, handlerType)
+	}
+	if handlerType.NumIn() < 2 || handlerType.In(0) != reflect.TypeOf((*gin.Context)(nil)) {
+		return fmt.Errorf("%w: %s must take *gin.Context followed by the dependencies to inject", ErrInvalidHandler, handlerType)
+	}
+	return nil
+}
+
+// mustValidateHandler panics when the route's handler is invalid, so a
+// misconfigured route fails at startup rather than on its first request
+func mustValidateHandler(config RouteConfig, handler interface{}) {
+	if err := validateHandler(handler); err != nil {
+		panic(fmt.Errorf("%s %s: %w", config.Method, config.Path, err))
+	}
+}
+
+// triggerOnHandlerRoute is triggerOnRoute, recording the parameters withController
+// injects into handler so that PluginManager.Validate can check them
+func (r *EnhancedRouter) triggerOnHandlerRoute(config *RouteConfig, handler interface{}) bool {
+	handlerType := reflect.TypeOf(handler)
+	params := make([]reflect.Type, 0, handlerType.NumIn()-1)
+	for i := 1; i < handlerType.NumIn(); i++ {
+		params = append(params, handlerType.In(i))
+	}
+	return r.recordAndTriggerRoute(config, params)
+}
+
+// SetMissingRequestContainer sets how handlers resolve their parameters when no
+// request container is in context; routers from the app default to
+// AppOptions.MissingRequestContainer, others to FallbackToContainer
+func (r *EnhancedRouter) SetMissingRequestContainer(policy MissingRequestContainer) {
+	r.missingRequestContainer = policy
+}
 
-		// Get request container from context
-		var service interface{}
-		var err error
+// handlerContainer returns the request container when present, otherwise applies
+// the router's MissingRequestContainer policy
+func (r *EnhancedRouter) handlerContainer(c *gin.Context) (DIContainer, error) {
+	return requestContainerFor(c, r.missingRequestContainer, r.container)
+}
 
-		if rc, exists := c.Get("requestContainer"); exists {
-			// Resolve from request container
-			requestContainer := rc.(*RequestContainer)
-			typeName := controllerType.String()
-			service, err = requestContainer.Resolve(typeName)
+// resolveHandlerArgs resolves the handler parameters from index first onwards into args
+// It aborts with a 500 API error and returns false when a dependency cannot be resolved
+// or the request container is required but missing, a 400 when the path parameters
+// do not bind, and aborts with ErrResolutionTimeout (503) when the route's timeout
+// expires while resolving
+func (r *EnhancedRouter) resolveHandlerArgs(c *gin.Context, handlerType reflect.Type, args []reflect.Value, first int) bool {
+	container, err := r.handlerContainer(c)
+	if err != nil {
+		AbortWithError(c, ErrInternal.WithMessage(fmt.Sprintf("Failed to resolve controller: %v", err)).WithCause(err))
+		return false
+	}
+	for i := first; i < handlerType.NumIn(); i++ {
+		paramType := handlerType.In(i)
+		if isMultipartForm(paramType) {
+			form, err := bindMultipartForm(c, paramType)
 			if err != nil {
-				// Try with naming convention
-				typeName = toServiceName(controllerType)
-				service, err = requestContainer.Resolve(typeName)
+				AbortWithError(c, err)
+				return false
 			}
-		} else {
-			// Fallback to global container (should not happen with proper middleware setup)
-			typeName := controllerType.String()
-			service, err = r.container.Resolve(typeName)
-			if err != nil {
-				// Try with naming convention
-				typeName = toServiceName(controllerType)
-				service, err = r.container.Resolve(typeName)
+			args[i] = form
+			continue
+		}
+		if isURIParams(container, paramType) {
+			params := reflect.New(paramType)
+			if err := c.ShouldBindUri(params.Interface()); err != nil {
+				AbortWithError(c, ErrBadRequest.WithMessage(fmt.Sprintf("Invalid path parameters: %v", err)).WithCause(err))
+				return false
 			}
+			args[i] = params.Elem()
+			continue
 		}
 
+		arg, err := resolveHandlerParam(c.Request.Context(), container, paramType)
+		if err != nil && errors.Is(err, context.DeadlineExceeded) && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+			// The route's timeout expired before the handler could run
+			AbortWithError(c, fmt.Errorf("%w: %s %s: parameter %d (%s): %w",
+				ErrResolutionTimeout, c.Request.Method, c.FullPath(), i, paramType, err))
+			return false
+		}
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Failed to resolve controller: %v", err),
-			})
-			return
+			AbortWithError(c, ErrInternal.WithMessage(fmt.Sprintf("Failed to resolve controller: parameter %d (%s): %v", i, paramType, err)).WithCause(err))
+			return false
 		}
+		args[i] = arg
+	}
+	return true
+}
 
-		// Execute pre-handler hooks
-		if app, exists := c.Get("app"); exists {
-			if doffApp, ok := app.(*DoffApp); ok {
-				doffApp.pluginManager.GetLifecycleManager().ExecutePreHandler(c)
-				if c.IsAborted() {
-					return
-				}
+// isURIParams reports whether a handler parameter is bound from the path parameters
+// rather than injected: a struct value, e.g. struct{ ID string `uri:"id"` }, that is
+// not registered under any of the names of serviceNamesForType
+func isURIParams(container DIContainer, paramType reflect.Type) bool {
+	if paramType.Kind() != reflect.Struct {
+		return false
+	}
+	_, registered := serviceNameForType(container, paramType)
+	return !registered
+}
+
+// runPreHandlerHooks executes the app's PreHandler hooks and reports whether the request may continue
+func runPreHandlerHooks(c *gin.Context) bool {
+	if app, exists := c.Get("app"); exists {
+		if doffApp, ok := app.(*DoffApp); ok {
+			doffApp.pluginManager.GetLifecycleManager().ExecutePreHandler(c)
+			if c.IsAborted() {
+				return false
 			}
 		}
+	}
+	return true
+}
 
-		// Call the handler with injected controller
-		args := []reflect.Value{
-			reflect.ValueOf(c),
-			reflect.ValueOf(service),
+// resolveHandlerParam resolves a handler parameter by the names of serviceNamesForType,
+// in order: the container's naming strategy, or its type name then its short name
+// Resolution uses the request context, so a route timeout also cancels async providers
+func resolveHandlerParam(ctx context.Context, container DIContainer, paramType reflect.Type) (reflect.Value, error) {
+	var service interface{}
+	var err error
+	for _, name := range serviceNamesForType(container, paramType) {
+		service, err = container.ResolveWithContext(name, ctx)
+		// A registered service that failed to build (e.g. cancelled with the request)
+		// keeps its own error
+		if err == nil || container.Has(name) {
+			break
 		}
-		handlerValue.Call(args)
 	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if service == nil {
+		return reflect.Zero(paramType), nil
+	}
+
+	value := reflect.ValueOf(service)
+	if !value.Type().AssignableTo(paramType) {
+		return reflect.Value{}, fmt.Errorf("resolved service is of type %s", value.Type())
+	}
+	return value, nil
 }
 
 // EnhancedRouterGroup provides enhanced route groups
 type EnhancedRouterGroup struct {
-	group       *gin.RouterGroup
-	router      *EnhancedRouter
-	groupPrefix string  // Full prefix for this group
+	group  *gin.RouterGroup
+	router *EnhancedRouter
 }
 
 // Group creates a nested enhanced route group
 func (rg *EnhancedRouterGroup) Group(relativePath string, handlers ...gin.HandlerFunc) *EnhancedRouterGroup {
-	fullPrefix := rg.groupPrefix
-	if relativePath != "" {
-		fullPrefix = fullPrefix + "/" + strings.TrimPrefix(relativePath, "/")
-	}
-	group := rg.group.Group(relativePath, handlers...)
-
 	return &EnhancedRouterGroup{
-		group:       group,
-		router:      rg.router,
-		groupPrefix: fullPrefix,
+		group:  rg.group.Group(relativePath, handlers...),
+		router: rg.router,
 	}
 }
 
-// applyGroupPrefix applies group prefix to a path
-func (rg *EnhancedRouterGroup) applyGroupPrefix(path string) string {
-	if rg.groupPrefix == "" {
-		return path
-	}
-
-	if !strings.HasPrefix(path, "/") {
-		return rg.groupPrefix + "/" + path
-	}
-
-	// Path is already absolute, check if it already has the prefix
-	if strings.HasPrefix(path, rg.groupPrefix) {
-		return path
-	}
-
-	// Return path as is (absolute path without group prefix)
-	return path
-}
-
 // GET registers a GET route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) GET(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodGet
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.GET(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.GET(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // POST registers a POST route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) POST(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPost
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.POST(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.POST(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // PUT registers a PUT route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) PUT(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPut
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.PUT(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.PUT(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // PATCH registers a PATCH route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) PATCH(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPatch
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.PATCH(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.PATCH(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // DELETE registers a DELETE route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) DELETE(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodDelete
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.DELETE(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.DELETE(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // OPTIONS registers an OPTIONS route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) OPTIONS(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodOptions
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.OPTIONS(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.OPTIONS(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // HEAD registers a HEAD route in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) HEAD(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodHead
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.HEAD(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.HEAD(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // Any registers a route that matches all HTTP methods in the group with automatic controller injection
 func (rg *EnhancedRouterGroup) Any(config RouteConfig, handler interface{}) {
-	// Apply group prefix to the path
-	prefixedPath := rg.applyGroupPrefix(config.Path)
-	config.Path = prefixedPath
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = MethodAny
+	mustValidateHandler(config, handler)
 
-	rg.router.triggerOnRoute(&config)
-	rg.group.Any(config.Path, rg.router.withController(handler))
+	if !rg.router.triggerOnHandlerRoute(&config, handler) {
+		return
+	}
+	rg.group.Any(path, routeHandlers(config, rg.router.withController(handler))...)
 }
 
 // Use adds middleware to the group
@@ -351,5 +498,7 @@ func (rg *EnhancedRouterGroup) StaticFile(relativePath, filepath string) {
 
 // Helper function to get the enhanced router from DoffApp
 func (d *DoffApp) GetEnhancedRouter() *EnhancedRouter {
-	return NewEnhancedRouter(d.server, d.container)
+	router := NewEnhancedRouter(d.server, d.container)
+	router.missingRequestContainer = d.missingRequestContainer
+	return router
 }
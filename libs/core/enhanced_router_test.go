@@ -0,0 +1,479 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routeTestController is injected into enhanced router handlers
+type routeTestController struct{}
+
+func TestEnhancedRouter_RouteMiddlewares(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var order []string
+	resolved := 0
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		resolved++
+		return &routeTestController{}, nil
+	})
+
+	app.GetPluginManager().GetLifecycleManager().AddHook(&LifecycleHookFunc{
+		OnRequestFunc: func(c *gin.Context) { order = append(order, "OnRequest") },
+	})
+
+	requireToken := func(c *gin.Context) {
+		order = append(order, "requireToken")
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+	tagRequest := func(c *gin.Context) {
+		order = append(order, "tagRequest")
+	}
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{
+		Path:        "/secure",
+		Middlewares: []gin.HandlerFunc{requireToken, tagRequest},
+	}, func(c *gin.Context, controller *routeTestController) {
+		order = append(order, "Handler")
+		c.Status(http.StatusOK)
+	})
+
+	// Middleware runs in order after OnRequest and before the handler
+	request := httptest.NewRequest(http.MethodGet, "/secure", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"OnRequest", "requireToken", "tagRequest", "Handler"}, order)
+	assert.Equal(t, 1, resolved)
+
+	// Aborting skips the remaining middleware and controller resolution
+	order = nil
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/secure", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, []string{"OnRequest", "requireToken"}, order)
+	assert.Equal(t, 1, resolved)
+}
+
+// createUserSchema is the request body schema used by the validation tests
+type createUserSchema struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Age   int    `json:"age" binding:"gte=0"`
+}
+
+// validationResponse mirrors the 400 error envelope returned on schema validation failure
+type validationResponse struct {
+	Error struct {
+		Code    string       `json:"code"`
+		Message string       `json:"message"`
+		Details []FieldError `json:"details"`
+	} `json:"error"`
+}
+
+func newSchemaTestApp(t *testing.T, handled *interface{}) *DoffApp {
+	t.Helper()
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.POST(RouteConfig{
+		Path:            "/users",
+		SchemaValidator: &createUserSchema{},
+	}, func(c *gin.Context, controller *routeTestController) {
+		*handled, _ = GetValidatedBody(c)
+		c.Status(http.StatusCreated)
+	})
+	return app
+}
+
+func postJSON(app *DoffApp, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestEnhancedRouter_SchemaValidatorValidBody(t *testing.T) {
+	var handled interface{}
+	app := newSchemaTestApp(t, &handled)
+
+	recorder := postJSON(app, "/users", `{"name":"Ada","email":"ada@example.com","age":36}`)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	require.IsType(t, &createUserSchema{}, handled)
+	assert.Equal(t, &createUserSchema{Name: "Ada", Email: "ada@example.com", Age: 36}, handled)
+}
+
+func TestEnhancedRouter_SchemaValidatorMissingRequiredFields(t *testing.T) {
+	var handled interface{}
+	app := newSchemaTestApp(t, &handled)
+
+	recorder := postJSON(app, "/users", `{"email":"not-an-email"}`)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Nil(t, handled, "handler must not run when validation fails")
+
+	var response validationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "bad_request", response.Error.Code)
+	assert.Equal(t, "Validation failed", response.Error.Message)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+	}, response.Error.Details)
+}
+
+func TestEnhancedRouter_SchemaValidatorTypeMismatch(t *testing.T) {
+	var handled interface{}
+	app := newSchemaTestApp(t, &handled)
+
+	recorder := postJSON(app, "/users", `{"name":"Ada","email":"ada@example.com","age":"thirty"}`)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Nil(t, handled)
+
+	var response validationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Error.Details, 1)
+	assert.Equal(t, "age", response.Error.Details[0].Field)
+	assert.Equal(t, "type", response.Error.Details[0].Rule)
+	assert.Equal(t, "expected int but got string", response.Error.Details[0].Message)
+}
+
+func TestEnhancedRouter_SchemaValidatorMalformedJSON(t *testing.T) {
+	var handled interface{}
+	app := newSchemaTestApp(t, &handled)
+
+	recorder := postJSON(app, "/users", `{"name":`)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Nil(t, handled)
+	assert.Contains(t, recorder.Body.String(), "Invalid request body")
+}
+
+// routeTestAuditLog is a second dependency injected alongside the controller
+type routeTestAuditLog struct {
+	entries []string
+}
+
+func TestEnhancedRouter_MultipleInjectedDependencies(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	audit := &routeTestAuditLog{}
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+	// Registered under the naming convention rather than the full type name
+	app.GetContainer().RegisterSingleton("routeTestAuditLog", func(c DIContainer) (interface{}, error) {
+		return audit, nil
+	})
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/audit"}, func(c *gin.Context, controller *routeTestController, log *routeTestAuditLog) {
+		require.NotNil(t, controller)
+		log.entries = append(log.entries, c.Request.URL.Path)
+		c.Status(http.StatusOK)
+	})
+	router.GET(RouteConfig{Path: "/missing"}, func(c *gin.Context, controller *routeTestController, missing *TestService) {
+		t.Fatal("handler must not run when a dependency fails to resolve")
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"/audit"}, audit.entries)
+
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "parameter 2 (*core.TestService)")
+}
+
+// blockingAsyncProvider registers an async TestService provider that blocks until
+// its context is done and reports the context error on observed
+func blockingAsyncProvider(t *testing.T, container DIContainer, started chan<- struct{}, observed chan<- error) {
+	t.Helper()
+	require.NoError(t, container.RegisterProvider(NewAsyncProvider("*core.TestService", func(c DIContainer, ctx context.Context) (interface{}, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			observed <- ctx.Err()
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			observed <- nil
+			return &TestService{}, nil
+		}
+	}, Transient)))
+}
+
+// serveCancelled serves a request whose context is cancelled once the provider starts
+func serveCancelled(app *DoffApp, path string, started <-chan struct{}) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+	return recorder
+}
+
+func TestEnhancedRouter_RequestCancellationReachesAsyncProvider(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	started, observed := make(chan struct{}), make(chan error, 1)
+	blockingAsyncProvider(t, app.GetContainer(), started, observed)
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/slow"}, func(c *gin.Context, service *TestService) {
+		t.Fatal("handler must not run when its provider is cancelled")
+	})
+
+	recorder := serveCancelled(app, "/slow", started)
+	assert.ErrorIs(t, <-observed, context.Canceled)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "context canceled")
+}
+
+func TestEnhancedRouter_InvalidHandlerPanicsAtRegistration(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+
+	var nilHandler func(c *gin.Context, controller *routeTestController)
+	tests := []struct {
+		name    string
+		handler interface{}
+		message string
+	}{
+		{"nil", nil, "handler is nil"},
+		{"nil func", nilHandler, "handler is nil"},
+		{"not a func", &routeTestController{}, "*core.routeTestController is not a func"},
+		{"context only", func(c *gin.Context) {}, "must take *gin.Context followed by"},
+		{"context not first", func(controller *routeTestController, c *gin.Context) {}, "must take *gin.Context followed by"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "registration must panic with an error")
+				assert.ErrorIs(t, err, ErrInvalidHandler)
+				assert.ErrorContains(t, err, "GET /invalid: ")
+				assert.ErrorContains(t, err, tt.message)
+			}()
+			router.GET(RouteConfig{Path: "/invalid"}, tt.handler)
+		})
+	}
+
+	// The failed registrations left nothing behind, so the route is still free
+	assert.NotPanics(t, func() {
+		router.GET(RouteConfig{Path: "/invalid"}, func(c *gin.Context, controller *routeTestController) {})
+	})
+	assert.Len(t, app.GetRoutes(), 1)
+}
+
+func TestEnhancedRouter_BindsPathParameters(t *testing.T) {
+	type orderParams struct {
+		ID   int    `uri:"id" binding:"required"`
+		Item string `uri:"item"`
+	}
+
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/orders/:id/items/:item"}, func(c *gin.Context, controller *routeTestController, params orderParams) {
+		require.NotNil(t, controller)
+		c.JSON(http.StatusOK, gin.H{"id": params.ID, "item": params.Item})
+	})
+	router.GET(RouteConfig{Path: "/users/:id"}, func(c *gin.Context, params struct {
+		ID string `uri:"id"`
+	}) {
+		c.String(http.StatusOK, params.ID)
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders/42/items/book", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"id":42,"item":"book"}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/ana", nil))
+	assert.Equal(t, "ana", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders/abc/items/book", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid path parameters")
+}
+
+func TestEnhancedRouter_MissingRequestContainer(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	require.NoError(t, app.DecorateRequestFactory("*core.TestService", func(c *gin.Context) interface{} {
+		return &TestService{Value: c.GetHeader("X-Tenant")}
+	}))
+
+	serve := func(policy MissingRequestContainer, path string) *httptest.ResponseRecorder {
+		router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+		router.SetMissingRequestContainer(policy)
+		router.GET(RouteConfig{Path: path}, func(c *gin.Context, tenant *TestService) {
+			_, stored := GetRequestContainer(c)
+			assert.True(t, stored)
+			c.String(http.StatusOK, tenant.Value)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set("X-Tenant", "acme")
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// The root container knows nothing of request decorators
+	recorder := serve(FallbackToContainer, "/fallback")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "parameter 1 (*core.TestService)")
+
+	recorder = serve(CreateRequestContainer, "/create")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "acme", recorder.Body.String())
+
+	recorder = serve(RequireRequestContainer, "/require")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "request container middleware not installed")
+}
+
+func TestEnhancedRouter_CreatedRequestContainerIsDisposed(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var closed []string
+	require.NoError(t, app.GetContainer().RegisterScoped("*core.unitOfWork", func(c DIContainer) (interface{}, error) {
+		return &unitOfWork{disposableService: &disposableService{name: "uow", closed: &closed}}, nil
+	}))
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.SetMissingRequestContainer(CreateRequestContainer)
+	router.GET(RouteConfig{Path: "/orders"}, func(c *gin.Context, uow *unitOfWork) {
+		assert.Empty(t, closed)
+		c.Status(http.StatusOK)
+	})
+
+	for range 2 {
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/orders", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, closed, 1, "the request's Scoped instance is closed when it ends")
+		closed = nil
+	}
+}
+
+func TestEnhancedRouter_ValidateReportsUnresolvableParameters(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	require.NoError(t, app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	}))
+	require.NoError(t, app.DecorateRequest("*core.TestService", &TestService{}))
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/ok/:id"}, func(c *gin.Context, controller *routeTestController, tenant *TestService, params struct {
+		ID string `uri:"id"`
+	}) {
+	})
+	router.GET(RouteConfig{Path: "/audit"}, func(c *gin.Context, controller *routeTestController, log *routeTestAuditLog) {})
+	router.POST(RouteConfig{Path: "/reset"}, func(c *gin.Context, missing *TestService2) {})
+
+	err := app.Validate()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnresolvableHandlerParam)
+	assert.Contains(t, err.Error(), "GET /audit: parameter 2 (*core.routeTestAuditLog)")
+	assert.Contains(t, err.Error(), "POST /reset: parameter 1 (*core.TestService2)")
+	assert.NotContains(t, err.Error(), "/ok")
+
+	require.NoError(t, app.GetContainer().RegisterSingleton("routeTestAuditLog", func(c DIContainer) (interface{}, error) {
+		return &routeTestAuditLog{}, nil
+	}))
+	require.NoError(t, app.GetContainer().RegisterSingleton("TestService2", func(c DIContainer) (interface{}, error) {
+		return &TestService2{}, nil
+	}))
+	assert.NoError(t, app.Validate())
+}
+
+func TestEnhancedRouterGroup_ServesRecordedPaths(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("billing", "1.0.0").WithPrefix("/billing"))))
+
+	handler := func(c *gin.Context, controller *routeTestController) {
+		c.String(http.StatusOK, c.FullPath())
+	}
+	users := NewEnhancedRouter(app.GetEngine(), app.GetContainer()).Group("/users")
+	users.GET(RouteConfig{Path: "list"}, handler)
+	users.Group("/admins").POST(RouteConfig{Path: "/:id"}, handler)
+	app.GetPluginManager().GetEnhancedRouterForModule("billing").Group("invoices").GET(RouteConfig{Path: ""}, handler)
+
+	var recorded []string
+	for _, route := range app.GetRoutes() {
+		recorded = append(recorded, route.Method+" "+route.Path)
+	}
+	assert.ElementsMatch(t, []string{"GET /users/list", "POST /users/admins/:id", "GET /billing/invoices"}, recorded)
+
+	for method, path := range map[string]string{
+		http.MethodGet:  "/users/list",
+		http.MethodPost: "/users/admins/7",
+	} {
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code, path)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/billing/invoices", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "/billing/invoices", recorder.Body.String())
+}
+
+func TestEnhancedRouter_ResolutionFailureUsesErrorEnvelope(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var hookErrors []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrors = append(hookErrors, err)
+	}))
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/unresolvable"}, func(c *gin.Context, controller *routeTestController) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unresolvable", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var body struct {
+		Error APIError `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, ErrInternal.Code, body.Error.Code)
+	assert.Contains(t, body.Error.Message, "Failed to resolve controller: parameter 1 (*core.routeTestController)")
+	assert.Len(t, hookErrors, 1)
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Paths of the built-in health endpoints registered by AppOptions.HealthCheck
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// readinessTimeout bounds the time all readiness checks may take for one request
+const readinessTimeout = 5 * time.Second
+
+// ReadinessChecker is implemented by plugins, or by singleton services they provide,
+// that can report whether they are ready to serve traffic (e.g. a database connection)
+type ReadinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+// ReadinessStatus is the result of one readiness check in the /readyz body
+type ReadinessStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readinessContributor is a named ReadinessChecker
+type readinessContributor struct {
+	name    string
+	checker ReadinessChecker
+}
+
+// registerHealthRoutes registers the public liveness and readiness endpoints
+func (d *DoffApp) registerHealthRoutes() {
+	public := false
+	router := d.GetRouter()
+
+	// Skipped optional plugins degrade the app without making it unhealthy
+	router.GET(RouteConfig{Path: LivenessPath, IsAuth: &public}, func(c *gin.Context, container DIContainer) {
+		if skipped := d.SkippedPlugins(); len(skipped) > 0 {
+			c.JSON(http.StatusOK, gin.H{"status": "degraded", "skippedPlugins": skipped})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	router.GET(RouteConfig{Path: ReadinessPath, IsAuth: &public}, func(c *gin.Context, container DIContainer) {
+		if d.pluginManager == nil || !d.pluginManager.IsInitialized() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "starting",
+				"checks": []ReadinessStatus{},
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+
+		ready, checks := d.checkReadiness(ctx)
+		if !ready {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "checks": checks})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": checks})
+	})
+}
+
+// checkReadiness runs every readiness contributor and reports whether all succeeded
+func (d *DoffApp) checkReadiness(ctx context.Context) (bool, []ReadinessStatus) {
+	ready := true
+	checks := []ReadinessStatus{}
+
+	for _, contributor := range d.readinessContributors() {
+		status := ReadinessStatus{Name: contributor.name, Status: "ok"}
+		if err := contributor.checker.Ready(ctx); err != nil {
+			ready = false
+			status.Status = "failed"
+			status.Error = err.Error()
+		}
+		checks = append(checks, status)
+	}
+
+	return ready, checks
+}
+
+// readinessContributors collects plugins implementing ReadinessChecker and the
+// singleton module providers whose instances implement it, sorted by name
+// Providers are named "module/service"
+func (d *DoffApp) readinessContributors() []readinessContributor {
+	var contributors []readinessContributor
+
+	for name, plugin := range d.pluginManager.GetPlugins() {
+		if checker, ok := plugin.(ReadinessChecker); ok {
+			contributors = append(contributors, readinessContributor{name: name, checker: checker})
+		}
+	}
+
+	for _, module := range d.pluginManager.GetModuleGraph().GetAllModules() {
+		for _, provider := range module.Providers {
+			if provider.GetLifetime() != Singleton {
+				continue
+			}
+
+			instance, err := d.container.Resolve(provider.GetName())
+			if err != nil {
+				continue
+			}
+			if checker, ok := instance.(ReadinessChecker); ok {
+				contributors = append(contributors, readinessContributor{
+					name:    module.Name + "/" + provider.GetName(),
+					checker: checker,
+				})
+			}
+		}
+	}
+
+	sort.Slice(contributors, func(i, j int) bool {
+		return contributors[i].name < contributors[j].name
+	})
+	return contributors
+}
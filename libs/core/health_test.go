@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readyPlugin is a plugin reporting readiness itself
+type readyPlugin struct {
+	*moduleTestPlugin
+}
+
+func (p *readyPlugin) Ready(ctx context.Context) error { return nil }
+
+// readinessService is a provider instance reporting readiness
+type readinessService struct {
+	err error
+}
+
+func (s *readinessService) Ready(ctx context.Context) error { return s.err }
+
+func serveHealth(app *DoffApp, path string) (int, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+	return recorder.Code, body
+}
+
+func TestHealthCheck_Endpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:          "health-test",
+		Mode:          gin.TestMode,
+		HealthCheck:   true,
+		Authenticator: &fakeAuthenticator{validToken: "secret"},
+	}).(*DoffApp)
+
+	database := &readinessService{}
+	module := NewModule("database", "1.0.0").
+		WithProviders(NewValueProvider("db", database))
+	require.NoError(t, app.RegisterPlugin(&readyPlugin{moduleTestPlugin: newModuleTestPlugin(module)}))
+
+	// Liveness is public and always up
+	code, body := serveHealth(app, LivenessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body["status"])
+
+	// Not ready until plugins are initialized
+	code, body = serveHealth(app, ReadinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "starting", body["status"])
+
+	require.NoError(t, app.GetPluginManager().InitializePlugins())
+
+	code, body = serveHealth(app, ReadinessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "database", "status": "ok"},
+		map[string]interface{}{"name": "database/db", "status": "ok"},
+	}, body["checks"])
+
+	database.err = errors.New("connection refused")
+	code, body = serveHealth(app, ReadinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not ready", body["status"])
+	assert.Contains(t, body["checks"], map[string]interface{}{
+		"name":   "database/db",
+		"status": "failed",
+		"error":  "connection refused",
+	})
+}
+
+func TestHealthCheck_DisabledByDefault(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	code, _ := serveHealth(app, LivenessPath)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestHealthCheck_ReportsSkippedPlugins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{Name: "health-test", Mode: gin.TestMode, HealthCheck: true}).(*DoffApp)
+	var log []string
+	app.RegisterOptionalPlugin(&unconfiguredPlugin{orderedPlugin: newOrderedPlugin("payments", &log)})
+
+	code, body := serveHealth(app, LivenessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "degraded", body["status"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"name":  "payments",
+		"error": "plugin registration failed: 'payments': PAYMENTS_API_KEY is not set",
+	}}, body["skippedPlugins"])
+}
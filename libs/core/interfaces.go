@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Interface resolution errors
+var (
+	// ErrNoImplementation is returned when resolving an interface no service is
+	// declared to implement
+	ErrNoImplementation = newError("no service is declared to implement the interface")
+	// ErrAmbiguousImplementation is returned when resolving an interface several
+	// services are declared to implement
+	ErrAmbiguousImplementation = newError("several services are declared to implement the interface")
+)
+
+// InterfaceContainer is implemented by containers indexing services by the interfaces
+// they are declared to implement; the default container is, and containers created
+// from it (module, request and scoped containers) fall back to their parent's index
+type InterfaceContainer interface {
+	// BindInterface declares that the service registered as name implements iface
+	BindInterface(iface reflect.Type, name string) error
+	// InterfaceImplementations returns the services declared to implement iface, in
+	// declaration order; a container with none of its own returns its parent's
+	InterfaceImplementations(iface reflect.Type) []string
+}
+
+// BindInterface declares that the service registered as name implements iface
+// Whether it does is checked when the service is resolved through the interface
+func (c *diContainer) BindInterface(iface reflect.Type, name string) error {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("cannot bind service '%s': %v is not an interface type", name, iface)
+	}
+	if !c.Has(name) {
+		return fmt.Errorf("cannot bind service '%s' to %s: service is not registered", name, iface)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interfaces == nil {
+		c.interfaces = make(map[reflect.Type][]string)
+	}
+	if !slices.Contains(c.interfaces[iface], name) {
+		c.interfaces[iface] = append(c.interfaces[iface], name)
+	}
+	return nil
+}
+
+// InterfaceImplementations returns the services declared to implement iface
+func (c *diContainer) InterfaceImplementations(iface reflect.Type) []string {
+	c.mu.RLock()
+	names := slices.Clone(c.interfaces[iface])
+	c.mu.RUnlock()
+
+	if len(names) == 0 && c.parent != nil {
+		return interfaceImplementations(c.parent, iface)
+	}
+	return names
+}
+
+// BindInterface declares the binding on the container the factory resolves from
+func (b *boundContainer) BindInterface(iface reflect.Type, name string) error {
+	return bindInterface(b.DIContainer, iface, name)
+}
+
+// InterfaceImplementations returns the implementations known to the container the
+// factory resolves from
+func (b *boundContainer) InterfaceImplementations(iface reflect.Type) []string {
+	return interfaceImplementations(b.DIContainer, iface)
+}
+
+// BindInterface declares the binding on the container registrations are recorded on
+func (r *registrationRecorder) BindInterface(iface reflect.Type, name string) error {
+	return bindInterface(r.DIContainer, iface, name)
+}
+
+// InterfaceImplementations returns the implementations known to the container
+// registrations are recorded on
+func (r *registrationRecorder) InterfaceImplementations(iface reflect.Type) []string {
+	return interfaceImplementations(r.DIContainer, iface)
+}
+
+// bindInterface declares a binding on container, if it indexes interfaces
+func bindInterface(container DIContainer, iface reflect.Type, name string) error {
+	indexed, ok := container.(InterfaceContainer)
+	if !ok {
+		return fmt.Errorf("cannot bind service '%s' to %s: container %T does not index interfaces", name, iface, container)
+	}
+	return indexed.BindInterface(iface, name)
+}
+
+// interfaceImplementations returns the services container knows to implement iface
+func interfaceImplementations(container DIContainer, iface reflect.Type) []string {
+	if indexed, ok := container.(InterfaceContainer); ok {
+		return indexed.InterfaceImplementations(iface)
+	}
+	return nil
+}
+
+// Implements declares that the service registered as name implements the interface
+// I, so ResolveInterface[I] finds it, e.g. core.Implements[UserService](container, "userService")
+func Implements[I any](container DIContainer, name string) error {
+	return bindInterface(container, reflect.TypeOf((*I)(nil)).Elem(), name)
+}
+
+// ResolveInterface resolves the one service declared to implement the interface T
+func ResolveInterface[T any](container DIContainer) (T, error) {
+	return ResolveInterfaceWithContext[T](container, context.Background())
+}
+
+// ResolveInterfaceWithContext resolves the one service declared to implement the
+// interface T with context; it fails with ErrNoImplementation or
+// ErrAmbiguousImplementation when there is not exactly one
+func ResolveInterfaceWithContext[T any](container DIContainer, ctx context.Context) (T, error) {
+	var zero T
+	iface := reflect.TypeOf((*T)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		return zero, fmt.Errorf("cannot resolve %s by interface: it is not an interface type", iface)
+	}
+	if container == nil {
+		return zero, fmt.Errorf("cannot resolve %s: container is nil", iface)
+	}
+
+	names := interfaceImplementations(container, iface)
+	switch len(names) {
+	case 0:
+		return zero, fmt.Errorf("%w: %s", ErrNoImplementation, iface)
+	case 1:
+		return ResolveTypedWithContext[T](container, names[0], ctx)
+	default:
+		return zero, fmt.Errorf("%w: %s is implemented by %s", ErrAmbiguousImplementation, iface, describeServiceNames(names))
+	}
+}
+
+// ResolveByInterface resolves the one service declared to implement the interface T
+// from the global locator's container
+func ResolveByInterface[T any]() (T, error) {
+	return ResolveInterface[T](GlobalLocator.GetContainer())
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lazyModule runs the deferred initialization of a Module with Lazy set: its async
+// providers and eager singletons, built once on first access
+type lazyModule struct {
+	name   string
+	plugin Plugin
+	pm     *PluginManager
+	once   sync.Once
+	err    error
+}
+
+// lazyInitKey marks resolutions made by a lazy module's own initialization
+type lazyInitKey struct{}
+
+// initialize initializes the module once; resolutions from the initialization itself
+// pass through. trigger is the service being resolved, if any, which is left for its
+// resolution to build rather than being built twice
+func (m *lazyModule) initialize(ctx context.Context, trigger string) error {
+	if ctx.Value(lazyInitKey{}) == m {
+		return nil
+	}
+
+	m.once.Do(func() {
+		// Detached from the triggering resolution, so a cancelled request cannot
+		// fail the module for good
+		ctx := context.WithValue(context.Background(), lazyInitKey{}, m)
+		plugins := []Plugin{m.plugin}
+		if err := m.pm.initializeAsyncProviders(ctx, plugins, trigger); err != nil {
+			m.err = fmt.Errorf("lazy module '%s' initialization failed: %w", m.name, err)
+			return
+		}
+		if err := m.pm.initializeEagerSingletons(ctx, plugins, trigger); err != nil {
+			m.err = fmt.Errorf("lazy module '%s' initialization failed: %w", m.name, err)
+		}
+	})
+	return m.err
+}
+
+// lazyProvider initializes its lazy module before resolving
+// Value providers are not wrapped: they build nothing and keep their validation
+type lazyProvider struct {
+	Provider
+	module *lazyModule
+}
+
+func (p *lazyProvider) Resolve(container DIContainer, ctx context.Context) (interface{}, error) {
+	if err := p.module.initialize(ctx, p.GetName()); err != nil {
+		return nil, err
+	}
+	return p.Provider.Resolve(container, ctx)
+}
+
+// wrap returns provider wrapped so that resolving it initializes the module
+func (m *lazyModule) wrap(provider Provider) Provider {
+	if provider == nil {
+		return nil
+	}
+	if _, ok := provider.(*ValueProvider); ok {
+		return provider
+	}
+	return &lazyProvider{Provider: provider, module: m}
+}
+
+// lazyModuleMiddleware initializes a lazy module on the first request to one of its
+// routes, answering 500 if that fails
+func (pm *PluginManager) lazyModuleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if module := pm.lazyModule(pm.routeModule(c.Request.Method, c.FullPath())); module != nil {
+			if err := module.initialize(c.Request.Context(), ""); err != nil {
+				AbortWithError(c, err)
+				return
+			}
+		}
+		c.Next()
+	}
+}
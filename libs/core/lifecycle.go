@@ -1,6 +1,10 @@
 package core
 
 import (
+	"slices"
+	"sort"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -53,9 +57,18 @@ func (h *LifecycleHookFunc) OnError(c *gin.Context, err error) {
 }
 
 // LifecycleManager manages the execution of lifecycle hooks
+// Hooks run in priority order (see PrioritizedPlugin), then in the order they were added
+// Hooks may be added and removed while requests are served: the slices are replaced,
+// never modified in place, so a request keeps the hooks it started with
 type LifecycleManager struct {
-	hooks    []LifecycleHook
-	appHooks []ApplicationHook
+	mu                sync.RWMutex
+	hooks             []LifecycleHook
+	hookPriorities    []int    // Parallel to hooks, ascending
+	hookOwners        []string // Parallel to hooks; the plugin that added each hook, if any
+	appHooks          []ApplicationHook
+	appHookPriorities []int    // Parallel to appHooks, ascending
+	appHookOwners     []string // Parallel to appHooks
+	responseBodyLimit int      // JSON response bytes captured for OnResponse; 0 disables capture
 }
 
 // NewLifecycleManager creates a new lifecycle manager
@@ -66,16 +79,86 @@ func NewLifecycleManager() *LifecycleManager {
 	}
 }
 
-// AddHook adds a lifecycle hook
+// SetResponseBodyLimit enables capturing up to limit bytes of JSON response bodies
+// into ResponseInfo.Body for OnResponse hooks; zero or less disables capture
+func (lm *LifecycleManager) SetResponseBodyLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	lm.responseBodyLimit = limit
+}
+
+// AddHook adds a lifecycle hook with DefaultPriority
 func (lm *LifecycleManager) AddHook(hook LifecycleHook) {
-	if hook != nil {
-		lm.hooks = append(lm.hooks, hook)
+	lm.AddHookWithPriority(hook, DefaultPriority)
+}
+
+// AddHookWithPriority adds a lifecycle hook after every hook of lower or equal priority
+func (lm *LifecycleManager) AddHookWithPriority(hook LifecycleHook, priority int) {
+	lm.addHook(hook, priority, "")
+}
+
+// addHook adds a lifecycle hook on behalf of owner, so RemovePluginHooks can remove it
+func (lm *LifecycleManager) addHook(hook LifecycleHook, priority int, owner string) {
+	if hook == nil {
+		return
 	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	i := priorityIndex(lm.hookPriorities, priority)
+	lm.hooks = slices.Insert(slices.Clone(lm.hooks), i, hook)
+	lm.hookPriorities = slices.Insert(slices.Clone(lm.hookPriorities), i, priority)
+	lm.hookOwners = slices.Insert(slices.Clone(lm.hookOwners), i, owner)
+}
+
+// RemovePluginHooks removes the lifecycle and application hooks added for the named
+// plugin when it was registered; hooks it added itself (e.g. in Init) are kept
+func (lm *LifecycleManager) RemovePluginHooks(plugin string) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	lm.hooks, lm.hookPriorities, lm.hookOwners = withoutOwner(lm.hooks, lm.hookPriorities, lm.hookOwners, plugin)
+	lm.appHooks, lm.appHookPriorities, lm.appHookOwners = withoutOwner(lm.appHooks, lm.appHookPriorities, lm.appHookOwners, plugin)
+}
+
+// withoutOwner returns copies of the parallel hook slices without the entries of owner
+func withoutOwner[H any](hooks []H, priorities []int, owners []string, owner string) ([]H, []int, []string) {
+	keptHooks := make([]H, 0, len(hooks))
+	keptPriorities := make([]int, 0, len(priorities))
+	keptOwners := make([]string, 0, len(owners))
+	for i, hookOwner := range owners {
+		if hookOwner != owner {
+			keptHooks = append(keptHooks, hooks[i])
+			keptPriorities = append(keptPriorities, priorities[i])
+			keptOwners = append(keptOwners, hookOwner)
+		}
+	}
+	return keptHooks, keptPriorities, keptOwners
+}
+
+// currentHooks returns the lifecycle hooks to run for a request
+func (lm *LifecycleManager) currentHooks() []LifecycleHook {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+	return lm.hooks
+}
+
+// currentAppHooks returns the application hooks to run
+func (lm *LifecycleManager) currentAppHooks() []ApplicationHook {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+	return lm.appHooks
+}
+
+// priorityIndex returns where an entry of the given priority is inserted into
+// ascending priorities: after every entry of lower or equal priority
+func priorityIndex(priorities []int, priority int) int {
+	return sort.Search(len(priorities), func(i int) bool { return priorities[i] > priority })
 }
 
 // ExecuteOnRequest executes all OnRequest hooks
 func (lm *LifecycleManager) ExecuteOnRequest(c *gin.Context) {
-	for _, hook := range lm.hooks {
+	for _, hook := range lm.currentHooks() {
 		hook.OnRequest(c)
 		if c.IsAborted() {
 			return
@@ -85,7 +168,7 @@ func (lm *LifecycleManager) ExecuteOnRequest(c *gin.Context) {
 
 // ExecutePreHandler executes all PreHandler hooks
 func (lm *LifecycleManager) ExecutePreHandler(c *gin.Context) {
-	for _, hook := range lm.hooks {
+	for _, hook := range lm.currentHooks() {
 		hook.PreHandler(c)
 		if c.IsAborted() {
 			return
@@ -95,18 +178,80 @@ func (lm *LifecycleManager) ExecutePreHandler(c *gin.Context) {
 
 // ExecuteOnResponse executes all OnResponse hooks
 func (lm *LifecycleManager) ExecuteOnResponse(c *gin.Context, response interface{}) {
-	for _, hook := range lm.hooks {
+	for _, hook := range lm.currentHooks() {
 		hook.OnResponse(c, response)
 	}
 }
 
-// ExecuteOnError executes all OnError hooks
+// ExecuteOnError executes all OnError hooks, then responds with the JSON error
+// envelope (see ToAPIError), or the error handler of the route's module, unless a
+// response has already been written
 func (lm *LifecycleManager) ExecuteOnError(c *gin.Context, err error) {
-	for _, hook := range lm.hooks {
+	lm.executeOnErrorHooks(c, err)
+	if !c.Writer.Written() {
+		respondWithError(c, err, ToAPIError(err))
+	}
+}
+
+// executeOnErrorHooks executes all OnError hooks without rendering a response
+func (lm *LifecycleManager) executeOnErrorHooks(c *gin.Context, err error) {
+	for _, hook := range lm.currentHooks() {
 		hook.OnError(c, err)
 	}
 }
 
+// ResponseFinisher is implemented by response writers that OnRequest hooks install
+// over c.Writer and that buffer output, such as compression; Finish writes what
+// is left and is called once the handler chain is done, before OnResponse
+// A finisher installed over another finishes it in turn; errors it records on the
+// context (see AbortWithError) reach the OnError hooks
+type ResponseFinisher interface {
+	gin.ResponseWriter
+	Finish() error
+}
+
+// ResponseMiddleware returns middleware that runs OnError and OnResponse hooks
+// It must be installed before the OnRequest middleware so that requests aborted
+// by OnRequest hooks still reach OnResponse. Per-request hook order is:
+//
+//	OnRequest -> route middleware -> PreHandler -> handler -> OnError (per error) -> OnResponse
+//
+// OnError also fires when a handler panics; the panic is then re-raised for the
+// recovery middleware, which does not run OnError again
+func (lm *LifecycleManager) ResponseMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := newResponseWriter(c.Writer, lm.responseBodyLimit)
+		c.Writer = writer
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				// The recovery middleware renders the response
+				lm.executeOnErrorHooks(c, panicError(recovered))
+				c.Set(panicReportedKey, true)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		for _, ginErr := range c.Errors {
+			lm.ExecuteOnError(c, ginErr.Err)
+		}
+		reported := len(c.Errors)
+
+		// Writers installed by hooks (e.g. compression) flush first so the size is final
+		if finisher, ok := c.Writer.(ResponseFinisher); ok {
+			finisher.Finish()
+		}
+		// Finishing responds to its own errors, e.g. a failed transaction commit
+		for _, ginErr := range c.Errors[reported:] {
+			lm.executeOnErrorHooks(c, ginErr.Err)
+		}
+
+		lm.ExecuteOnResponse(c, writer.Info())
+	}
+}
+
 // Helper functions to create specific hooks
 
 // NewOnRequestHook creates a hook that only implements OnRequest
@@ -207,30 +352,47 @@ func (h *ApplicationHookFunc) OnClose() error {
 	return nil
 }
 
-// AddAppHook adds an application lifecycle hook
+// AddAppHook adds an application lifecycle hook with DefaultPriority
 func (lm *LifecycleManager) AddAppHook(hook ApplicationHook) {
-	if hook != nil {
-		lm.appHooks = append(lm.appHooks, hook)
+	lm.AddAppHookWithPriority(hook, DefaultPriority)
+}
+
+// AddAppHookWithPriority adds an application hook after every hook of lower or equal priority
+func (lm *LifecycleManager) AddAppHookWithPriority(hook ApplicationHook, priority int) {
+	lm.addAppHook(hook, priority, "")
+}
+
+// addAppHook adds an application hook on behalf of owner (see addHook)
+func (lm *LifecycleManager) addAppHook(hook ApplicationHook, priority int, owner string) {
+	if hook == nil {
+		return
 	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	i := priorityIndex(lm.appHookPriorities, priority)
+	lm.appHooks = slices.Insert(slices.Clone(lm.appHooks), i, hook)
+	lm.appHookPriorities = slices.Insert(slices.Clone(lm.appHookPriorities), i, priority)
+	lm.appHookOwners = slices.Insert(slices.Clone(lm.appHookOwners), i, owner)
 }
 
 // ExecuteOnRoute executes all OnRoute hooks
 func (lm *LifecycleManager) ExecuteOnRoute(config *RouteConfig) {
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		hook.OnRoute(config)
 	}
 }
 
 // ExecuteOnRegister executes all OnRegister hooks
 func (lm *LifecycleManager) ExecuteOnRegister(plugin interface{}) {
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		hook.OnRegister(plugin)
 	}
 }
 
 // ExecuteOnReady executes all OnReady hooks
 func (lm *LifecycleManager) ExecuteOnReady(app interface{}) error {
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		if err := hook.OnReady(app); err != nil {
 			return err
 		}
@@ -240,14 +402,14 @@ func (lm *LifecycleManager) ExecuteOnReady(app interface{}) error {
 
 // ExecuteOnListen executes all OnListen hooks
 func (lm *LifecycleManager) ExecuteOnListen(addr string) {
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		hook.OnListen(addr)
 	}
 }
 
 // ExecutePreClose executes all PreClose hooks
 func (lm *LifecycleManager) ExecutePreClose(ctx interface{}) {
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		hook.PreClose(ctx)
 	}
 }
@@ -255,7 +417,7 @@ func (lm *LifecycleManager) ExecutePreClose(ctx interface{}) {
 // ExecuteOnClose executes all OnClose hooks
 func (lm *LifecycleManager) ExecuteOnClose() error {
 	var lastErr error
-	for _, hook := range lm.appHooks {
+	for _, hook := range lm.currentAppHooks() {
 		if err := hook.OnClose(); err != nil {
 			lastErr = err
 		}
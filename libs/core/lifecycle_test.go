@@ -0,0 +1,290 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleTestApp(t *testing.T) *DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := CreateDoffApp(&AppOptions{
+		Name: "lifecycle-test",
+		Mode: gin.TestMode,
+	})
+	return app.(*DoffApp)
+}
+
+func TestLifecycle_OnResponseAndOnErrorOrder(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var order []string
+	var responseInfo *ResponseInfo
+	var hookErr error
+
+	app.GetPluginManager().GetLifecycleManager().AddHook(&LifecycleHookFunc{
+		OnRequestFunc:  func(c *gin.Context) { order = append(order, "OnRequest") },
+		PreHandlerFunc: func(c *gin.Context) { order = append(order, "PreHandler") },
+		OnErrorFunc: func(c *gin.Context, err error) {
+			order = append(order, "OnError")
+			hookErr = err
+		},
+		OnResponseFunc: func(c *gin.Context, response interface{}) {
+			order = append(order, "OnResponse")
+			responseInfo, _ = response.(*ResponseInfo)
+		},
+	})
+
+	app.GetRouter().GET(RouteConfig{Path: "/fail"}, func(c *gin.Context, container DIContainer) {
+		order = append(order, "Handler")
+		c.Error(errors.New("boom"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "boom"})
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	assert.Equal(t, []string{"OnRequest", "PreHandler", "Handler", "OnError", "OnResponse"}, order)
+	require.NotNil(t, responseInfo)
+	assert.Equal(t, http.StatusBadRequest, responseInfo.StatusCode)
+	assert.Equal(t, recorder.Body.Len(), responseInfo.Size)
+	assert.EqualError(t, hookErr, "boom")
+}
+
+func TestLifecycle_OnResponseRunsWhenOnRequestAborts(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var status int
+	app.GetPluginManager().GetLifecycleManager().AddHook(&LifecycleHookFunc{
+		OnRequestFunc: func(c *gin.Context) { c.AbortWithStatus(http.StatusNoContent) },
+		OnResponseFunc: func(c *gin.Context, response interface{}) {
+			status = response.(*ResponseInfo).StatusCode
+		},
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodOptions, "/anything", nil))
+
+	assert.Equal(t, http.StatusNoContent, status)
+}
+
+func TestLifecycle_OnErrorOnPanic(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+
+	app.GetEngine().GET("/panic", func(c *gin.Context) {
+		panic("handler exploded")
+	})
+
+	// The recovery middleware answers 500 without running OnError a second time
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.Len(t, hookErrs, 1)
+	assert.EqualError(t, hookErrs[0], "panic: handler exploded")
+}
+
+func TestDoffApp_ValidateDoesNotRegisterRoutes(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	routesCalled := false
+	plugin := &routesRecordingPlugin{
+		moduleTestPlugin: newModuleTestPlugin(NewModule("orders", "1.0.0")),
+		called:           &routesCalled,
+	}
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	require.NoError(t, app.Validate())
+	assert.False(t, routesCalled)
+	assert.Empty(t, app.GetEngine().Routes())
+}
+
+// routesRecordingPlugin records whether Routes was called
+type routesRecordingPlugin struct {
+	*moduleTestPlugin
+	called *bool
+}
+
+func (p *routesRecordingPlugin) Routes(router *gin.Engine) error {
+	*p.called = true
+	return nil
+}
+
+// shutdownRecordingPlugin records Shutdown calls and can fail Init
+type shutdownRecordingPlugin struct {
+	*moduleTestPlugin
+	initErr  error
+	shutdown chan struct{}
+}
+
+func (p *shutdownRecordingPlugin) Init(app *DoffApp) error {
+	return p.initErr
+}
+
+func (p *shutdownRecordingPlugin) Shutdown() error {
+	close(p.shutdown)
+	return nil
+}
+
+func TestDoffApp_RunContextShutsDownGracefully(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.config.Port = 0 // any free port
+
+	plugin := &shutdownRecordingPlugin{
+		moduleTestPlugin: newModuleTestPlugin(NewModule("orders", "1.0.0")),
+		shutdown:         make(chan struct{}),
+	}
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- app.RunContext(ctx)
+	}()
+
+	require.Eventually(t, app.GetPluginManager().IsInitialized, time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunContext did not return after cancellation")
+	}
+
+	select {
+	case <-plugin.shutdown:
+	default:
+		t.Fatal("plugins were not shut down")
+	}
+}
+
+func TestDoffApp_RunReturnsStartupErrors(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	errInit := errors.New("missing credentials")
+	plugin := &shutdownRecordingPlugin{
+		moduleTestPlugin: newModuleTestPlugin(NewModule("orders", "1.0.0")),
+		initErr:          errInit,
+		shutdown:         make(chan struct{}),
+	}
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	err := app.Run()
+	assert.ErrorIs(t, err, errInit)
+}
+
+func TestCreateDoffApp_ShutdownTimeout(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{Name: "timeouts", Mode: gin.TestMode}).(*DoffApp)
+	assert.Equal(t, DefaultShutdownTimeout, app.shutdownTimeout)
+
+	app = CreateDoffApp(&AppOptions{Name: "timeouts", Mode: gin.TestMode, ShutdownTimeout: time.Second}).(*DoffApp)
+	assert.Equal(t, time.Second, app.shutdownTimeout)
+}
+
+func TestDoffApp_ListenReturnsErrors(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	errInit := errors.New("missing credentials")
+	require.NoError(t, app.RegisterPlugin(&shutdownRecordingPlugin{
+		moduleTestPlugin: newModuleTestPlugin(NewModule("orders", "1.0.0")),
+		initErr:          errInit,
+		shutdown:         make(chan struct{}),
+	}))
+
+	assert.ErrorIs(t, app.Listen(), errInit)
+}
+
+func TestDoffApp_ListenReturnsNilAfterShutdown(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.config.Port = 0 // any free port
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Listen()
+	}()
+
+	require.Eventually(t, app.GetPluginManager().IsInitialized, time.Second, 10*time.Millisecond)
+	// Give ListenAndServe a moment to bind before shutting down
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, app.Shutdown(context.Background()))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Listen did not return after Shutdown")
+	}
+}
+
+func TestLifecycle_OnResponseCapturesJSONBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:              "lifecycle-test",
+		Mode:              gin.TestMode,
+		ResponseBodyLimit: 32,
+	}).(*DoffApp)
+
+	var responses []*ResponseInfo
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnResponseHook(func(c *gin.Context, response interface{}) {
+		responses = append(responses, response.(*ResponseInfo))
+	}))
+
+	app.GetEngine().GET("/user", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": "42"})
+	})
+	app.GetEngine().GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"padding": strings.Repeat("x", 64)})
+	})
+	app.GetEngine().GET("/download", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte("binary"))
+	})
+
+	for _, path := range []string{"/user", "/large", "/download"} {
+		app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	require.Len(t, responses, 3)
+
+	var user map[string]string
+	require.NoError(t, responses[0].DecodeJSON(&user))
+	assert.Equal(t, "42", user["id"])
+
+	assert.True(t, responses[1].Truncated)
+	assert.Len(t, responses[1].Body, 32)
+	assert.ErrorIs(t, responses[1].DecodeJSON(&user), ErrResponseBodyNotCaptured)
+
+	assert.Nil(t, responses[2].Body)
+	assert.Equal(t, 6, responses[2].Size)
+}
+
+func TestLifecycle_OnResponseSkipsBodyByDefault(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var info *ResponseInfo
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnResponseHook(func(c *gin.Context, response interface{}) {
+		info = response.(*ResponseInfo)
+	}))
+	app.GetEngine().GET("/user", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": "42"})
+	})
+
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user", nil))
+
+	require.NotNil(t, info)
+	assert.Nil(t, info.Body)
+	assert.Equal(t, "application/json; charset=utf-8", info.ContentType)
+}
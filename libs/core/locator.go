@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
@@ -52,19 +53,17 @@ func (sl *serviceLocator) GetByType(serviceType reflect.Type) (interface{}, erro
 		return nil, fmt.Errorf("container not set")
 	}
 
-	// Get the type name
-	typeName := serviceType.String()
+	// Resolve by the first name registered: the container's naming strategy if set,
+	// otherwise the type name (e.g. *users.UserService), then the short name (UserService)
+	names := serviceNamesForType(sl.container, serviceType)
+	name, found := serviceNameForType(sl.container, serviceType)
+	if !found {
+		return nil, fmt.Errorf("no service registered for type %s as %s", serviceType, describeServiceNames(names))
+	}
 
-	// Try to resolve by type name
-	service, err := sl.container.Resolve(typeName)
+	service, err := sl.container.Resolve(name)
 	if err != nil {
-		// If type name resolution fails, try with a generic naming convention
-		// For example: *UserService -> userService
-		typeName = toServiceName(serviceType)
-		service, err = sl.container.Resolve(typeName)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	return service, nil
@@ -146,6 +145,33 @@ func SetGlobalContainer(container DIContainer) {
 	GlobalLocator.SetContainer(container)
 }
 
+// ResolveTyped resolves a service by name and asserts it to T
+// Returns the zero value of T and a descriptive error instead of panicking on mismatch
+func ResolveTyped[T any](container DIContainer, name string) (T, error) {
+	return ResolveTypedWithContext[T](container, name, context.Background())
+}
+
+// ResolveTypedWithContext resolves a service by name with context and asserts it to T
+func ResolveTypedWithContext[T any](container DIContainer, name string, ctx context.Context) (T, error) {
+	var zero T
+
+	if container == nil {
+		return zero, fmt.Errorf("cannot resolve service '%s': container is nil", name)
+	}
+
+	service, err := container.ResolveWithContext(name, ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := service.(T)
+	if !ok {
+		return zero, fmt.Errorf("service '%s' is of type %T, not %s", name, service, reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	return typed, nil
+}
+
 // toServiceName converts a type to a service name
 func toServiceName(t reflect.Type) string {
 	// If it's a pointer, get the element type
@@ -157,10 +183,11 @@ func toServiceName(t reflect.Type) string {
 	return t.Name()
 }
 
-// RegisterByType registers a service by its type for easier resolution
+// RegisterByType registers a service by its type for easier resolution, under the
+// name the container's naming strategy gives it (the full type name by default)
 func RegisterByType[T any](container DIContainer, factory Factory, lifetime Lifetime) error {
 	var t T
-	typeName := reflect.TypeOf(t).String()
+	typeName := serviceNamesForType(container, reflect.TypeOf(t))[0]
 	return container.Register(typeName, factory, lifetime)
 }
 
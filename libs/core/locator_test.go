@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// greeter is an interface used to resolve services by interface type
+type greeter interface {
+	Greet() string
+}
+
+func (s *TestService) Greet() string {
+	return "hello " + s.Value
+}
+
+func TestResolveTyped_PointerType(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewValueProvider("testService", &TestService{Value: "typed"}))
+
+	service, err := ResolveTyped[*TestService](container, "testService")
+	require.NoError(t, err)
+	assert.Equal(t, "typed", service.Value)
+}
+
+func TestResolveTyped_InterfaceType(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewValueProvider("greeter", &TestService{Value: "world"}))
+
+	service, err := ResolveTyped[greeter](container, "greeter")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", service.Greet())
+}
+
+func TestResolveTyped_ScopedContainers(t *testing.T) {
+	root := NewDIContainer()
+	root.RegisterProvider(NewValueProvider("testService", &TestService{Value: "root"}))
+
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), root)
+	requestContainer := NewRequestContainer(moduleContainer)
+	requestContainer.DecorateRequest("userID", 42)
+
+	fromModule, err := ResolveTyped[*TestService](moduleContainer, "testService")
+	require.NoError(t, err)
+	assert.Equal(t, "root", fromModule.Value)
+
+	userID, err := ResolveTyped[int](requestContainer, "userID")
+	require.NoError(t, err)
+	assert.Equal(t, 42, userID)
+}
+
+func TestResolveTyped_TypeMismatch(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewValueProvider("testService", &TestService{Value: "typed"}))
+
+	service, err := ResolveTyped[*TestService2](container, "testService")
+	require.Error(t, err)
+	assert.Nil(t, service)
+	assert.Equal(t, "service 'testService' is of type *core.TestService, not *core.TestService2", err.Error())
+
+	_, err = ResolveTyped[*TestService](container, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service 'missing' is not registered")
+}
+
+// httpServerConfig is named by the naming strategy tests
+type httpServerConfig struct {
+	Port int
+}
+
+// HTTPServer checks acronyms are kept together in snake_case names
+type HTTPServer struct{}
+
+func TestNamingStrategies(t *testing.T) {
+	typ := reflect.TypeOf(&httpServerConfig{})
+
+	assert.Equal(t, "*core.httpServerConfig", FullTypeName(typ))
+	assert.Equal(t, "httpServerConfig", ShortTypeName(typ))
+	assert.Equal(t, "http_server_config", SnakeCaseTypeName(typ))
+	assert.Equal(t, "http_server", SnakeCaseTypeName(reflect.TypeOf(struct{ HTTPServer }{}).Field(0).Type))
+	assert.Equal(t, "user_id2_service", toSnakeCase("UserID2Service"))
+}
+
+func TestGetByType_NamingStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy NamingStrategy
+		register string
+	}{
+		{name: "full type name", strategy: FullTypeName, register: "*core.httpServerConfig"},
+		{name: "short type name", strategy: ShortTypeName, register: "httpServerConfig"},
+		{name: "snake case", strategy: SnakeCaseTypeName, register: "http_server_config"},
+	}
+	typ := reflect.TypeOf(&httpServerConfig{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewDIContainer()
+			container.(NamingStrategyContainer).SetNamingStrategy(tt.strategy)
+			require.NoError(t, container.RegisterProvider(NewValueProvider(tt.register, &httpServerConfig{Port: 8080})))
+			locator := &serviceLocator{container: container}
+
+			service, err := locator.GetByType(typ)
+			require.NoError(t, err)
+			assert.Equal(t, 8080, service.(*httpServerConfig).Port)
+
+			// Only the strategy's name is looked up
+			other := NewDIContainer()
+			other.(NamingStrategyContainer).SetNamingStrategy(tt.strategy)
+			for _, name := range []string{"*core.httpServerConfig", "httpServerConfig", "http_server_config"} {
+				if name != tt.register {
+					require.NoError(t, other.RegisterProvider(NewValueProvider(name, &httpServerConfig{})))
+				}
+			}
+			_, err = (&serviceLocator{container: other}).GetByType(typ)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "'"+tt.register+"'")
+
+			// RegisterByType uses the same name
+			byType := NewDIContainer()
+			byType.(NamingStrategyContainer).SetNamingStrategy(tt.strategy)
+			require.NoError(t, RegisterSingletonByType[*httpServerConfig](byType, func(DIContainer) (interface{}, error) {
+				return &httpServerConfig{}, nil
+			}))
+			assert.True(t, byType.Has(tt.register))
+		})
+	}
+}
+
+func TestGetByType_DefaultLookupOrder(t *testing.T) {
+	typ := reflect.TypeOf(&httpServerConfig{})
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterProvider(NewValueProvider("httpServerConfig", &httpServerConfig{Port: 1})))
+	locator := &serviceLocator{container: container}
+
+	service, err := locator.GetByType(typ)
+	require.NoError(t, err)
+	assert.Equal(t, 1, service.(*httpServerConfig).Port)
+
+	// The full type name wins over the short name
+	require.NoError(t, container.RegisterProvider(NewValueProvider("*core.httpServerConfig", &httpServerConfig{Port: 2})))
+	service, err = locator.GetByType(typ)
+	require.NoError(t, err)
+	assert.Equal(t, 2, service.(*httpServerConfig).Port)
+
+	_, err = (&serviceLocator{container: NewDIContainer()}).GetByType(typ)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'*core.httpServerConfig' or 'httpServerConfig'")
+}
+
+func TestNamingStrategy_InheritedByChildContainers(t *testing.T) {
+	root := NewDIContainer()
+	root.(NamingStrategyContainer).SetNamingStrategy(SnakeCaseTypeName)
+	require.NoError(t, root.RegisterProvider(NewValueProvider("http_server_config", &httpServerConfig{Port: 9000})))
+	require.NoError(t, root.RegisterProvider(NewClassProviderWithConstructor("server", func(config *httpServerConfig) *TestService {
+		return &TestService{Value: fmt.Sprint(config.Port)}
+	}, Singleton)))
+
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), root)
+	requestContainer := NewRequestContainer(moduleContainer)
+	assert.NotNil(t, requestContainer.NamingStrategy())
+
+	value, err := resolveHandlerParam(context.Background(), requestContainer, reflect.TypeOf(&httpServerConfig{}))
+	require.NoError(t, err)
+	assert.Equal(t, 9000, value.Interface().(*httpServerConfig).Port)
+
+	server, err := ResolveTyped[*TestService](requestContainer, "server")
+	require.NoError(t, err)
+	assert.Equal(t, "9000", server.Value)
+
+	// A child's own strategy overrides the inherited one
+	moduleContainer.SetNamingStrategy(FullTypeName)
+	_, err = resolveHandlerParam(context.Background(), requestContainer, reflect.TypeOf(&httpServerConfig{}))
+	require.Error(t, err)
+}
+
+func TestResolveInterface_DeclaredImplementation(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterSingleton("englishGreeter", func(c DIContainer) (interface{}, error) {
+		return &TestService{Value: "world"}, nil
+	}))
+	require.NoError(t, Implements[greeter](container, "englishGreeter"))
+
+	service, err := ResolveInterface[greeter](container)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", service.Greet())
+
+	// Child containers fall back to the parent's declarations
+	moduleContainer := NewModuleContainer(NewModule("greetings", "1.0.0"), container)
+	service, err = ResolveInterface[greeter](moduleContainer)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", service.Greet())
+
+	// The global locator resolves through its container
+	previous := GlobalLocator.GetContainer()
+	SetGlobalContainer(container)
+	t.Cleanup(func() { SetGlobalContainer(previous) })
+	service, err = ResolveByInterface[greeter]()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", service.Greet())
+}
+
+func TestResolveInterface_Errors(t *testing.T) {
+	container := NewDIContainer()
+	require.NoError(t, container.RegisterProvider(NewValueProvider("english", &TestService{Value: "world"})))
+	require.NoError(t, container.RegisterProvider(NewValueProvider("french", &TestService{Value: "monde"})))
+
+	_, err := ResolveInterface[greeter](container)
+	assert.ErrorIs(t, err, ErrNoImplementation)
+
+	assert.Error(t, Implements[greeter](container, "missing"), "the service must be registered")
+	assert.Error(t, Implements[*TestService](container, "english"), "only interfaces can be bound")
+
+	require.NoError(t, Implements[greeter](container, "english"))
+	require.NoError(t, Implements[greeter](container, "french"))
+	_, err = ResolveInterface[greeter](container)
+	assert.ErrorIs(t, err, ErrAmbiguousImplementation)
+	assert.Contains(t, err.Error(), "'english' or 'french'")
+
+	// Unregistering a service drops its bindings
+	require.NoError(t, container.(*diContainer).Unregister("french"))
+	service, err := ResolveInterface[greeter](container)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", service.Greet())
+
+	// A declaration is checked when resolving
+	other := NewDIContainer()
+	require.NoError(t, other.RegisterProvider(NewValueProvider("number", 42)))
+	require.NoError(t, Implements[greeter](other, "number"))
+	_, err = ResolveInterface[greeter](other)
+	assert.ErrorContains(t, err, "is of type int")
+}
@@ -3,10 +3,51 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
+// LogLevel is the severity of a log entry
+// The zero value is LevelInfo so entries without a level keep logging as before
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the upper-case level name
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// LogFormat selects how the default logger renders entries
+type LogFormat string
+
+const (
+	// LogFormatText is the human-oriented multi-line format (default)
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes one JSON object per line for log aggregators
+	LogFormatJSON LogFormat = "json"
+)
+
 type LoggerItem struct {
+	Level    LogLevel
 	Event    string
 	Messages string
 	Error    error       `json:"error,omitempty"`
@@ -17,19 +58,162 @@ type Logger interface {
 	Infor(*LoggerItem)
 }
 
-type logger struct{}
+// LeveledLogger is a Logger with severity-specific helpers and a minimum level
+type LeveledLogger interface {
+	Logger
+	Debug(*LoggerItem)
+	Info(*LoggerItem)
+	Warn(*LoggerItem)
+	Error(*LoggerItem)
+	SetLevel(level LogLevel)
+	GetLevel() LogLevel
+}
+
+// LoggerOptions configures the default logger
+type LoggerOptions struct {
+	Level  LogLevel
+	Format LogFormat // LogFormatText when empty
+	Output io.Writer // os.Stdout when nil
+}
+
+type logger struct {
+	mu     sync.Mutex
+	level  LogLevel
+	format LogFormat
+	out    io.Writer
+}
 
 func InitLogger() Logger {
-	return &logger{}
+	return NewLogger(LevelInfo)
+}
+
+// NewLogger creates the default logger, dropping entries below the given level
+func NewLogger(level LogLevel) LeveledLogger {
+	return NewLoggerWithOptions(LoggerOptions{Level: level})
+}
+
+// NewLoggerWithOptions creates the default logger with the given level, format and output
+func NewLoggerWithOptions(opts LoggerOptions) LeveledLogger {
+	l := &logger{
+		level:  opts.Level,
+		format: opts.Format,
+		out:    opts.Output,
+	}
+	if l.format == "" {
+		l.format = LogFormatText
+	}
+	if l.out == nil {
+		l.out = os.Stdout
+	}
+	return l
+}
+
+// jsonLogEntry is the single-line JSON rendering of a LoggerItem
+type jsonLogEntry struct {
+	Time    string      `json:"time"`
+	Level   string      `json:"level"`
+	Event   string      `json:"event"`
+	Message string      `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
+// Infor writes the entry at its own Level (Info when unset)
 func (l *logger) Infor(payload *LoggerItem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if payload.Level < l.level {
+		return
+	}
+
+	if l.format == LogFormatJSON {
+		l.writeJSON(payload)
+		return
+	}
+
 	b, _ := json.MarshalIndent(payload.Data, "", " ")
-	fmt.Printf("[Doff-Event]::%s::[Message]::::%s:::[Data]----->`\n%s\n", payload.Event, payload.Messages, string(b))
+	fmt.Fprintf(l.out, "[Doff-Event]::%s::[Level]::%s::[Message]::::%s:::[Data]----->`\n%s\n", payload.Event, payload.Level, payload.Messages, string(b))
+	if payload.Error != nil {
+		fmt.Fprintf(l.out, "[Error]::%v\n", payload.Error)
+	}
+}
+
+// writeJSON writes the entry as one JSON line; error values are rendered as their message
+func (l *logger) writeJSON(payload *LoggerItem) {
+	entry := jsonLogEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   payload.Level.String(),
+		Event:   payload.Event,
+		Message: payload.Messages,
+		Data:    payload.Data,
+	}
+	if payload.Error != nil {
+		entry.Error = payload.Error.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// Keep the entry even when Data can't be marshaled
+		entry.Data = fmt.Sprintf("%+v", payload.Data)
+		line, _ = json.Marshal(entry)
+	}
+	fmt.Fprintf(l.out, "%s\n", line)
+}
+
+// Debug logs the entry at LevelDebug
+func (l *logger) Debug(payload *LoggerItem) {
+	l.Infor(withLevel(payload, LevelDebug))
+}
+
+// Info logs the entry at LevelInfo
+func (l *logger) Info(payload *LoggerItem) {
+	l.Infor(withLevel(payload, LevelInfo))
+}
+
+// Warn logs the entry at LevelWarn
+func (l *logger) Warn(payload *LoggerItem) {
+	l.Infor(withLevel(payload, LevelWarn))
+}
+
+// Error logs the entry at LevelError
+func (l *logger) Error(payload *LoggerItem) {
+	l.Infor(withLevel(payload, LevelError))
+}
+
+// SetLevel changes the minimum level written by the logger
+func (l *logger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// GetLevel returns the minimum level written by the logger
+func (l *logger) GetLevel() LogLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// withLevel returns a copy of the entry with the given level, leaving the caller's entry untouched
+func withLevel(payload *LoggerItem, level LogLevel) *LoggerItem {
+	item := *payload
+	item.Level = level
+	return &item
 }
 
 func DefaultLogger() Logger {
-	logger := InitLogger()
+	return DefaultLoggerWithLevel(LevelInfo)
+}
+
+// DefaultLoggerWithLevel creates the default logger with a minimum level and logs its initialization
+func DefaultLoggerWithLevel(level LogLevel) Logger {
+	return DefaultLoggerWithOptions(LoggerOptions{Level: level})
+}
+
+// DefaultLoggerWithOptions creates the default logger from options and logs its initialization
+func DefaultLoggerWithOptions(opts LoggerOptions) Logger {
+	logger := NewLoggerWithOptions(opts)
 
 	payload := &LoggerItem{
 		Event:    "initLoggerSuccefully",
@@ -44,3 +228,23 @@ func DefaultLogger() Logger {
 
 	return logger
 }
+
+var (
+	frameworkLogger      Logger
+	frameworkLoggerMutex sync.RWMutex
+)
+
+// SetFrameworkLogger routes the framework's internal warnings (e.g. encapsulation violations)
+// through the given logger; DoffApp sets it to the app logger
+func SetFrameworkLogger(l Logger) {
+	frameworkLoggerMutex.Lock()
+	defer frameworkLoggerMutex.Unlock()
+	frameworkLogger = l
+}
+
+// getFrameworkLogger returns the logger set by SetFrameworkLogger, or nil
+func getFrameworkLogger() Logger {
+	frameworkLoggerMutex.RLock()
+	defer frameworkLoggerMutex.RUnlock()
+	return frameworkLogger
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBufferLogger creates a default logger writing to a buffer
+func newBufferLogger(level LogLevel) (LeveledLogger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return NewLoggerWithOptions(LoggerOptions{Level: level, Output: buf}), buf
+}
+
+// recordingLogger captures entries for assertions
+type recordingLogger struct {
+	items []*LoggerItem
+}
+
+func (l *recordingLogger) Infor(item *LoggerItem) {
+	l.items = append(l.items, item)
+}
+
+func TestLogger_MinimumLevel(t *testing.T) {
+	l, buf := newBufferLogger(LevelWarn)
+
+	l.Debug(&LoggerItem{Event: "debugEvent"})
+	l.Info(&LoggerItem{Event: "infoEvent"})
+	l.Infor(&LoggerItem{Event: "unleveledEvent"})
+	l.Warn(&LoggerItem{Event: "warnEvent"})
+	l.Error(&LoggerItem{Event: "errorEvent", Error: errors.New("disk full")})
+
+	output := buf.String()
+	assert.NotContains(t, output, "debugEvent")
+	assert.NotContains(t, output, "infoEvent")
+	assert.NotContains(t, output, "unleveledEvent")
+	assert.Contains(t, output, "[Doff-Event]::warnEvent::[Level]::WARN")
+	assert.Contains(t, output, "[Doff-Event]::errorEvent::[Level]::ERROR")
+	assert.Contains(t, output, "[Error]::disk full")
+
+	buf.Reset()
+	l.SetLevel(LevelDebug)
+	assert.Equal(t, LevelDebug, l.GetLevel())
+	l.Debug(&LoggerItem{Event: "debugEvent"})
+	assert.Contains(t, buf.String(), "[Level]::DEBUG")
+}
+
+func TestLogger_LevelHelpersDoNotMutateEntry(t *testing.T) {
+	l, _ := newBufferLogger(LevelDebug)
+	item := &LoggerItem{Event: "shared"}
+
+	l.Error(item)
+	assert.Equal(t, LevelInfo, item.Level)
+}
+
+func TestEncapsulationWarningRoutesThroughFrameworkLogger(t *testing.T) {
+	originalMode := GetEncapsulationMode()
+	originalLogger := getFrameworkLogger()
+	originalFile := encapsulationViolationLogger
+	defer func() {
+		SetEncapsulationMode(originalMode)
+		SetFrameworkLogger(originalLogger)
+		SetEncapsulationViolationLogger(originalFile)
+	}()
+
+	recorder := &recordingLogger{}
+	SetFrameworkLogger(recorder)
+	SetEncapsulationViolationLogger(nil)
+	SetEncapsulationMode(EncapsulationWarn)
+
+	allowed, err := CheckEncapsulationViolation("orders", "billing", "invoiceRepo")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.Len(t, recorder.items, 1)
+	item := recorder.items[0]
+	assert.Equal(t, LevelWarn, item.Level)
+	assert.Equal(t, "EncapsulationViolation", item.Event)
+	assert.Contains(t, item.Messages, "module 'orders' cannot access unexported provider 'invoiceRepo' from module 'billing'")
+}
+
+func TestDoffApp_LogLevelOption(t *testing.T) {
+	originalLogger := getFrameworkLogger()
+	defer SetFrameworkLogger(originalLogger)
+
+	app := CreateDoffApp(&AppOptions{Name: "leveled", LogLevel: LevelError}).(*DoffApp)
+
+	leveled, ok := app.logger.(LeveledLogger)
+	require.True(t, ok)
+	assert.Equal(t, LevelError, leveled.GetLevel())
+	assert.Same(t, app.logger, getFrameworkLogger())
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewLoggerWithOptions(LoggerOptions{Format: LogFormatJSON, Output: buf})
+
+	l.Error(&LoggerItem{
+		Event:    "PluginInitializationError",
+		Messages: "Failed to initialize plugins",
+		Error:    errors.New("database unreachable"),
+		Data:     map[string]int{"attempts": 3},
+	})
+	l.Info(&LoggerItem{Event: "StartServer"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2, "each entry must be a single line")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "PluginInitializationError", entry["event"])
+	assert.Equal(t, "Failed to initialize plugins", entry["message"])
+	assert.Equal(t, "database unreachable", entry["error"])
+	assert.Equal(t, map[string]interface{}{"attempts": float64(3)}, entry["data"])
+
+	timestamp, err := time.Parse(time.RFC3339Nano, entry["time"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+
+	entry = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.NotContains(t, entry, "error")
+	assert.NotContains(t, entry, "data")
+}
+
+func TestLogger_JSONFormatUnmarshalableData(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewLoggerWithOptions(LoggerOptions{Format: LogFormatJSON, Output: buf})
+
+	l.Info(&LoggerItem{Event: "channel", Data: make(chan int)})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "channel", entry["event"])
+	assert.IsType(t, "", entry["data"])
+}
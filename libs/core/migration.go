@@ -21,7 +21,11 @@ const (
 var (
 	currentEncapsulationMode     = EncapsulationDisabled
 	encapsulationModeMutex       sync.RWMutex
-	encapsulationViolationLogger = os.Stderr
+	encapsulationViolationLogger *os.File // nil routes warnings through the framework logger
+
+	// encapsulationViolations counts detected violations keyed by "fromModule->toModule:service"
+	encapsulationViolations      = make(map[string]int)
+	encapsulationViolationsMutex sync.Mutex
 )
 
 // SetEncapsulationMode configures enforcement level
@@ -39,6 +43,7 @@ func GetEncapsulationMode() EncapsulationMode {
 }
 
 // SetEncapsulationViolationLogger sets the output for violation warnings
+// By default (nil) warnings go to the framework logger, or stderr when none is set
 func SetEncapsulationViolationLogger(w *os.File) {
 	encapsulationModeMutex.Lock()
 	defer encapsulationModeMutex.Unlock()
@@ -57,6 +62,8 @@ func CheckEncapsulationViolation(fromModule, toModule, serviceName string) (bool
 		return true, nil
 	}
 
+	recordEncapsulationViolation(fromModule, toModule, serviceName)
+
 	errMsg := fmt.Errorf(
 		"module '%s' cannot access unexported provider '%s' from module '%s'",
 		fromModule,
@@ -65,10 +72,56 @@ func CheckEncapsulationViolation(fromModule, toModule, serviceName string) (bool
 	)
 
 	if mode == EncapsulationWarn {
-		fmt.Fprintf(logger, "WARNING: %s\n", errMsg)
+		if logger != nil {
+			fmt.Fprintf(logger, "WARNING: %s\n", errMsg)
+		} else if appLogger := getFrameworkLogger(); appLogger != nil {
+			appLogger.Infor(&LoggerItem{
+				Level:    LevelWarn,
+				Event:    "EncapsulationViolation",
+				Messages: errMsg.Error(),
+				Error:    errMsg,
+				Data: map[string]string{
+					"fromModule": fromModule,
+					"toModule":   toModule,
+					"service":    serviceName,
+				},
+			})
+		} else {
+			fmt.Fprintf(os.Stderr, "WARNING: %s\n", errMsg)
+		}
 		return true, nil
 	}
 
 	// EncapsulationEnforce
 	return false, errMsg
-}
\ No newline at end of file
+}
+
+// recordEncapsulationViolation increments the counter for a module/service pair
+func recordEncapsulationViolation(fromModule, toModule, serviceName string) {
+	key := fmt.Sprintf("%s->%s:%s", fromModule, toModule, serviceName)
+
+	encapsulationViolationsMutex.Lock()
+	defer encapsulationViolationsMutex.Unlock()
+	encapsulationViolations[key]++
+}
+
+// GetEncapsulationViolations returns how many times each violation was detected
+// (in Warn or Enforce mode), keyed by "fromModule->toModule:service"
+// Run the test suite with EncapsulationWarn and sort by count to prioritize fixes before enforcing
+func GetEncapsulationViolations() map[string]int {
+	encapsulationViolationsMutex.Lock()
+	defer encapsulationViolationsMutex.Unlock()
+
+	violations := make(map[string]int, len(encapsulationViolations))
+	for key, count := range encapsulationViolations {
+		violations[key] = count
+	}
+	return violations
+}
+
+// ResetEncapsulationViolations clears the violation counters
+func ResetEncapsulationViolations() {
+	encapsulationViolationsMutex.Lock()
+	defer encapsulationViolationsMutex.Unlock()
+	encapsulationViolations = make(map[string]int)
+}
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 // Module represents a logical grouping of providers, controllers, and dependencies
@@ -22,6 +24,10 @@ type Module struct {
 	// Services exported by imported modules become available in this module's container
 	Imports []*Module
 
+	// ImportConstraints maps imported module names to the version range they must satisfy
+	// (e.g. "^1.0.0" or ">=1.2.0 <2.0.0"), checked when the modules are added to the graph
+	ImportConstraints map[string]string
+
 	// Providers are services registered in this module's DI container
 	// Changed from []Factory to []Provider in Phase 2
 	Providers []Provider
@@ -40,8 +46,21 @@ type Module struct {
 	// Global flag breaks encapsulation (fastify-plugin pattern)
 	// If true, all providers registered in root container
 	Global bool
+
+	// Lazy defers the module's async providers and eager singletons from startup to
+	// the first resolution of one of its services or request to one of its routes
+	Lazy bool
+
+	// ErrorHandler responds to the errors of requests routed to this module's routes
+	// in place of the JSON error envelope (see WithOnError)
+	ErrorHandler ModuleErrorHandler
 }
 
+// ModuleErrorHandler formats the error response of a request routed to a module
+// OnError hooks see the error as usual; when the handler writes nothing, the JSON
+// error envelope of ToAPIError(err) is sent
+type ModuleErrorHandler func(c *gin.Context, err error)
+
 // Controller placeholder (defined in Phase 5)
 type Controller interface{}
 
@@ -58,12 +77,28 @@ func NewModule(name, version string) *Module {
 	}
 }
 
+// WithDescription sets the human-readable module purpose
+func (m *Module) WithDescription(description string) *Module {
+	m.Description = description
+	return m
+}
+
 // WithImports adds import dependencies to the module
 func (m *Module) WithImports(imports ...*Module) *Module {
 	m.Imports = append(m.Imports, imports...)
 	return m
 }
 
+// WithImportConstraint requires the imported module with the given name to satisfy
+// the semver range, e.g. WithImportConstraint("database", ">=1.2.0")
+func (m *Module) WithImportConstraint(name, constraint string) *Module {
+	if m.ImportConstraints == nil {
+		m.ImportConstraints = make(map[string]string)
+	}
+	m.ImportConstraints[name] = constraint
+	return m
+}
+
 // WithProviders adds providers to the module
 func (m *Module) WithProviders(providers ...Provider) *Module {
 	m.Providers = append(m.Providers, providers...)
@@ -141,6 +176,19 @@ func (m *Module) AsGlobal() *Module {
 	return m
 }
 
+// AsLazy defers the module's async and eager initialization to first access (see Lazy)
+func (m *Module) AsLazy() *Module {
+	m.Lazy = true
+	return m
+}
+
+// WithOnError sets the handler formatting the error responses of the module's routes,
+// e.g. to answer in a legacy client's error format
+func (m *Module) WithOnError(handler ModuleErrorHandler) *Module {
+	m.ErrorHandler = handler
+	return m
+}
+
 // Validate checks if the module configuration is valid
 func (m *Module) Validate() error {
 	if m.Name == "" {
@@ -175,13 +223,24 @@ func (m *Module) Validate() error {
 		providerNames[name] = true
 	}
 
-	// Check that all exported providers exist
+	// Check that all exported providers exist, either in this module or
+	// re-exported from an imported module that exports them
 	for _, export := range m.Exports {
-		if !providerNames[export] {
+		if !providerNames[export] && m.ReExportSource(export) == nil {
 			return fmt.Errorf("exported provider '%s' not found in module '%s'", export, m.Name)
 		}
 	}
 
+	// Check import constraints refer to imports and parse
+	for name, constraint := range m.ImportConstraints {
+		if !contains(m.GetImportNames(), name) {
+			return fmt.Errorf("version constraint for '%s' in module '%s' but it is not imported", name, m.Name)
+		}
+		if _, err := parseVersionConstraint(constraint); err != nil {
+			return fmt.Errorf("import '%s' in module '%s': %w", name, m.Name, err)
+		}
+	}
+
 	// Validate module prefix
 	if err := m.ValidatePrefix(); err != nil {
 		return err
@@ -200,14 +259,24 @@ func (m *Module) IsExported(providerName string) bool {
 	return false
 }
 
+// ReExportSource returns the imported module that exports the given name, or nil
+// A module may list such a name in Exports to pass the service through to its importers
+func (m *Module) ReExportSource(providerName string) *Module {
+	for _, imported := range m.Imports {
+		if imported != nil && imported.IsExported(providerName) {
+			return imported
+		}
+	}
+	return nil
+}
+
 // ValidateExports checks all exported provider names exist in module (alias for Validate consistency)
 func (m *Module) ValidateExports() error {
 	return m.Validate()
 }
 
-// GetFullPrefix computes full prefix including parent prefixes
-// For now, returns the module's own prefix
-// TODO: In future phases, concatenate with parent module prefixes
+// GetFullPrefix returns the module's own normalized prefix
+// Use ModuleGraph.GetFullPrefix to include the prefixes of importing (parent) modules
 func (m *Module) GetFullPrefix() string {
 	if m.Prefix == "" {
 		return ""
@@ -239,6 +308,26 @@ func (m *Module) ValidatePrefix() error {
 	return nil
 }
 
+// CheckImportVersion verifies the imported module satisfies this module's constraint for it
+func (m *Module) CheckImportVersion(imported *Module) error {
+	constraint, exists := m.ImportConstraints[imported.Name]
+	if !exists {
+		return nil
+	}
+
+	ok, err := satisfiesVersion(imported.Version, constraint)
+	if err != nil {
+		return fmt.Errorf("module '%s' cannot check import '%s': %w", m.Name, imported.Name, err)
+	}
+	if !ok {
+		return fmt.Errorf(
+			"module '%s' requires '%s' version '%s', but version '%s' is registered",
+			m.Name, imported.Name, constraint, imported.Version,
+		)
+	}
+	return nil
+}
+
 // GetImportNames returns the names of all imported modules
 func (m *Module) GetImportNames() []string {
 	names := make([]string, len(m.Imports))
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 )
@@ -61,6 +62,12 @@ func (mc *ModuleContainer) GetDecorator(name string) (interface{}, bool) {
 	return nil, false
 }
 
+// CreateScope creates a ScopedContainer resolving through this module, decorators
+// and encapsulation included, e.g. for a job run by the module
+func (mc *ModuleContainer) CreateScope() DIContainer {
+	return NewScopedContainer(mc)
+}
+
 // CreateRequestScope creates a request-scoped container
 func (mc *ModuleContainer) CreateRequestScope() *RequestContainer {
 	return NewRequestContainer(mc)
@@ -103,6 +110,25 @@ func (mc *ModuleContainer) GetAllChildren() map[string]*ModuleContainer {
 	return result
 }
 
+// Resolve resolves a service by name, checking decorators first
+func (mc *ModuleContainer) Resolve(name string) (interface{}, error) {
+	return mc.ResolveWithContext(name, context.Background())
+}
+
+// ResolveAs resolves a service, decorators included, into the target pointer
+func (mc *ModuleContainer) ResolveAs(name string, target interface{}) error {
+	return mc.ResolveAsWithContext(name, context.Background(), target)
+}
+
+// ResolveAsWithContext resolves a service with context, decorators included, into the target pointer
+func (mc *ModuleContainer) ResolveAsWithContext(name string, ctx context.Context, target interface{}) error {
+	instance, err := mc.ResolveWithContext(name, ctx)
+	if err != nil {
+		return err
+	}
+	return assignResolved(name, instance, target)
+}
+
 // ResolveWithContext overrides parent resolution to check decorators first
 func (mc *ModuleContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
 	// Check decorators first
@@ -110,43 +136,11 @@ func (mc *ModuleContainer) ResolveWithContext(name string, ctx context.Context)
 		return value, nil
 	}
 
-	// Fall back to parent resolution
-	mc.mu.RLock()
-	service, exists := mc.services[name]
-	mc.mu.RUnlock()
-
-	if exists {
-		provider := service.Provider
-
-		switch provider.GetLifetime() {
-		case Singleton:
-			if service.Instance != nil {
-				return service.Instance, nil
-			}
-
-			// Create singleton instance
-			instance, err := provider.Resolve(mc, ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create singleton service '%s': %w", name, err)
-			}
-
-			// Store the instance
-			mc.mu.Lock()
-			service.Instance = instance
-			mc.mu.Unlock()
-
-			return instance, nil
-
-		case Transient:
-			return provider.Resolve(mc, ctx)
-
-		case Scoped:
-			// For scoped services, always create a new instance
-			return provider.Resolve(mc, ctx)
-
-		default:
-			return nil, fmt.Errorf("unknown lifetime for service '%s'", name)
-		}
+	// Services registered on this module; the base container's lock guards them
+	if service, exists := mc.lookupService(name); exists {
+		// Singletons are cached on the module container, so every request container
+		// of this module (and of its child modules) shares the instance
+		return mc.resolveService(mc, name, service, ctx)
 	}
 
 	// Check parent container
@@ -155,7 +149,8 @@ func (mc *ModuleContainer) ResolveWithContext(name string, ctx context.Context)
 		if parentModule, ok := mc.parent.(*ModuleContainer); ok {
 			// Skip validation if either module is Global
 			if !mc.module.Global && !parentModule.module.Global {
-				// Check if the service is exported by parent module
+				// Check if the service is exported by parent module; a re-exported
+				// name is then checked again between the parent and its own parent
 				if !parentModule.module.IsExported(name) {
 					// Check encapsulation mode
 					allowed, err := CheckEncapsulationViolation(
@@ -176,7 +171,44 @@ func (mc *ModuleContainer) ResolveWithContext(name string, ctx context.Context)
 		return mc.parent.Resolve(name)
 	}
 
-	return nil, fmt.Errorf("service '%s' is not registered in module '%s'", name, mc.module.Name)
+	return nil, &ServiceNotRegisteredError{Name: name, Module: mc.module.Name}
+}
+
+// ResolveOptional resolves a service, decorators included, reporting whether it is registered
+func (mc *ModuleContainer) ResolveOptional(name string) (interface{}, bool, error) {
+	return mc.ResolveOptionalWithContext(name, context.Background())
+}
+
+// ResolveOptionalWithContext is ResolveOptional with a context
+// A service hidden by encapsulation counts as registered and returns the violation
+func (mc *ModuleContainer) ResolveOptionalWithContext(name string, ctx context.Context) (interface{}, bool, error) {
+	return resolveOptional(mc, name, ctx)
+}
+
+// ResolveGroup resolves every member of the group visible to this module
+func (mc *ModuleContainer) ResolveGroup(group string) ([]interface{}, error) {
+	return mc.ResolveGroupWithContext(group, context.Background())
+}
+
+// ResolveGroupWithContext resolves the parent's group members, then this module's
+func (mc *ModuleContainer) ResolveGroupWithContext(group string, ctx context.Context) ([]interface{}, error) {
+	return resolveGroup(mc, mc.diContainer, group, ctx)
+}
+
+// Dispose closes singletons of child module containers, then this container's own
+func (mc *ModuleContainer) Dispose() error {
+	var errs []error
+	for _, child := range mc.GetAllChildren() {
+		if err := child.Dispose(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := mc.diContainer.Dispose(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
 // Validate checks if the module container is valid
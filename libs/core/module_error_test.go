@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingModulePlugin registers routes failing in different ways through its module's
+// enhanced router
+type failingModulePlugin struct {
+	*moduleTestPlugin
+}
+
+func (p *failingModulePlugin) Init(app *DoffApp) error {
+	router := app.GetPluginManager().GetEnhancedRouterForModule(p.Name())
+	router.GET(RouteConfig{Path: "aborted"}, func(c *gin.Context, controller *routeTestController) {
+		AbortWithError(c, errors.New(p.Name()+" database down"))
+	})
+	router.GET(RouteConfig{Path: "recorded"}, func(c *gin.Context, controller *routeTestController) {
+		c.Error(errors.New(p.Name() + " queue full"))
+	})
+	router.GET(RouteConfig{Path: "panicked"}, func(c *gin.Context, controller *routeTestController) {
+		panic(p.Name() + " nil map")
+	})
+	router.GET(RouteConfig{Path: "missing"}, func(c *gin.Context, controller *routeTestController) {
+		AbortWithError(c, ErrNotFound)
+	})
+	return nil
+}
+
+func TestModule_WithOnErrorFormatsModuleErrors(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+	app.GetEngine().GET("/plain", func(c *gin.Context) {
+		AbortWithError(c, errors.New("boom"))
+	})
+
+	var hookErrors []string
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrors = append(hookErrors, err.Error())
+	}))
+
+	billing := NewModule("billing", "1.0.0").WithPrefix("/billing").
+		WithOnError(func(c *gin.Context, err error) {
+			apiErr := ToAPIError(err)
+			c.JSON(apiErr.Status, gin.H{"billingError": apiErr.Code, "reason": err.Error()})
+		})
+	shipping := NewModule("shipping", "1.0.0").WithPrefix("/shipping").
+		WithOnError(func(c *gin.Context, err error) {
+			if errors.Is(err, ErrNotFound) {
+				return // Keeps the default envelope
+			}
+			c.String(http.StatusInternalServerError, "shipping failed: "+err.Error())
+		})
+	require.NoError(t, app.RegisterPlugin(&failingModulePlugin{moduleTestPlugin: newModuleTestPlugin(billing)}))
+	require.NoError(t, app.RegisterPlugin(&failingModulePlugin{moduleTestPlugin: newModuleTestPlugin(shipping)}))
+	require.NoError(t, app.Init())
+
+	for _, path := range []string{"/billing/aborted", "/billing/recorded", "/billing/panicked"} {
+		recorder := serveMount(app, http.MethodGet, path)
+		require.Equal(t, http.StatusInternalServerError, recorder.Code, path)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body), path)
+		assert.Equal(t, ErrInternal.Code, body["billingError"], path)
+		assert.Contains(t, body["reason"], "billing", path)
+		assert.NotContains(t, body, "error", path)
+	}
+
+	recorder := serveMount(app, http.MethodGet, "/shipping/aborted")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "shipping failed: shipping database down", recorder.Body.String())
+	recorder = serveMount(app, http.MethodGet, "/shipping/panicked")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "shipping failed: panic: shipping nil map")
+
+	// A handler writing nothing, and routes outside modules, keep the JSON error envelope
+	for path, status := range map[string]int{"/shipping/missing": http.StatusNotFound, "/plain": http.StatusInternalServerError} {
+		recorder := serveMount(app, http.MethodGet, path)
+		assert.Equal(t, status, recorder.Code, path)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body), path)
+		assert.Contains(t, body, "error", path)
+	}
+
+	// OnError hooks still see the errors
+	assert.Contains(t, hookErrors, "billing database down")
+	assert.Contains(t, hookErrors, "shipping database down")
+}
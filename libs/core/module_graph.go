@@ -2,13 +2,17 @@ package core
 
 import (
 	"fmt"
+	"maps"
+	"path"
 	"sort"
+	"strings"
 )
 
 // ModuleGraph manages module dependencies and initialization order
 type ModuleGraph struct {
 	modules map[string]*Module
 	edges   map[string][]string // module name -> dependency names
+	mounts  map[string]string   // module name -> prefix set by Mount, replacing its full prefix
 }
 
 // NewModuleGraph creates a new module graph
@@ -16,6 +20,7 @@ func NewModuleGraph() *ModuleGraph {
 	return &ModuleGraph{
 		modules: make(map[string]*Module),
 		edges:   make(map[string][]string),
+		mounts:  make(map[string]string),
 	}
 }
 
@@ -25,17 +30,34 @@ func (g *ModuleGraph) AddModule(module *Module) error {
 		return fmt.Errorf("module cannot be nil")
 	}
 
-	// Validate module before adding
+	if _, exists := g.modules[module.Name]; exists {
+		return fmt.Errorf("module '%s' already registered: to depend on it, list it in Imports "+
+			"(a DefaultModule(\"%s\", version) reference is enough) rather than registering it again",
+			module.Name, module.Name)
+	}
+
+	// Validate module before adding, with its imports linked so re-exports are
+	// checked against the registered modules
+	g.linkImports(module)
 	if err := module.Validate(); err != nil {
 		return fmt.Errorf("module validation failed: %w", err)
 	}
 
-	if _, exists := g.modules[module.Name]; exists {
-		return fmt.Errorf("module '%s' already registered", module.Name)
+	if err := g.checkImportVersions(module); err != nil {
+		return err
 	}
 
 	g.modules[module.Name] = module
 
+	// Link the imports of registered modules that referred to this one by name
+	for _, importer := range g.modules {
+		for i, imported := range importer.Imports {
+			if imported != nil && imported != module && imported.Name == module.Name {
+				importer.Imports[i] = module
+			}
+		}
+	}
+
 	// Build dependency edges
 	dependencies := make([]string, len(module.Imports))
 	for i, dep := range module.Imports {
@@ -46,6 +68,56 @@ func (g *ModuleGraph) AddModule(module *Module) error {
 	return nil
 }
 
+// linkImports replaces the imports that refer to a registered module by name, such
+// as DefaultModule(name, version) references, with the registered instance, so its
+// exports, re-exports and prefix are those of the real module
+// Imports of modules registered later are linked by AddModule when they are
+func (g *ModuleGraph) linkImports(module *Module) {
+	for i, imported := range module.Imports {
+		if imported == nil {
+			continue
+		}
+		if registered, exists := g.modules[imported.Name]; exists {
+			module.Imports[i] = registered
+		}
+	}
+}
+
+// RemoveModule removes a module that no other module imports
+func (g *ModuleGraph) RemoveModule(name string) error {
+	dependents, err := g.GetDependents(name)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		names := make([]string, len(dependents))
+		for i, dependent := range dependents {
+			names[i] = dependent.Name
+		}
+		sort.Strings(names)
+		return fmt.Errorf("module '%s' is imported by %s", name, strings.Join(names, ", "))
+	}
+
+	delete(g.modules, name)
+	delete(g.edges, name)
+	delete(g.mounts, name)
+	return nil
+}
+
+// Mount sets the prefix a module's routes are served under at runtime, replacing its
+// declared Prefix and those of the modules importing it; the modules it imports
+// are served under the new prefix too. An empty prefix mounts it at the root
+func (g *ModuleGraph) Mount(name, prefix string) error {
+	if _, exists := g.modules[name]; !exists {
+		return fmt.Errorf("module '%s' not found", name)
+	}
+	if strings.Contains(prefix, "..") {
+		return fmt.Errorf("mount prefix '%s' contains invalid path traversal", prefix)
+	}
+	g.mounts[name] = joinPrefixes(prefix)
+	return nil
+}
+
 // GetModule returns a module by name
 func (g *ModuleGraph) GetModule(name string) (*Module, bool) {
 	module, exists := g.modules[name]
@@ -62,7 +134,8 @@ func (g *ModuleGraph) GetAllModules() []*Module {
 }
 
 // TopologicalSort returns modules in dependency order
-// Dependencies come before dependents in the result
+// Dependencies come before dependents in the result; the order is stable across
+// runs, with modules that do not depend on each other ordered by name
 func (g *ModuleGraph) TopologicalSort() ([]*Module, error) {
 	visited := make(map[string]bool)
 	temp := make(map[string]bool)
@@ -98,19 +171,21 @@ func (g *ModuleGraph) TopologicalSort() ([]*Module, error) {
 		return nil
 	}
 
-	// Visit all modules in dependency order
-	// Build a list of modules ordered by their dependencies
-	moduleOrder := make([]string, 0, len(g.modules))
-
-	// Find modules with no dependencies first
+	// Visit modules without dependencies first, each bucket by name, so modules
+	// that do not depend on each other always come out in the same order
+	names := make([]string, 0, len(g.modules))
 	for name := range g.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	moduleOrder := make([]string, 0, len(names))
+	for _, name := range names {
 		if len(g.edges[name]) == 0 {
 			moduleOrder = append(moduleOrder, name)
 		}
 	}
-
-	// Then add modules with dependencies
-	for name := range g.modules {
+	for _, name := range names {
 		if len(g.edges[name]) > 0 {
 			moduleOrder = append(moduleOrder, name)
 		}
@@ -124,14 +199,8 @@ func (g *ModuleGraph) TopologicalSort() ([]*Module, error) {
 		}
 	}
 
-	// Post-order gives us dependents first, dependencies last
-	// Reverse to get dependencies first
-	result := make([]*Module, len(postOrder))
-	for i, module := range postOrder {
-		result[len(postOrder)-1-i] = module
-	}
-
-	return result, nil
+	// Post-order already lists every module after its dependencies
+	return postOrder, nil
 }
 
 // buildCyclePath constructs a readable path for circular dependency error
@@ -195,6 +264,82 @@ func (g *ModuleGraph) GetDependents(moduleName string) ([]*Module, error) {
 	return dependents, nil
 }
 
+// GetFullPrefix composes a module's prefix with the prefixes of the modules importing it
+// e.g. "users" (/users) imported by "api" (/api) resolves to /api/users
+// A module without its own prefix inherits its parent's. Several modules may import
+// the same module as long as they resolve to the same prefix; when their prefixes
+// differ the module inherits none (see ValidateParent)
+// A module given a prefix with Mount resolves to that prefix
+func (g *ModuleGraph) GetFullPrefix(moduleName string) string {
+	return g.fullPrefix(moduleName, make(map[string]bool))
+}
+
+// fullPrefix walks the parent chain, stopping on unknown modules or cycles
+func (g *ModuleGraph) fullPrefix(moduleName string, visited map[string]bool) string {
+	module, exists := g.modules[moduleName]
+	if !exists || visited[moduleName] {
+		return ""
+	}
+	visited[moduleName] = true
+
+	if mount, mounted := g.mounts[moduleName]; mounted {
+		return mount
+	}
+
+	parent, err := g.parentOf(moduleName, visited)
+	if parent == "" || err != nil {
+		return module.GetFullPrefix()
+	}
+	return joinPrefixes(g.fullPrefix(parent, visited), module.GetFullPrefix())
+}
+
+// parentOf returns the module whose prefix the given module inherits: its importer, or
+// the first by name when several importers resolve to the same prefix
+// Importers with different prefixes return ErrAmbiguousModuleParent; visited holds the
+// modules already on the walk, so cycles stop
+func (g *ModuleGraph) parentOf(moduleName string, visited map[string]bool) (string, error) {
+	var importers []string
+	for name, edges := range g.edges {
+		if contains(edges, moduleName) {
+			importers = append(importers, name)
+		}
+	}
+	if len(importers) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(importers)
+	prefix := g.fullPrefix(importers[0], maps.Clone(visited))
+	for _, other := range importers[1:] {
+		if otherPrefix := g.fullPrefix(other, maps.Clone(visited)); otherPrefix != prefix {
+			return "", fmt.Errorf("%w: module '%s' is imported by '%s' (%s) and '%s' (%s); mount it to choose its prefix",
+				ErrAmbiguousModuleParent, moduleName, importers[0], prefix, other, otherPrefix)
+		}
+	}
+	return importers[0], nil
+}
+
+// ValidateParent fails with ErrAmbiguousModuleParent when a module declaring a prefix of
+// its own is imported by modules with different prefixes, so the prefix it nests under
+// is ambiguous; modules without a prefix (e.g. shared services) and mounted modules pass
+func (g *ModuleGraph) ValidateParent(name string) error {
+	module, exists := g.modules[name]
+	if _, mounted := g.mounts[name]; !exists || mounted || module.GetFullPrefix() == "" {
+		return nil
+	}
+	_, err := g.parentOf(name, map[string]bool{name: true})
+	return err
+}
+
+// joinPrefixes joins route prefixes without duplicated slashes
+func joinPrefixes(prefixes ...string) string {
+	joined := path.Join(append([]string{"/"}, prefixes...)...)
+	if joined == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(joined, "/")
+}
+
 // ValidateGraph checks for common issues like missing dependencies
 func (g *ModuleGraph) ValidateGraph() error {
 	// Check for missing dependencies
@@ -212,24 +357,63 @@ func (g *ModuleGraph) ValidateGraph() error {
 		return fmt.Errorf("graph validation failed: %w", err)
 	}
 
+	// Check for modules nested under importers with different prefixes
+	names := make([]string, 0, len(g.modules))
+	for name := range g.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.ValidateParent(name); err != nil {
+			return fmt.Errorf("graph validation failed: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// ValidateImports checks all imported modules exist and are registered
+// ValidateImports checks all imported modules exist, are registered and satisfy
+// the module's version constraints
 func (g *ModuleGraph) ValidateImports(module *Module) error {
 	for _, imported := range module.Imports {
-		if _, exists := g.modules[imported.Name]; !exists {
+		registered, exists := g.modules[imported.Name]
+		if !exists {
 			return fmt.Errorf(
 				"module '%s' imports non-existent module '%s'",
 				module.Name,
 				imported.Name,
 			)
 		}
+		if err := module.CheckImportVersion(registered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkImportVersions checks the version constraints between a module being added
+// and the registered modules, in both directions, so registration order does not matter
+func (g *ModuleGraph) checkImportVersions(module *Module) error {
+	for _, imported := range module.Imports {
+		if registered, exists := g.modules[imported.Name]; exists {
+			if err := module.CheckImportVersion(registered); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, importer := range g.modules {
+		if contains(g.edges[importer.Name], module.Name) {
+			if err := importer.CheckImportVersion(module); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
 // ValidateExportAccess checks module only accesses exported providers from imports
+// Names an import re-exports from its own imports count as exported by it
 // This is a static analysis helper; actual enforcement in ResolveWithContext
 func (g *ModuleGraph) ValidateExportAccess(module *Module, providerName string) error {
 	// Check if provider exists in any imported module
@@ -254,15 +438,16 @@ func (g *ModuleGraph) Clone() *ModuleGraph {
 	// Clone modules
 	for name, module := range g.modules {
 		cloneModule := &Module{
-			Name:        module.Name,
-			Version:     module.Version,
-			Description: module.Description,
-			Imports:     make([]*Module, len(module.Imports)),
-			Providers:   make([]Provider, len(module.Providers)),
-			Exports:     make([]string, len(module.Exports)),
-			Controllers: make([]Controller, len(module.Controllers)),
-			Prefix:      module.Prefix,
-			Global:      module.Global,
+			Name:         module.Name,
+			Version:      module.Version,
+			Description:  module.Description,
+			Imports:      make([]*Module, len(module.Imports)),
+			Providers:    make([]Provider, len(module.Providers)),
+			Exports:      make([]string, len(module.Exports)),
+			Controllers:  make([]Controller, len(module.Controllers)),
+			Prefix:       module.Prefix,
+			Global:       module.Global,
+			ErrorHandler: module.ErrorHandler,
 		}
 
 		// Copy slices
@@ -271,6 +456,13 @@ func (g *ModuleGraph) Clone() *ModuleGraph {
 		copy(cloneModule.Exports, module.Exports)
 		copy(cloneModule.Controllers, module.Controllers)
 
+		if module.ImportConstraints != nil {
+			cloneModule.ImportConstraints = make(map[string]string, len(module.ImportConstraints))
+			for importName, constraint := range module.ImportConstraints {
+				cloneModule.ImportConstraints[importName] = constraint
+			}
+		}
+
 		clone.modules[name] = cloneModule
 	}
 
@@ -281,5 +473,9 @@ func (g *ModuleGraph) Clone() *ModuleGraph {
 		clone.edges[name] = cloneEdges
 	}
 
+	for name, prefix := range g.mounts {
+		clone.mounts[name] = prefix
+	}
+
 	return clone
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PrefixCollisionMode controls what registering a module whose route prefix overlaps
+// another module's does, e.g. "/api" and "/api/v1", which makes route ownership ambiguous
+type PrefixCollisionMode int
+
+const (
+	// PrefixCollisionsAllowed - No check (the default)
+	PrefixCollisionsAllowed PrefixCollisionMode = iota
+	// PrefixCollisionsWarn - Log overlaps, register the module anyway
+	PrefixCollisionsWarn
+	// PrefixCollisionsEnforce - Refuse to register the module
+	PrefixCollisionsEnforce
+)
+
+// SetPrefixCollisionMode configures the check RegisterPlugin and MountModule run on
+// module prefixes
+func (pm *PluginManager) SetPrefixCollisionMode(mode PrefixCollisionMode) {
+	pm.prefixCollisions = mode
+}
+
+// PrefixCollisions returns the modules whose full prefix equals the named module's or
+// contains it, or is contained by it, sorted by name
+// Modules nested through imports (e.g. "users" under "api") do not collide, nor do
+// modules without a prefix of their own
+func (g *ModuleGraph) PrefixCollisions(name string) []string {
+	if !g.claimsPrefix(name) {
+		return nil
+	}
+	prefix := g.GetFullPrefix(name)
+
+	var collisions []string
+	for other := range g.modules {
+		if other == name || !g.claimsPrefix(other) || g.isAncestor(name, other) || g.isAncestor(other, name) {
+			continue
+		}
+		if prefixesOverlap(prefix, g.GetFullPrefix(other)) {
+			collisions = append(collisions, other)
+		}
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// claimsPrefix reports whether a module declares or was mounted at a prefix
+func (g *ModuleGraph) claimsPrefix(name string) bool {
+	if _, mounted := g.mounts[name]; mounted {
+		return true
+	}
+	module, exists := g.modules[name]
+	return exists && module.GetFullPrefix() != ""
+}
+
+// isAncestor reports whether ancestor is found walking up the importers of name
+func (g *ModuleGraph) isAncestor(ancestor, name string) bool {
+	visited := map[string]bool{name: true}
+	for parent, _ := g.parentOf(name, visited); parent != "" && !visited[parent]; parent, _ = g.parentOf(parent, visited) {
+		if parent == ancestor {
+			return true
+		}
+		visited[parent] = true
+	}
+	return false
+}
+
+// prefixesOverlap reports whether two route prefixes are equal or one contains the other
+func prefixesOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+"/") || strings.HasPrefix(a, b+"/")
+}
+
+// checkPrefixCollisions applies the prefix collision mode to a registered module
+func (pm *PluginManager) checkPrefixCollisions(name string) error {
+	if pm.prefixCollisions == PrefixCollisionsAllowed {
+		return nil
+	}
+	collisions := pm.modules.PrefixCollisions(name)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	described := make([]string, len(collisions))
+	for i, other := range collisions {
+		described[i] = fmt.Sprintf("'%s' (%s)", other, pm.modules.GetFullPrefix(other))
+	}
+	err := fmt.Errorf("%w: module '%s' (%s) overlaps %s",
+		ErrPrefixCollision, name, pm.modules.GetFullPrefix(name), strings.Join(described, ", "))
+
+	if pm.prefixCollisions == PrefixCollisionsEnforce {
+		return err
+	}
+	if logger := getFrameworkLogger(); logger != nil {
+		logger.Infor(&LoggerItem{
+			Level:    LevelWarn,
+			Event:    "ModulePrefixCollision",
+			Messages: err.Error(),
+			Error:    err,
+			Data: map[string]interface{}{
+				"module":     name,
+				"collisions": collisions,
+			},
+		})
+	}
+	return nil
+}
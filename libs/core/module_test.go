@@ -1,6 +1,10 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -75,6 +79,38 @@ func TestModule_WithMethods(t *testing.T) {
 	}
 }
 
+func TestModule_FullFluentChain(t *testing.T) {
+	dep := NewModule("database", "1.2.0")
+
+	module := NewModule("users", "1.0.0").
+		WithDescription("User management").
+		WithImports(dep).
+		WithImportConstraint("database", "^1.0.0").
+		WithProviders(NewValueProvider("usersConfig", "config")).
+		AddFactoryProvider("usersService", func(c DIContainer) (interface{}, error) {
+			return "users", nil
+		}, Singleton).
+		WithExports("usersService").
+		WithPrefix("/users").
+		AsLazy()
+
+	if module.Description != "User management" {
+		t.Errorf("Expected description 'User management', got '%s'", module.Description)
+	}
+	if module.ImportConstraints["database"] != "^1.0.0" {
+		t.Errorf("Expected constraint '^1.0.0', got '%s'", module.ImportConstraints["database"])
+	}
+	if len(module.Providers) != 2 || !module.IsExported("usersService") {
+		t.Errorf("Expected 2 providers with 'usersService' exported, got %d", len(module.Providers))
+	}
+	if module.Prefix != "/users" || !module.Lazy {
+		t.Errorf("Expected lazy module with prefix '/users', got '%s' (lazy: %v)", module.Prefix, module.Lazy)
+	}
+	if err := module.Validate(); err != nil {
+		t.Errorf("Expected valid module, got: %v", err)
+	}
+}
+
 func TestModule_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -241,6 +277,34 @@ func TestModuleGraph_TopologicalSort(t *testing.T) {
 	}
 }
 
+func TestModuleGraph_TopologicalSortIsStable(t *testing.T) {
+	graph := NewModuleGraph()
+	database := NewModule("database", "1.0.0")
+	cache := NewModule("cache", "1.0.0")
+	graph.AddModule(NewModule("metrics", "1.0.0"))
+	graph.AddModule(database)
+	graph.AddModule(cache)
+	graph.AddModule(NewModule("users", "1.0.0").WithImports(database, cache))
+	graph.AddModule(NewModule("audit", "1.0.0").WithImports(database))
+	graph.AddModule(NewModule("auth", "1.0.0"))
+
+	expected := []string{"auth", "cache", "database", "metrics", "audit", "users"}
+	for i := 0; i < 50; i++ {
+		sorted, err := graph.TopologicalSort()
+		if err != nil {
+			t.Fatalf("TopologicalSort() error = %v", err)
+		}
+
+		names := make([]string, len(sorted))
+		for j, module := range sorted {
+			names[j] = module.Name
+		}
+		if !slices.Equal(names, expected) {
+			t.Fatalf("run %d: TopologicalSort() = %v, want %v", i, names, expected)
+		}
+	}
+}
+
 func TestModuleGraph_CircularDependency(t *testing.T) {
 	// Create circular dependency: a -> b -> c -> a
 	c := NewModule("c", "1.0.0")
@@ -267,6 +331,58 @@ func TestModuleGraph_CircularDependency(t *testing.T) {
 	}
 }
 
+func TestModuleGraph_ImportConstraints(t *testing.T) {
+	database := NewModule("database", "1.1.0")
+	api := NewModule("api", "1.0.0").
+		WithImports(database).
+		WithImportConstraint("database", ">=1.2.0")
+
+	graph := NewModuleGraph()
+	if err := graph.AddModule(database); err != nil {
+		t.Fatalf("AddModule(database) failed: %v", err)
+	}
+
+	err := graph.AddModule(api)
+	want := "module 'api' requires 'database' version '>=1.2.0', but version '1.1.0' is registered"
+	if err == nil || err.Error() != want {
+		t.Fatalf("AddModule(api) error = %v, want %v", err, want)
+	}
+	if _, exists := graph.GetModule("api"); exists {
+		t.Error("api should not be registered")
+	}
+
+	// The importer may be registered before the imported module
+	graph = NewModuleGraph()
+	if err := graph.AddModule(api); err != nil {
+		t.Fatalf("AddModule(api) failed: %v", err)
+	}
+	if err := graph.AddModule(database); err == nil {
+		t.Error("AddModule(database) should fail the registered constraint")
+	}
+
+	database.Version = "1.4.0"
+	if err := graph.AddModule(database); err != nil {
+		t.Fatalf("AddModule(database) failed: %v", err)
+	}
+	if err := graph.ValidateImports(api); err != nil {
+		t.Errorf("ValidateImports() error = %v", err)
+	}
+}
+
+func TestModule_ValidateImportConstraints(t *testing.T) {
+	database := NewModule("database", "1.0.0")
+
+	unknown := NewModule("api", "1.0.0").WithImportConstraint("cache", "^1.0.0")
+	if err := unknown.Validate(); err == nil {
+		t.Error("Expected error for constraint on a module that is not imported")
+	}
+
+	invalid := NewModule("api", "1.0.0").WithImports(database).WithImportConstraint("database", ">=abc")
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected error for an invalid constraint")
+	}
+}
+
 func TestModuleGraph_GetModule(t *testing.T) {
 	graph := NewModuleGraph()
 	module := NewModule("test", "1.0.0")
@@ -339,6 +455,161 @@ func TestModuleGraph_GetDependents(t *testing.T) {
 	}
 }
 
+func TestModuleGraph_GetFullPrefix(t *testing.T) {
+	graph := NewModuleGraph()
+
+	users := NewModule("users", "1.0.0").WithPrefix("/users/")
+	shared := NewModule("shared", "1.0.0")
+	profiles := NewModule("profiles", "1.0.0").WithPrefix("/profiles")
+	account := NewModule("account", "1.0.0").WithImports(profiles)
+	api := NewModule("api", "1.0.0").WithPrefix("/api/").WithImports(users, shared, account)
+	standalone := NewModule("standalone", "1.0.0").WithPrefix("/standalone")
+
+	for _, module := range []*Module{users, shared, profiles, account, api, standalone} {
+		if err := graph.AddModule(module); err != nil {
+			t.Fatalf("AddModule(%s) error = %v", module.Name, err)
+		}
+	}
+
+	tests := []struct {
+		module   string
+		expected string
+	}{
+		{"api", "/api"},
+		{"users", "/api/users"},
+		{"shared", "/api"},               // No prefix: inherits the parent's
+		{"profiles", "/api/profiles"},    // Parent without prefix is skipped
+		{"standalone", "/standalone"},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := graph.GetFullPrefix(tt.module); got != tt.expected {
+			t.Errorf("GetFullPrefix(%s) = '%s', expected '%s'", tt.module, got, tt.expected)
+		}
+	}
+}
+
+func TestModuleGraph_PrefixCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		added    string
+		collides bool
+	}{
+		{"equal", "/api", "/api", true},
+		{"added contains existing", "/api/v1", "/api", true},
+		{"existing contains added", "/api", "/api/v1/", true},
+		{"shared text only", "/api", "/apis", false},
+		{"disjoint", "/users", "/orders", false},
+		{"no prefix", "", "/api", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewModuleGraph()
+			graph.AddModule(NewModule("existing", "1.0.0").WithPrefix(tt.existing))
+			graph.AddModule(NewModule("added", "1.0.0").WithPrefix(tt.added))
+
+			collisions := graph.PrefixCollisions("added")
+			if got := len(collisions) == 1 && collisions[0] == "existing"; got != tt.collides {
+				t.Errorf("PrefixCollisions(%q vs %q) = %v, expected collision %v", tt.added, tt.existing, collisions, tt.collides)
+			}
+		})
+	}
+}
+
+func TestModuleGraph_PrefixCollisionsIgnoreNestedModules(t *testing.T) {
+	graph := NewModuleGraph()
+	users := NewModule("users", "1.0.0").WithPrefix("/users")
+	api := NewModule("api", "1.0.0").WithPrefix("/api").WithImports(users)
+	legacy := NewModule("legacy", "1.0.0").WithPrefix("/api/users")
+	for _, module := range []*Module{users, api, legacy} {
+		if err := graph.AddModule(module); err != nil {
+			t.Fatalf("AddModule(%s) error = %v", module.Name, err)
+		}
+	}
+
+	// "users" is served under "api" by design, but "legacy" claims its prefix
+	if got := graph.PrefixCollisions("api"); !slices.Equal(got, []string{"legacy"}) {
+		t.Errorf("PrefixCollisions(api) = %v, expected [legacy]", got)
+	}
+	if got := graph.PrefixCollisions("users"); !slices.Equal(got, []string{"legacy"}) {
+		t.Errorf("PrefixCollisions(users) = %v, expected [legacy]", got)
+	}
+
+	// Mounting elsewhere resolves the overlap
+	graph.Mount("legacy", "/v0/users")
+	if got := graph.PrefixCollisions("legacy"); len(got) != 0 {
+		t.Errorf("PrefixCollisions(legacy) = %v after Mount, expected none", got)
+	}
+}
+
+func TestModuleGraph_AmbiguousParent(t *testing.T) {
+	graph := NewModuleGraph()
+	reports := NewModule("reports", "1.0.0").WithPrefix("/reports")
+	shared := NewModule("shared", "1.0.0")
+	users := NewModule("users", "1.0.0").WithPrefix("/users").WithImports(reports, shared)
+	orders := NewModule("orders", "1.0.0").WithPrefix("/orders").WithImports(reports, shared)
+	for _, module := range []*Module{reports, shared, users, orders} {
+		if err := graph.AddModule(module); err != nil {
+			t.Fatalf("AddModule(%s) error = %v", module.Name, err)
+		}
+	}
+
+	// Neither importer is picked: the module keeps its own prefix and validation fails
+	if got := graph.GetFullPrefix("reports"); got != "/reports" {
+		t.Errorf("GetFullPrefix(reports) = '%s', expected '/reports'", got)
+	}
+	if err := graph.ValidateParent("reports"); !errors.Is(err, ErrAmbiguousModuleParent) {
+		t.Errorf("ValidateParent(reports) error = %v, expected ErrAmbiguousModuleParent", err)
+	}
+	err := graph.ValidateGraph()
+	if !errors.Is(err, ErrAmbiguousModuleParent) ||
+		!strings.Contains(err.Error(), "module 'reports' is imported by 'orders' (/orders) and 'users' (/users)") {
+		t.Errorf("ValidateGraph() error = %v, expected the ambiguous parents of 'reports'", err)
+	}
+
+	// A module without a prefix of its own, e.g. shared services, is not nested anywhere
+	if err := graph.ValidateParent("shared"); err != nil {
+		t.Errorf("ValidateParent(shared) error = %v", err)
+	}
+	if got := graph.GetFullPrefix("shared"); got != "" {
+		t.Errorf("GetFullPrefix(shared) = '%s', expected ''", got)
+	}
+
+	// Mounting the module chooses its prefix
+	if err := graph.Mount("reports", "/users/reports"); err != nil {
+		t.Fatalf("Mount() error = %v", err)
+	}
+	if err := graph.ValidateGraph(); err != nil {
+		t.Errorf("ValidateGraph() error = %v after Mount", err)
+	}
+	if got := graph.GetFullPrefix("reports"); got != "/users/reports" {
+		t.Errorf("GetFullPrefix(reports) = '%s', expected '/users/reports'", got)
+	}
+}
+
+func TestModuleGraph_ImportersWithSamePrefix(t *testing.T) {
+	graph := NewModuleGraph()
+	reports := NewModule("reports", "1.0.0").WithPrefix("/reports")
+	admin := NewModule("admin", "1.0.0").WithImports(reports)
+	audit := NewModule("audit", "1.0.0").WithImports(reports)
+	api := NewModule("api", "1.0.0").WithPrefix("/api").WithImports(admin, audit)
+	for _, module := range []*Module{reports, admin, audit, api} {
+		if err := graph.AddModule(module); err != nil {
+			t.Fatalf("AddModule(%s) error = %v", module.Name, err)
+		}
+	}
+
+	if err := graph.ValidateGraph(); err != nil {
+		t.Errorf("ValidateGraph() error = %v", err)
+	}
+	if got := graph.GetFullPrefix("reports"); got != "/api/reports" {
+		t.Errorf("GetFullPrefix(reports) = '%s', expected '/api/reports'", got)
+	}
+}
+
 func TestModuleGraph_ValidateGraph(t *testing.T) {
 	graph := NewModuleGraph()
 
@@ -423,4 +694,75 @@ func BenchmarkModuleGraph_TopologicalSort(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		graph.TopologicalSort()
 	}
-}
\ No newline at end of file
+}
+
+func TestModuleGraph_ImportReferencesLinkToRegisteredModules(t *testing.T) {
+	graph := NewModuleGraph()
+
+	database := NewModule("database", "1.2.0").
+		WithProviders(NewValueProvider("db", "postgres")).
+		WithExports("db").
+		WithPrefix("/db")
+	if err := graph.AddModule(database); err != nil {
+		t.Fatalf("Expected database to register, got %v", err)
+	}
+
+	// users declares its import as a lightweight reference and re-exports "db"
+	users := NewModule("users", "1.0.0").
+		WithImports(DefaultModule("database", "1.0.0")).
+		WithExports("db")
+	if err := graph.AddModule(users); err != nil {
+		t.Fatalf("Expected the import reference to link to the registered module, got %v", err)
+	}
+	if users.Imports[0] != database {
+		t.Errorf("Expected the import of users to be the registered database module")
+	}
+	if err := graph.ValidateExportAccess(users, "db"); err != nil {
+		t.Errorf("Expected 'db' to be exported through the linked import, got %v", err)
+	}
+	dependencies, _ := graph.GetDependencies("users")
+	if len(dependencies) != 1 || dependencies[0] != database {
+		t.Errorf("Expected users to depend on the registered database module, got %v", dependencies)
+	}
+
+	// A reference to a module registered later is linked once it registers
+	reports := NewModule("reports", "1.0.0").WithImports(DefaultModule("analytics", "1.0.0"))
+	if err := graph.AddModule(reports); err != nil {
+		t.Fatalf("Expected reports to register, got %v", err)
+	}
+	analytics := NewModule("analytics", "2.0.0")
+	if err := graph.AddModule(analytics); err != nil {
+		t.Fatalf("Expected analytics to register, got %v", err)
+	}
+	if reports.Imports[0] != analytics {
+		t.Errorf("Expected the import of reports to be linked to the registered analytics module")
+	}
+
+	// Registering the referenced module again is still a duplicate
+	err := graph.AddModule(DefaultModule("database", "1.0.0"))
+	if err == nil || !strings.Contains(err.Error(), "list it in Imports") {
+		t.Errorf("Expected a duplicate registration error suggesting an import, got %v", err)
+	}
+}
+
+func TestPluginManager_ImportReferenceReExports(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+
+	moduleA := DefaultModule("module-a", "1.0.0").
+		WithProviders(NewValueProvider("exportedService", "exported")).
+		WithExports("exportedService")
+	if err := pm.RegisterPlugin(newModuleTestPlugin(moduleA)); err != nil {
+		t.Fatalf("Expected module-a to register, got %v", err)
+	}
+
+	// As in examples/isolated-modules, module-b imports a reference to module-a
+	moduleB := DefaultModule("module-b", "1.0.0").
+		WithImports(DefaultModule("module-a", "1.0.0")).
+		WithExports("exportedService")
+	if err := pm.RegisterPlugin(newModuleTestPlugin(moduleB)); err != nil {
+		t.Fatalf("Expected module-b to register with an import reference, got %v", err)
+	}
+	if err := pm.Validate(context.Background()); err != nil {
+		t.Errorf("Expected the wiring to validate, got %v", err)
+	}
+}
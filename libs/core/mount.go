@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mountPathParam is the catch-all parameter of the routes serving a mounted app
+const mountPathParam = "mountPath"
+
+// mountedApp is a DoffApp served under a prefix of another
+type mountedApp struct {
+	prefix string
+	app    *DoffApp
+}
+
+// MountOption configures Mount
+type MountOption func(*mountOptions)
+
+type mountOptions struct {
+	shareContainer bool
+}
+
+// ShareContainer makes the mounted app's root container fall back to the parent
+// app's, so it can resolve the parent's services; the parent does not see the
+// mounted app's. By default the two containers are isolated
+func ShareContainer() MountOption {
+	return func(o *mountOptions) {
+		o.shareContainer = true
+	}
+}
+
+// MountModule serves a module's routes under prefix instead of its declared Prefix
+// (see ModuleGraph.Mount), e.g. to run the same module under "/v1/billing" in one
+// deployment and "/billing" in another
+// Call it after registering the module's plugin and before Init or Listen: routes
+// already registered stay where they are, as gin cannot move them
+func (pm *PluginManager) MountModule(name, prefix string) error {
+	for _, route := range pm.registeredRoutes() {
+		if route.Module == name {
+			return fmt.Errorf("cannot mount module '%s': it has already registered %s", name, describeRoute(route))
+		}
+	}
+	previous, mounted := pm.modules.mounts[name]
+	if err := pm.modules.Mount(name, prefix); err != nil {
+		return err
+	}
+	if err := pm.checkPrefixCollisions(name); err != nil {
+		if mounted {
+			pm.modules.mounts[name] = previous
+		} else {
+			delete(pm.modules.mounts, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// MountModule serves a module's routes under prefix (see PluginManager.MountModule)
+func (d *DoffApp) MountModule(name, prefix string) error {
+	return d.pluginManager.MountModule(name, prefix)
+}
+
+// Mount serves another app under prefix, e.g. app.Mount("/admin", adminApp), for a
+// modular monolith built from separate apps
+// Requests under prefix run the parent's global middleware and hooks (except
+// authentication, left to the mounted app), then the mounted app handles them with
+// its own engine, hooks, decorators and plugins, seeing the path without prefix
+// The mounted app is initialized and shut down with the parent, its routes are
+// listed by the parent's GetRoutes and OpenAPI document, and the parent's containers
+// are isolated from it unless ShareContainer is given
+// Mount fails if the parent already serves a route under prefix
+func (d *DoffApp) Mount(prefix string, sub *DoffApp, opts ...MountOption) error {
+	if sub == nil || sub == d {
+		return fmt.Errorf("cannot mount app at '%s': the app must be another, non-nil app", prefix)
+	}
+	if strings.Contains(prefix, "..") {
+		return fmt.Errorf("mount prefix '%s' contains invalid path traversal", prefix)
+	}
+	prefix = joinPrefixes(prefix)
+	if prefix == "" {
+		return fmt.Errorf("cannot mount app '%s' at the root: use a prefix such as '/%s'", sub.name, sub.name)
+	}
+
+	for _, route := range d.server.Routes() {
+		if route.Path == prefix || strings.HasPrefix(route.Path, prefix+"/") {
+			return fmt.Errorf("%w: cannot mount app '%s' at '%s' over %s %s",
+				ErrRouteConflict, sub.name, prefix, route.Method, route.Path)
+		}
+	}
+
+	var options mountOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.shareContainer {
+		container, ok := sub.container.(*diContainer)
+		if !ok {
+			return fmt.Errorf("cannot share the container with app '%s': %T has no parent", sub.name, sub.container)
+		}
+		container.mu.Lock()
+		container.parent = d.container
+		container.mu.Unlock()
+	}
+
+	handler := mountHandler(prefix, sub)
+	for _, pattern := range []string{prefix, prefix + "/*" + mountPathParam} {
+		d.server.Any(pattern, handler)
+		d.pluginManager.markPublicRoute(MethodAny, pattern)
+	}
+	d.mounts = append(d.mounts, mountedApp{prefix: prefix, app: sub})
+	return nil
+}
+
+// mountHandler hands requests to the mounted app's engine with prefix stripped
+func mountHandler(prefix string, sub *DoffApp) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := *c.Request
+		url := *c.Request.URL
+		url.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(url.Path, prefix), "/")
+		url.RawPath = ""
+		request.URL = &url
+
+		sub.server.ServeHTTP(c.Writer, &request)
+		c.Abort()
+	}
+}
+
+// mountedRoutes returns the routes of the mounted apps, their paths prefixed
+func (d *DoffApp) mountedRoutes() []RouteInfo {
+	var routes []RouteInfo
+	for _, mount := range d.mounts {
+		for _, route := range mount.app.GetRoutes() {
+			route.Path = joinRoutePath(mount.prefix, route.Path)
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// mountFor returns the app mounted at the longest prefix of path, and path within it
+func (d *DoffApp) mountFor(path string) (*DoffApp, string, bool) {
+	var found *mountedApp
+	for i, mount := range d.mounts {
+		if path != mount.prefix && !strings.HasPrefix(path, mount.prefix+"/") {
+			continue
+		}
+		if found == nil || len(mount.prefix) > len(found.prefix) {
+			found = &d.mounts[i]
+		}
+	}
+	if found == nil {
+		return nil, "", false
+	}
+	return found.app, "/" + strings.TrimPrefix(strings.TrimPrefix(path, found.prefix), "/"), true
+}
+
+// prepareMounts initializes the mounted apps
+func (d *DoffApp) prepareMounts() error {
+	for _, mount := range d.mounts {
+		if err := mount.app.prepare(); err != nil {
+			return fmt.Errorf("app '%s' mounted at '%s': %w", mount.app.name, mount.prefix, err)
+		}
+	}
+	return nil
+}
+
+// shutdownMounts shuts the mounted apps down, last mounted first
+func (d *DoffApp) shutdownMounts(ctx context.Context) error {
+	var errs []error
+	for i := len(d.mounts) - 1; i >= 0; i-- {
+		mount := d.mounts[i]
+		if err := mount.app.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("app '%s' mounted at '%s': %w", mount.app.name, mount.prefix, err))
+		}
+	}
+	return errors.Join(errs...)
+}
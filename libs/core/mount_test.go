@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// routedModulePlugin registers one route through its module's enhanced router
+type routedModulePlugin struct {
+	*moduleTestPlugin
+}
+
+func newRoutedModulePlugin(name, prefix string) *routedModulePlugin {
+	return &routedModulePlugin{moduleTestPlugin: newModuleTestPlugin(NewModule(name, "1.0.0").WithPrefix(prefix))}
+}
+
+func (p *routedModulePlugin) Init(app *DoffApp) error {
+	router := app.GetPluginManager().GetEnhancedRouterForModule(p.Name())
+	router.GET(RouteConfig{Path: "status"}, func(c *gin.Context, controller *routeTestController) {
+		c.String(http.StatusOK, p.Name())
+	})
+	return nil
+}
+
+func serveMount(app *DoffApp, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestDoffApp_MountModule(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	// Both modules declare the same prefix; mounting them apart avoids the collision
+	require.NoError(t, app.RegisterPlugin(newRoutedModulePlugin("billing", "/api")))
+	require.NoError(t, app.RegisterPlugin(newRoutedModulePlugin("shipping", "/api")))
+	require.NoError(t, app.MountModule("billing", "/v1/billing"))
+	require.NoError(t, app.MountModule("shipping", "shipping/"))
+	assert.Equal(t, "/v1/billing", app.GetPluginManager().GetModulePrefix("billing"))
+	assert.Equal(t, "/shipping", app.GetPluginManager().GetModulePrefix("shipping"))
+
+	require.NoError(t, app.Init())
+
+	recorder := serveMount(app, http.MethodGet, "/v1/billing/status")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "billing", recorder.Body.String())
+	recorder = serveMount(app, http.MethodGet, "/shipping/status")
+	assert.Equal(t, "shipping", recorder.Body.String())
+	assert.Equal(t, http.StatusNotFound, serveMount(app, http.MethodGet, "/api/status").Code)
+
+	// Routes are bound once registered
+	assert.ErrorContains(t, app.MountModule("billing", "/billing"), "already registered GET /v1/billing/status")
+	assert.ErrorContains(t, app.MountModule("missing", "/missing"), "module 'missing' not found")
+}
+
+func TestModuleGraph_MountAppliesToImportedModules(t *testing.T) {
+	graph := NewModuleGraph()
+	users := NewModule("users", "1.0.0").WithPrefix("/users")
+	api := NewModule("api", "1.0.0").WithPrefix("/api").WithImports(users)
+	require.NoError(t, graph.AddModule(users))
+	require.NoError(t, graph.AddModule(api))
+
+	require.NoError(t, graph.Mount("api", "/internal/v2"))
+	assert.Equal(t, "/internal/v2", graph.GetFullPrefix("api"))
+	assert.Equal(t, "/internal/v2/users", graph.GetFullPrefix("users"))
+	assert.Equal(t, "/internal/v2/users", graph.Clone().GetFullPrefix("users"))
+
+	assert.Error(t, graph.Mount("api", "/../etc"))
+}
+
+func TestDoffApp_Mount(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterSingleton("greeting", func(DIContainer) (interface{}, error) { return "hello", nil })
+	app.GetRouter().GET(RouteConfig{Path: "/status"}, func(c *gin.Context, container DIContainer) {
+		c.String(http.StatusOK, "main")
+	})
+
+	admin := newLifecycleTestApp(t)
+	admin.GetRouter().GET(RouteConfig{Path: "/status"}, func(c *gin.Context, container DIContainer) {
+		greeting, err := container.Resolve("greeting")
+		require.NoError(t, err)
+		c.String(http.StatusOK, "admin "+greeting.(string)+" "+c.Request.URL.Path)
+	})
+	var shutdown bool
+	admin.GetPluginManager().GetLifecycleManager().AddAppHook(&ApplicationHookFunc{OnCloseFunc: func() error {
+		shutdown = true
+		return nil
+	}})
+
+	reports := newLifecycleTestApp(t)
+	reports.GetRouter().GET(RouteConfig{Path: "/status"}, func(c *gin.Context, container DIContainer) {
+		_, err := container.Resolve("greeting")
+		assert.Error(t, err, "containers are isolated by default")
+		c.String(http.StatusOK, "reports")
+	})
+
+	require.NoError(t, app.Mount("/admin", admin, ShareContainer()))
+	require.NoError(t, app.Mount("/reports", reports))
+	require.NoError(t, app.Init())
+
+	assert.Equal(t, "main", serveMount(app, http.MethodGet, "/status").Body.String())
+	assert.Equal(t, "admin hello /status", serveMount(app, http.MethodGet, "/admin/status").Body.String())
+	assert.Equal(t, "reports", serveMount(app, http.MethodGet, "/reports/status").Body.String())
+	assert.Equal(t, http.StatusNotFound, serveMount(app, http.MethodGet, "/admin/missing").Code)
+
+	paths := make([]string, 0)
+	for _, route := range app.GetRoutes() {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	assert.Equal(t, []string{"GET /status", "GET /admin/status", "GET /reports/status"}, paths)
+	assert.Equal(t, []string{http.MethodGet}, app.AllowedMethods("/admin/status"))
+
+	// Prefixes already served are refused
+	assert.ErrorIs(t, app.Mount("/admin", newLifecycleTestApp(t)), ErrRouteConflict)
+	assert.ErrorIs(t, app.Mount("/status", newLifecycleTestApp(t)), ErrRouteConflict)
+	assert.Error(t, app.Mount("/", newLifecycleTestApp(t)))
+	assert.Error(t, app.Mount("/self", app))
+
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.True(t, shutdown, "mounted apps shut down with the parent")
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// MultipartMaxSizeOption caps the multipart/form-data bodies of a route at this many
+// bytes (int or int64), answering 413 when exceeded; defaults to DefaultMultipartMaxSize
+const MultipartMaxSizeOption = "multipartMaxSize"
+
+// DefaultMultipartMaxSize is the total size allowed for a multipart form unless the
+// route sets MultipartMaxSizeOption
+const DefaultMultipartMaxSize int64 = 32 << 20
+
+// multipartMaxSizeKey stores the route's MultipartMaxSizeOption in the gin context
+const multipartMaxSizeKey = "doffy.multipartMaxSize"
+
+// MultipartForm marks a struct an enhanced route handler takes as a parameter to have
+// the multipart/form-data body bound into it rather than injected: fields by their
+// `form` tag, files as *multipart.FileHeader or []*multipart.FileHeader, validated
+// with `binding` tags, e.g.
+//
+//	type AvatarUpload struct {
+//		core.MultipartForm
+//		Title  string                `form:"title" binding:"required"`
+//		Avatar *multipart.FileHeader `form:"avatar" binding:"required"`
+//	}
+//
+//	func(c *gin.Context, form *AvatarUpload, users *UserService)
+//
+// Missing required parts or a body that is not a multipart form answer 400, and a
+// body over the route's MultipartMaxSizeOption answers 413
+type MultipartForm struct{}
+
+var multipartFormType = reflect.TypeOf(MultipartForm{})
+
+// isMultipartForm reports whether a handler parameter is a struct, or pointer to a
+// struct, embedding MultipartForm
+func isMultipartForm(paramType reflect.Type) bool {
+	if paramType.Kind() == reflect.Ptr {
+		paramType = paramType.Elem()
+	}
+	if paramType.Kind() != reflect.Struct {
+		return false
+	}
+	field, ok := paramType.FieldByName(multipartFormType.Name())
+	return ok && field.Anonymous && field.Type == multipartFormType
+}
+
+// routeMultipartMaxSize returns the route's MultipartMaxSizeOption, if set
+func routeMultipartMaxSize(config RouteConfig) (int64, bool) {
+	switch limit := config.Options[MultipartMaxSizeOption].(type) {
+	case int64:
+		return limit, limit > 0
+	case int:
+		return int64(limit), limit > 0
+	}
+	return 0, false
+}
+
+// multipartMaxSizeHandler records the route's multipart form limit for bindMultipartForm
+func multipartMaxSizeHandler(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(multipartMaxSizeKey, limit)
+	}
+}
+
+// bindMultipartForm binds the request's multipart form into a new value of paramType
+// Errors are API errors, or oversized body errors ToAPIError maps to 413
+func bindMultipartForm(c *gin.Context, paramType reflect.Type) (reflect.Value, error) {
+	limit := DefaultMultipartMaxSize
+	if value, exists := c.Get(multipartMaxSizeKey); exists {
+		limit = value.(int64)
+	}
+	if c.Request.ContentLength > limit {
+		return reflect.Value{}, fmt.Errorf("%w: multipart form limit is %d bytes", ErrBodyTooLarge, limit)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || mediaType != binding.MIMEMultipartPOSTForm {
+		return reflect.Value{}, ErrBadRequest.WithMessage("Expected a multipart/form-data body")
+	}
+	if c.Request.Body != nil {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	}
+
+	structType := paramType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	form := reflect.New(structType)
+	if err := c.ShouldBindWith(form.Interface(), binding.FormMultipart); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return reflect.Value{}, fmt.Errorf("%w: multipart form limit is %d bytes", ErrBodyTooLarge, limit)
+		}
+		if fields := formFieldErrors(structType, err); fields != nil {
+			return reflect.Value{}, ErrBadRequest.WithMessage("Validation failed").WithDetails(fields).WithCause(err)
+		}
+		return reflect.Value{}, ErrBadRequest.WithMessage("Invalid multipart form").WithCause(err)
+	}
+
+	if paramType.Kind() == reflect.Ptr {
+		return form, nil
+	}
+	return form.Elem(), nil
+}
+
+// formFieldErrors converts the validation errors of a form into field errors named
+// by their `form` tags; it returns nil for other errors
+func formFieldErrors(formType reflect.Type, err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   taggedFieldPath(formType, fe.StructNamespace(), "form"),
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe),
+		})
+	}
+	return fields
+}
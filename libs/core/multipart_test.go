@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type avatarUpload struct {
+	MultipartForm
+	Title  string                  `form:"title" binding:"required"`
+	Avatar *multipart.FileHeader   `form:"avatar" binding:"required"`
+	Extras []*multipart.FileHeader `form:"extras"`
+}
+
+func newMultipartTestApp(t *testing.T) *DoffApp {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	handler := func(c *gin.Context, form *avatarUpload, controller *routeTestController) {
+		file, err := form.Avatar.Open()
+		require.NoError(t, err)
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+
+		c.JSON(http.StatusOK, gin.H{
+			"title":    form.Title,
+			"filename": form.Avatar.Filename,
+			"content":  string(content),
+			"extras":   len(form.Extras),
+			"injected": controller != nil,
+		})
+	}
+	router.POST(RouteConfig{Path: "/avatars"}, handler)
+	router.POST(RouteConfig{
+		Path:    "/small-avatars",
+		Options: map[string]interface{}{MultipartMaxSizeOption: 512},
+	}, handler)
+	return app
+}
+
+// postMultipart posts a form with the given fields and files (field name to content)
+func postMultipart(app *DoffApp, path string, fields, files map[string]string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	for name, content := range files {
+		part, _ := writer.CreateFormFile(name, name+".txt")
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, path, &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestEnhancedRouter_BindsMultipartForm(t *testing.T) {
+	app := newMultipartTestApp(t)
+
+	recorder := postMultipart(app, "/avatars", map[string]string{"title": "me"}, map[string]string{"avatar": "png bytes", "extras": "more"})
+
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "me", body["title"])
+	assert.Equal(t, "avatar.txt", body["filename"])
+	assert.Equal(t, "png bytes", body["content"])
+	assert.Equal(t, float64(1), body["extras"])
+	assert.Equal(t, true, body["injected"])
+}
+
+func TestEnhancedRouter_MultipartFormMissingRequiredFile(t *testing.T) {
+	app := newMultipartTestApp(t)
+
+	recorder := postMultipart(app, "/avatars", map[string]string{"title": "me"}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	var body struct {
+		Error struct {
+			Message string       `json:"message"`
+			Details []FieldError `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "Validation failed", body.Error.Message)
+	require.Len(t, body.Error.Details, 1)
+	assert.Equal(t, "avatar", body.Error.Details[0].Field)
+	assert.Equal(t, "required", body.Error.Details[0].Rule)
+}
+
+func TestEnhancedRouter_MultipartFormRejectsOtherBodies(t *testing.T) {
+	app := newMultipartTestApp(t)
+
+	request := httptest.NewRequest(http.MethodPost, "/avatars", strings.NewReader(`{"title":"me"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "multipart/form-data")
+}
+
+func TestEnhancedRouter_MultipartFormTooLarge(t *testing.T) {
+	app := newMultipartTestApp(t)
+	large := strings.Repeat("x", 1024)
+
+	recorder := postMultipart(app, "/small-avatars", map[string]string{"title": "me"}, map[string]string{"avatar": large})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+
+	// Bodies without a Content-Length are capped while parsing
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("title", "me")
+	part, _ := writer.CreateFormFile("avatar", "avatar.txt")
+	part.Write([]byte(large))
+	writer.Close()
+	request := httptest.NewRequest(http.MethodPost, "/small-avatars", io.MultiReader(&body))
+	request.ContentLength = -1
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	chunked := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(chunked, request)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, chunked.Code)
+
+	// The default limit still allows the same form
+	recorder = postMultipart(app, "/avatars", map[string]string{"title": "me"}, map[string]string{"avatar": large})
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the service name a type is registered and resolved under,
+// for handler parameters, constructor parameters, untagged ClassProvider fields,
+// RegisterByType and the locator's GetByType
+type NamingStrategy func(t reflect.Type) string
+
+// Built-in naming strategies
+var (
+	// FullTypeName names a service by its full type, e.g. "*users.UserController"
+	FullTypeName NamingStrategy = func(t reflect.Type) string { return t.String() }
+	// ShortTypeName names a service by its bare type name, e.g. "UserController"
+	ShortTypeName NamingStrategy = toServiceName
+	// SnakeCaseTypeName names a service by its bare type name in snake_case, e.g. "user_controller"
+	SnakeCaseTypeName NamingStrategy = func(t reflect.Type) string { return toSnakeCase(toServiceName(t)) }
+)
+
+// NamingStrategyContainer is implemented by containers with a configurable naming
+// strategy; the default container is, and containers created from it (module,
+// request and scoped containers) use their parent's unless they set their own
+// Without a strategy, a type is looked up by FullTypeName, then by ShortTypeName
+type NamingStrategyContainer interface {
+	SetNamingStrategy(strategy NamingStrategy)
+	NamingStrategy() NamingStrategy
+}
+
+// namingStrategySource is implemented by containers and wrappers that can report
+// the naming strategy in effect
+type namingStrategySource interface {
+	NamingStrategy() NamingStrategy
+}
+
+// SetNamingStrategy sets the strategy types are named by; nil restores the default lookup
+func (c *diContainer) SetNamingStrategy(strategy NamingStrategy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.naming = strategy
+}
+
+// NamingStrategy returns the container's strategy, or its parent's; nil means the default lookup
+func (c *diContainer) NamingStrategy() NamingStrategy {
+	c.mu.RLock()
+	naming := c.naming
+	c.mu.RUnlock()
+
+	if naming == nil && c.parent != nil {
+		return namingStrategyOf(c.parent)
+	}
+	return naming
+}
+
+// NamingStrategy returns the strategy of the container the factory resolves from
+func (b *boundContainer) NamingStrategy() NamingStrategy {
+	return namingStrategyOf(b.DIContainer)
+}
+
+// NamingStrategy returns the strategy of the container registrations are recorded on
+func (r *registrationRecorder) NamingStrategy() NamingStrategy {
+	return namingStrategyOf(r.DIContainer)
+}
+
+// namingStrategyOf returns the naming strategy in effect for container, or nil
+func namingStrategyOf(container DIContainer) NamingStrategy {
+	if source, ok := container.(namingStrategySource); ok {
+		return source.NamingStrategy()
+	}
+	return nil
+}
+
+// serviceNamesForType returns the names a type's service is looked up under, in
+// order: the strategy's name when the container has one, otherwise the full type
+// name, then the short name
+func serviceNamesForType(container DIContainer, t reflect.Type) []string {
+	if strategy := namingStrategyOf(container); strategy != nil {
+		return []string{strategy(t)}
+	}
+	if name := toServiceName(t); name != "" && name != t.String() {
+		return []string{t.String(), name}
+	}
+	return []string{t.String()}
+}
+
+// describeServiceNames quotes names for error messages, e.g. "'*core.A' or 'A'"
+func describeServiceNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// toSnakeCase converts a Go identifier to snake_case, keeping acronyms together,
+// e.g. "HTTPServer" becomes "http_server"
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,297 @@
+package core
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIPath is where AppOptions.OpenAPI serves the OpenAPI document
+const OpenAPIPath = "/openapi.json"
+
+// OpenAPIVersion is the OpenAPI specification version of the generated document
+const OpenAPIVersion = "3.0.3"
+
+// openAPISecurityScheme names the bearer scheme required by authenticated routes
+const openAPISecurityScheme = "bearerAuth"
+
+// OpenAPIDocument is a minimal OpenAPI 3 document describing the app's routes
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"` // Path, then lowercase method
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+}
+
+// OpenAPIInfo is the document's title and version
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIOperation describes one method of a path
+type OpenAPIOperation struct {
+	Tags        []string                   `json:"tags,omitempty"` // The module that registered the route
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	// Security lists the bearer scheme for routes requiring authentication: every route
+	// of an app with an Authenticator except those registered with IsAuth: false
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+// OpenAPIParameter is a path parameter
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody is the JSON body a route's SchemaValidator binds
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIMediaType holds the schema of a body in one media type
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIResponse describes a response status
+type OpenAPIResponse struct {
+	Description string `json:"description"`
+}
+
+// OpenAPISchema is the subset of JSON schema derived from Go types
+type OpenAPISchema struct {
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPIComponents holds the security schemes operations refer to
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPISecurityScheme is an HTTP authentication scheme
+type OpenAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// openAPIAnyMethods are the methods a MethodAny route is documented under
+var openAPIAnyMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions, http.MethodTrace,
+}
+
+// registerOpenAPIRoute registers the public OpenAPIPath endpoint
+func (d *DoffApp) registerOpenAPIRoute() {
+	public := false
+	d.GetRouter().GET(RouteConfig{Path: OpenAPIPath, IsAuth: &public}, func(c *gin.Context, container DIContainer) {
+		c.JSON(http.StatusOK, d.OpenAPIDocument())
+	})
+}
+
+// OpenAPIDocument describes the routes registered through the app's routers (see
+// GetRoutes), with their path parameters, the request body schema of routes with a
+// SchemaValidator, and the bearer authentication of routes the app's Authenticator
+// guards; routes added to the gin engine directly are not listed
+func (d *DoffApp) OpenAPIDocument() OpenAPIDocument {
+	document := OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: d.name, Version: "1.0.0"},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	authenticated := d.hasAuthenticator()
+	if authenticated {
+		document.Components = &OpenAPIComponents{SecuritySchemes: map[string]OpenAPISecurityScheme{
+			openAPISecurityScheme: {Type: "http", Scheme: "bearer"},
+		}}
+	}
+
+	for _, route := range d.GetRoutes() {
+		path, parameters := openAPIPath(route.Path)
+		operations, exists := document.Paths[path]
+		if !exists {
+			operations = make(map[string]*OpenAPIOperation)
+			document.Paths[path] = operations
+		}
+
+		methods := []string{route.Method}
+		if route.Method == MethodAny {
+			methods = openAPIAnyMethods
+		}
+		for _, method := range methods {
+			operations[strings.ToLower(method)] = openAPIOperation(route, parameters, authenticated)
+		}
+	}
+	return document
+}
+
+// hasAuthenticator reports whether the app authenticates its non-public routes
+func (d *DoffApp) hasAuthenticator() bool {
+	if d.container == nil {
+		return false
+	}
+	authenticator, found, err := d.container.ResolveOptional("authenticator")
+	if !found || err != nil {
+		return false
+	}
+	_, ok := authenticator.(Authenticator)
+	return ok
+}
+
+// openAPIOperation describes a registered route
+func openAPIOperation(route RouteInfo, parameters []OpenAPIParameter, authenticated bool) *OpenAPIOperation {
+	operation := &OpenAPIOperation{
+		Parameters: parameters,
+		Responses:  map[string]OpenAPIResponse{"default": {Description: "Response"}},
+	}
+	if route.Module != "" {
+		operation.Tags = []string{route.Module}
+	}
+
+	if schema, ok := route.Options["schema"]; ok && schema != nil {
+		operation.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]OpenAPIMediaType{
+				"application/json": {Schema: openAPISchema(reflect.TypeOf(schema), make(map[reflect.Type]bool))},
+			},
+		}
+		operation.Responses["400"] = OpenAPIResponse{Description: "Invalid request body"}
+	}
+
+	if isAuth, ok := route.Options["isAuth"].(bool); authenticated && (!ok || isAuth) {
+		operation.Security = []map[string][]string{{openAPISecurityScheme: {}}}
+		operation.Responses["401"] = OpenAPIResponse{Description: "Unauthorized"}
+	}
+	return operation
+}
+
+// openAPIPath converts a gin route pattern to an OpenAPI path, e.g. "/users/:id" to
+// "/users/{id}", returning its path parameters
+func openAPIPath(pattern string) (string, []OpenAPIParameter) {
+	var parameters []OpenAPIParameter
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// openAPISchema derives a schema from a Go type the way encoding/json encodes it:
+// exported fields by their json tag, embedded structs flattened, and fields with a
+// "required" binding or validate rule listed as required
+// seen guards against recursive types, which are described as plain objects
+func openAPISchema(t reflect.Type, seen map[reflect.Type]bool) *OpenAPISchema {
+	if t == nil {
+		return &OpenAPISchema{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64 {
+			return &OpenAPISchema{Type: "integer", Format: "int64"}
+		}
+		return &OpenAPISchema{Type: "integer"}
+	case t.Kind() == reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case t.Kind() == reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case t.Kind() == reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8:
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: openAPISchema(t.Elem(), seen)}
+	case t.Kind() == reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: openAPISchema(t.Elem(), seen)}
+	case t.Kind() == reflect.Struct:
+		if seen[t] {
+			return &OpenAPISchema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+		addOpenAPIFields(schema, t, seen)
+		sort.Strings(schema.Required)
+		return schema
+	default:
+		// Interfaces and other kinds accept any value
+		return &OpenAPISchema{}
+	}
+}
+
+// addOpenAPIFields adds the JSON fields of a struct type to schema
+func addOpenAPIFields(schema *OpenAPISchema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			addOpenAPIFields(schema, fieldType, seen)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = openAPISchema(field.Type, seen)
+		if hasRequiredRule(field.Tag.Get("binding")) || hasRequiredRule(field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// hasRequiredRule reports whether a validator tag such as "required,email" has the required rule
+func hasRequiredRule(tag string) bool {
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
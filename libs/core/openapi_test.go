@@ -0,0 +1,106 @@
+package core
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIAddress is embedded in openAPIOrderSchema
+type openAPIAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+// openAPIOrderSchema covers the field kinds the OpenAPI schema derives
+type openAPIOrderSchema struct {
+	openAPIAddress
+	ID       int64               `json:"id"`
+	Items    []string            `json:"items" binding:"required,min=1"`
+	Total    float64             `json:"total,omitempty"`
+	Paid     *bool               `json:"paid"`
+	PlacedAt time.Time           `json:"placedAt"`
+	Labels   map[string]string   `json:"labels"`
+	Parent   *openAPIOrderSchema `json:"parent"`
+	Secret   string              `json:"-"`
+	Note     string
+	internal string
+}
+
+func TestDoffApp_OpenAPIDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:          "openapi-test",
+		Mode:          gin.TestMode,
+		OpenAPI:       true,
+		Authenticator: &fakeAuthenticator{validToken: "secret"},
+	}).(*DoffApp)
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("orders", "1.0.0").WithPrefix("/orders"))))
+	noop := func(c *gin.Context, container DIContainer) {}
+	public := false
+
+	router := app.GetRouter()
+	router.GET(RouteConfig{Path: "/status", IsAuth: &public}, noop)
+	router.Any(RouteConfig{Path: "/files/*filepath"}, noop)
+	moduleRouter := app.GetPluginManager().GetEnhancedRouterForModule("orders")
+	moduleRouter.POST(RouteConfig{Path: ":id/items", SchemaValidator: &openAPIOrderSchema{}}, noop)
+	moduleRouter.GET(RouteConfig{Path: ":id/items"}, noop)
+
+	// Served publicly
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var served map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &served))
+	assert.Equal(t, OpenAPIVersion, served["openapi"])
+
+	document := app.OpenAPIDocument()
+	assert.Equal(t, "openapi-test", document.Info.Title)
+	assert.ElementsMatch(t, []string{OpenAPIPath, "/status", "/files/{filepath}", "/orders/{id}/items"}, slices.Collect(maps.Keys(document.Paths)))
+	assert.Len(t, document.Paths["/files/{filepath}"], len(openAPIAnyMethods))
+
+	status := document.Paths["/status"]["get"]
+	assert.Empty(t, status.Security, "IsAuth: false routes are public")
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, document.Paths["/files/{filepath}"]["get"].Security)
+	assert.Contains(t, document.Components.SecuritySchemes, "bearerAuth")
+
+	create := document.Paths["/orders/{id}/items"]["post"]
+	require.NotNil(t, create)
+	assert.Equal(t, []string{"orders"}, create.Tags)
+	assert.Equal(t, []OpenAPIParameter{{Name: "id", In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"}}}, create.Parameters)
+	assert.NotNil(t, create.Security)
+	assert.Nil(t, document.Paths["/orders/{id}/items"]["get"].RequestBody)
+
+	require.NotNil(t, create.RequestBody)
+	schema := create.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"city", "items"}, schema.Required)
+	assert.ElementsMatch(t, []string{"city", "id", "items", "total", "paid", "placedAt", "labels", "parent", "Note"}, slices.Collect(maps.Keys(schema.Properties)))
+	assert.Equal(t, &OpenAPISchema{Type: "integer", Format: "int64"}, schema.Properties["id"])
+	assert.Equal(t, &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "string"}}, schema.Properties["items"])
+	assert.Equal(t, &OpenAPISchema{Type: "boolean"}, schema.Properties["paid"])
+	assert.Equal(t, &OpenAPISchema{Type: "string", Format: "date-time"}, schema.Properties["placedAt"])
+	assert.Equal(t, &OpenAPISchema{Type: "object", AdditionalProperties: &OpenAPISchema{Type: "string"}}, schema.Properties["labels"])
+	assert.Equal(t, &OpenAPISchema{Type: "object"}, schema.Properties["parent"], "recursive types stop at an object")
+}
+
+func TestDoffApp_OpenAPIDisabledByDefault(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetRouter().GET(RouteConfig{Path: "/status"}, func(c *gin.Context, container DIContainer) {})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// Without an Authenticator no route requires authentication
+	document := app.OpenAPIDocument()
+	assert.Nil(t, document.Components)
+	assert.Empty(t, document.Paths["/status"]["get"].Security)
+}
@@ -0,0 +1,55 @@
+package core
+
+import "slices"
+
+// SkippedPlugin is an optional plugin whose registration failed, leaving the app
+// without the feature it provides
+type SkippedPlugin struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// RegisterOptionalPlugin registers a plugin like RegisterPlugin, but a failed
+// registration is recorded in SkippedPlugins instead of having to abort startup
+// The failed plugin leaves no module, services or hooks behind (see RegisterPlugin)
+func (pm *PluginManager) RegisterOptionalPlugin(plugin Plugin) error {
+	var name string
+	if plugin != nil {
+		name = plugin.Name()
+	}
+
+	pm.skipped = slices.DeleteFunc(pm.skipped, func(skipped SkippedPlugin) bool { return skipped.Name == name })
+	err := pm.RegisterPlugin(plugin)
+	if err != nil {
+		pm.skipped = append(pm.skipped, SkippedPlugin{Name: name, Error: err.Error()})
+	}
+	return err
+}
+
+// SkippedPlugins returns the optional plugins whose registration failed, in the
+// order they were registered
+func (pm *PluginManager) SkippedPlugins() []SkippedPlugin {
+	return slices.Clone(pm.skipped)
+}
+
+// RegisterOptionalPlugin registers a plugin the app can run without: when its
+// registration fails (e.g. an integration whose configuration is absent) the error
+// is logged, the plugin skipped and reported by SkippedPlugins and /healthz
+// It reports whether the plugin was registered
+func (d *DoffApp) RegisterOptionalPlugin(plugin Plugin) bool {
+	if err := d.pluginManager.RegisterOptionalPlugin(plugin); err != nil {
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelWarn,
+			Event:    "OptionalPluginSkipped",
+			Messages: "Optional plugin registration failed, continuing without it",
+			Error:    err,
+		})
+		return false
+	}
+	return true
+}
+
+// SkippedPlugins returns the optional plugins whose registration failed
+func (d *DoffApp) SkippedPlugins() []SkippedPlugin {
+	return d.pluginManager.SkippedPlugins()
+}
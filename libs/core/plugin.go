@@ -2,8 +2,15 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -29,7 +36,10 @@ type Plugin interface {
 type RouteInfo struct {
 	Method  string
 	Path    string
+	Module  string
 	Options map[string]interface{}
+
+	params []reflect.Type // Parameters an enhanced route's handler has injected
 }
 
 // RouteAwarePlugin defines the interface for plugins that want to be notified about route registration
@@ -39,6 +49,28 @@ type RouteAwarePlugin interface {
 	OnRoute(config *RouteConfig)
 }
 
+// PrioritizedPlugin is implemented by plugins that must run before or after others,
+// e.g. CORS before authentication before logging
+// Lower priorities go first: their hooks run first and their routes are registered
+// first. Plugins without a priority have DefaultPriority; equal priorities keep
+// their registration order
+type PrioritizedPlugin interface {
+	Plugin
+	Priority() int
+}
+
+// DefaultPriority is the priority of plugins not implementing PrioritizedPlugin
+// and of hooks added with AddHook
+const DefaultPriority = 0
+
+// pluginPriority returns the plugin's priority, DefaultPriority when it declares none
+func pluginPriority(plugin Plugin) int {
+	if prioritized, ok := plugin.(PrioritizedPlugin); ok {
+		return prioritized.Priority()
+	}
+	return DefaultPriority
+}
+
 // PluginConfig holds configuration for a plugin
 type PluginConfig struct {
 	Name   string                 `json:"name"`
@@ -55,26 +87,54 @@ type ModuleProvider interface {
 
 // PluginManager manages plugin registration and lifecycle
 type PluginManager struct {
-	plugins      map[string]Plugin
-	modules      *ModuleGraph
-	app          *DoffApp
-	container    DIContainer
-	lifecycle    *LifecycleManager
-	modulePrefixes map[string]string // Track module prefixes for route registration
+	mu                   sync.RWMutex // Guards the plugin and route registries, which UnregisterPlugin changes while serving
+	plugins              map[string]Plugin
+	ordered              []Plugin // Plugins by priority, then registration order
+	modules              *ModuleGraph
+	app                  *DoffApp
+	container            DIContainer
+	lifecycle            *LifecycleManager
+	routes               []RouteInfo                       // Routes registered through Router/EnhancedRouter
+	publicRoutes         map[string]bool                   // "METHOD:path" of routes registered with IsAuth: false
+	routeModules         map[string]string                 // "METHOD:path" of routes registered by a module, to its name
+	routeConflicts       []error                           // Duplicate route registrations, see RouteConflicts
+	retiredRoutes        []RouteInfo                       // Routes of unregistered plugins, still on the engine but disabled
+	services             map[string][]string               // Plugin name to the services its Register added, in order
+	lazyModules          map[string]*lazyModule            // Modules with Lazy set, by name
+	skipped              []SkippedPlugin                   // Optional plugins whose registration failed, see SkippedPlugins
+	prefixCollisions     PrefixCollisionMode               // Check of overlapping module prefixes, see SetPrefixCollisionMode
+	asyncInitConcurrency int                               // Async providers initialized at once, see SetAsyncInitConcurrency
+	pluginConfigs        map[string]map[string]interface{} // PluginConfig.Config by plugin name, see SetPluginConfigs
+	initialized          atomic.Bool                       // Set once InitializePlugins has completed
 }
 
 // NewPluginManager creates a new plugin manager
 func NewPluginManager(app *DoffApp, container DIContainer) *PluginManager {
 	return &PluginManager{
-		plugins:       make(map[string]Plugin),
-		modules:       NewModuleGraph(),
-		app:           app,
-		container:     container,
-		lifecycle:     NewLifecycleManager(),
-		modulePrefixes: make(map[string]string),
+		plugins:              make(map[string]Plugin),
+		modules:              NewModuleGraph(),
+		app:                  app,
+		container:            container,
+		lifecycle:            NewLifecycleManager(),
+		publicRoutes:         make(map[string]bool),
+		routeModules:         make(map[string]string),
+		services:             make(map[string][]string),
+		lazyModules:          make(map[string]*lazyModule),
+		asyncInitConcurrency: DefaultAsyncInitConcurrency,
 	}
 }
 
+// DefaultAsyncInitConcurrency is how many async providers InitializePlugins
+// initializes at once unless configured otherwise
+const DefaultAsyncInitConcurrency = 10
+
+// SetAsyncInitConcurrency bounds how many async providers are initialized at once;
+// zero or negative initializes them one at a time, in plugin order, which helps
+// debugging initialization order issues
+func (pm *PluginManager) SetAsyncInitConcurrency(limit int) {
+	pm.asyncInitConcurrency = limit
+}
+
 // ApplicationHookProvider defines the interface for plugins that provide application hooks
 type ApplicationHookProvider interface {
 	AppHooks() []ApplicationHook
@@ -87,10 +147,14 @@ func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 	}
 
 	name := plugin.Name()
-	if _, exists := pm.plugins[name]; exists {
+	if _, exists := pm.GetPlugin(name); exists {
 		return ErrPluginAlreadyRegistered
 	}
 
+	if err := pm.configurePlugin(plugin); err != nil {
+		return err
+	}
+
 	// Extract or create module
 	var module *Module
 	if moduleProvider, ok := plugin.(ModuleProvider); ok {
@@ -102,7 +166,9 @@ func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 		module = DefaultModule(plugin.Name(), plugin.Version())
 	}
 
-	// NEW: Validate module exports
+	// NEW: Validate module exports, re-exports checked against the registered modules
+	// its imports refer to
+	pm.modules.linkImports(module)
 	if err := module.ValidateExports(); err != nil {
 		return fmt.Errorf("export validation failed: %w", err)
 	}
@@ -114,29 +180,66 @@ func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 
 	// NEW: Validate module imports
 	if err := pm.modules.ValidateImports(module); err != nil {
+		pm.discardRegistration(module.Name, nil)
 		return fmt.Errorf("import validation failed: %w", err)
 	}
 
-	// Track module prefix for route registration
-	pm.modulePrefixes[module.Name] = module.GetFullPrefix()
+	for _, imported := range module.Imports {
+		if err := pm.modules.ValidateParent(imported.Name); err != nil {
+			pm.discardRegistration(module.Name, nil)
+			return err
+		}
+	}
 
-	// Register plugin services
-	if err := plugin.Register(pm.container); err != nil {
-		return ErrPluginRegistrationFailed
+	if err := pm.checkPrefixCollisions(module.Name); err != nil {
+		pm.discardRegistration(module.Name, nil)
+		return err
 	}
 
-	// Store plugin
+	if err := pm.checkProviderCollisions(name, module); err != nil {
+		pm.discardRegistration(module.Name, nil)
+		return err
+	}
+
+	// Register plugin services, recording them for UnregisterPlugin; services of a
+	// lazy module initialize it on first resolution, and services another plugin
+	// registered are rejected naming it
+	recorder := &registrationRecorder{
+		DIContainer: pm.container,
+		check: func(service string) error {
+			return pm.providerCollision(name, module.Name, service)
+		},
+	}
+	var lazy *lazyModule
+	if module.Lazy {
+		lazy = &lazyModule{name: module.Name, plugin: plugin, pm: pm}
+		recorder.wrap = lazy.wrap
+	}
+	if err := plugin.Register(recorder); err != nil {
+		pm.discardRegistration(module.Name, recorder.registered())
+		return fmt.Errorf("%w: '%s': %w", ErrPluginRegistrationFailed, name, err)
+	}
+	// Store plugin, after every plugin of lower or equal priority
+	priority := pluginPriority(plugin)
+	pm.mu.Lock()
+	pm.services[name] = recorder.registered()
+	if lazy != nil {
+		pm.lazyModules[module.Name] = lazy
+	}
 	pm.plugins[name] = plugin
+	i := sort.Search(len(pm.ordered), func(i int) bool { return pluginPriority(pm.ordered[i]) > priority })
+	pm.ordered = slices.Insert(pm.ordered, i, plugin)
+	pm.mu.Unlock()
 
 	// Add hooks to lifecycle manager
 	for _, hook := range plugin.Hooks() {
-		pm.lifecycle.AddHook(hook)
+		pm.lifecycle.addHook(hook, priority, name)
 	}
 
 	// Add application hooks if provided
 	if appHookProvider, ok := plugin.(ApplicationHookProvider); ok {
 		for _, hook := range appHookProvider.AppHooks() {
-			pm.lifecycle.AddAppHook(hook)
+			pm.lifecycle.addAppHook(hook, priority, name)
 		}
 	}
 
@@ -146,6 +249,228 @@ func (pm *PluginManager) RegisterPlugin(plugin Plugin) error {
 	return nil
 }
 
+// UnregisterPlugin shuts a plugin down and removes it: its lifecycle and application
+// hooks, its module and the services its Register added (disposing cached singletons)
+// It fails without changes when another module imports the plugin's module, or when
+// the container cannot remove services (see ServiceRemover)
+// Routes the plugin's module registered stay on the engine, as gin cannot remove
+// them, but are disabled (see DoffApp.DisableRoute); the plugin can be registered
+// again, e.g. with a new configuration, without registering those routes again
+// It is safe to call while the app serves requests
+func (pm *PluginManager) UnregisterPlugin(name string) error {
+	plugin, exists := pm.GetPlugin(name)
+	if !exists {
+		return fmt.Errorf("%w: '%s'", ErrPluginNotFound, name)
+	}
+
+	dependents, err := pm.modules.GetDependents(name)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		names := make([]string, len(dependents))
+		for i, dependent := range dependents {
+			names[i] = dependent.Name
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%w: '%s' is imported by %s", ErrPluginHasDependents, name, strings.Join(names, ", "))
+	}
+
+	pm.mu.RLock()
+	services := pm.services[name]
+	pm.mu.RUnlock()
+	remover, ok := pm.container.(ServiceRemover)
+	if len(services) > 0 && !ok {
+		return fmt.Errorf("plugin '%s': container %T cannot unregister services", name, pm.container)
+	}
+
+	// Requests stop reaching the plugin's routes before it shuts down
+	pm.mu.Lock()
+	retired := pm.retireModuleRoutes(name)
+	pm.mu.Unlock()
+	if pm.app != nil {
+		for _, route := range retired {
+			pm.app.routeToggles.set(route.Method+":"+route.Path, true)
+		}
+	}
+
+	var errs []error
+	if err := plugin.Shutdown(); err != nil {
+		errs = append(errs, fmt.Errorf("plugin '%s' shutdown failed: %w", name, err))
+	}
+
+	pm.lifecycle.RemovePluginHooks(name)
+	if err := pm.modules.RemoveModule(name); err != nil {
+		errs = append(errs, err)
+	}
+	for _, service := range slices.Backward(services) {
+		if err := remover.Unregister(service); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	pm.mu.Lock()
+	delete(pm.plugins, name)
+	delete(pm.services, name)
+	delete(pm.lazyModules, name)
+	pm.ordered = slices.DeleteFunc(pm.ordered, func(p Plugin) bool { return p.Name() == name })
+	pm.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// retireModuleRoutes moves the routes the module registered out of the registry into
+// retiredRoutes, returning them; pm.mu must be held
+func (pm *PluginManager) retireModuleRoutes(module string) []RouteInfo {
+	var retired []RouteInfo
+	pm.routes = slices.DeleteFunc(pm.routes, func(route RouteInfo) bool {
+		if route.Module != module {
+			return false
+		}
+		retired = append(retired, route)
+		delete(pm.publicRoutes, route.Method+":"+route.Path)
+		delete(pm.routeModules, route.Method+":"+route.Path)
+		return true
+	})
+	pm.retiredRoutes = append(pm.retiredRoutes, retired...)
+	return retired
+}
+
+// discardRegistration undoes a registration that failed part way: it removes the
+// plugin's module and the services its Register added before failing
+// Services stay registered when the container cannot remove them (see ServiceRemover)
+func (pm *PluginManager) discardRegistration(module string, services []string) {
+	pm.modules.RemoveModule(module)
+	if remover, ok := pm.container.(ServiceRemover); ok {
+		for _, service := range slices.Backward(services) {
+			remover.Unregister(service)
+		}
+	}
+}
+
+// registrationRecorder records the names of the services registered through it,
+// passing providers through wrap when set and names through check before registering
+type registrationRecorder struct {
+	DIContainer
+	mu    sync.Mutex
+	names []string
+	wrap  func(Provider) Provider
+	check func(name string) error
+}
+
+func (r *registrationRecorder) claim(name string) error {
+	if r.check == nil {
+		return nil
+	}
+	return r.check(name)
+}
+
+func (r *registrationRecorder) claimProvider(provider Provider) error {
+	if provider == nil {
+		return nil
+	}
+	return r.claim(provider.GetName())
+}
+
+func (r *registrationRecorder) record(name string, err error) error {
+	if err == nil {
+		r.mu.Lock()
+		r.names = append(r.names, name)
+		r.mu.Unlock()
+	}
+	return err
+}
+
+func (r *registrationRecorder) registered() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.names)
+}
+
+func (r *registrationRecorder) wrapped(provider Provider) Provider {
+	if r.wrap == nil {
+		return provider
+	}
+	return r.wrap(provider)
+}
+
+func (r *registrationRecorder) Register(name string, factory Factory, lifetime Lifetime) error {
+	if err := r.claim(name); err != nil {
+		return err
+	}
+	if r.wrap != nil {
+		return r.RegisterProvider(NewFactoryProvider(name, factory, lifetime))
+	}
+	return r.record(name, r.DIContainer.Register(name, factory, lifetime))
+}
+
+func (r *registrationRecorder) RegisterSingleton(name string, factory Factory) error {
+	return r.Register(name, factory, Singleton)
+}
+
+func (r *registrationRecorder) RegisterTransient(name string, factory Factory) error {
+	return r.Register(name, factory, Transient)
+}
+
+func (r *registrationRecorder) RegisterScoped(name string, factory Factory) error {
+	return r.Register(name, factory, Scoped)
+}
+
+func (r *registrationRecorder) RegisterProvider(provider Provider) error {
+	if err := r.claimProvider(provider); err != nil {
+		return err
+	}
+	err := r.DIContainer.RegisterProvider(r.wrapped(provider))
+	if err != nil {
+		return err
+	}
+	return r.record(provider.GetName(), nil)
+}
+
+func (r *registrationRecorder) RegisterProviderSingleton(provider Provider) error {
+	if err := r.claimProvider(provider); err != nil {
+		return err
+	}
+	err := r.DIContainer.RegisterProviderSingleton(r.wrapped(provider))
+	if err != nil {
+		return err
+	}
+	return r.record(provider.GetName(), nil)
+}
+
+func (r *registrationRecorder) RegisterProviderTransient(provider Provider) error {
+	if err := r.claimProvider(provider); err != nil {
+		return err
+	}
+	err := r.DIContainer.RegisterProviderTransient(r.wrapped(provider))
+	if err != nil {
+		return err
+	}
+	return r.record(provider.GetName(), nil)
+}
+
+func (r *registrationRecorder) RegisterProviderScoped(provider Provider) error {
+	if err := r.claimProvider(provider); err != nil {
+		return err
+	}
+	err := r.DIContainer.RegisterProviderScoped(r.wrapped(provider))
+	if err != nil {
+		return err
+	}
+	return r.record(provider.GetName(), nil)
+}
+
+func (r *registrationRecorder) RegisterProviderInGroup(group string, provider Provider) error {
+	if err := r.claimProvider(provider); err != nil {
+		return err
+	}
+	err := r.DIContainer.RegisterProviderInGroup(group, r.wrapped(provider))
+	if err != nil {
+		return err
+	}
+	return r.record(provider.GetName(), nil)
+}
+
 // RegisterPluginByName registers a plugin by name (for dynamic loading)
 func (pm *PluginManager) RegisterPluginByName(name string, config map[string]interface{}) error {
 	// This would be implemented for dynamic plugin loading
@@ -155,12 +480,16 @@ func (pm *PluginManager) RegisterPluginByName(name string, config map[string]int
 
 // GetPlugin returns a plugin by name
 func (pm *PluginManager) GetPlugin(name string) (Plugin, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	plugin, exists := pm.plugins[name]
 	return plugin, exists
 }
 
 // GetPlugins returns all registered plugins
 func (pm *PluginManager) GetPlugins() map[string]Plugin {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	// Return a copy to prevent external modification
 	result := make(map[string]Plugin)
 	for name, plugin := range pm.plugins {
@@ -177,29 +506,216 @@ func (pm *PluginManager) InitializePlugins() error {
 		return fmt.Errorf("failed to resolve module dependencies: %w", err)
 	}
 
-	// Phase 2: Initialize async providers
+	// Phase 2: Check the dependencies providers declare, before building any
+	modules := make([]*Module, 0, len(orderedPlugins))
+	for _, plugin := range orderedPlugins {
+		if moduleProvider, ok := plugin.(ModuleProvider); ok && moduleProvider.Module() != nil {
+			modules = append(modules, moduleProvider.Module())
+		}
+	}
+	if errs := pm.validateDeclaredDependencies(modules); len(errs) > 0 {
+		return fmt.Errorf("provider dependency validation failed: %w", errors.Join(errs...))
+	}
+
+	// Phase 3: Initialize async providers; lazy modules wait for first access
 	ctx := context.Background()
-	if err := pm.initializeAsyncProviders(ctx, orderedPlugins); err != nil {
+	eagerPlugins := slices.DeleteFunc(slices.Clone(orderedPlugins), pm.isLazy)
+	if err := pm.initializeAsyncProviders(ctx, eagerPlugins, ""); err != nil {
 		return fmt.Errorf("async provider initialization failed: %w", err)
 	}
 
-	// Phase 3: Call plugin Init() methods (existing logic)
+	// Phase 4: Instantiate eager singletons in module dependency order
+	if err := pm.initializeEagerSingletons(ctx, eagerPlugins, ""); err != nil {
+		return fmt.Errorf("eager singleton initialization failed: %w", err)
+	}
+
+	// Phase 5: Call plugin Init() methods (existing logic)
 	for _, plugin := range orderedPlugins {
 		if err := plugin.Init(pm.app); err != nil {
 			return fmt.Errorf("plugin '%s' init failed: %w", plugin.Name(), err)
 		}
 	}
 
+	pm.initialized.Store(true)
 	return nil
 }
 
-// initializeAsyncProviders pre-initializes all async providers
-func (pm *PluginManager) initializeAsyncProviders(ctx context.Context, plugins []Plugin) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(plugins))
-	semaphore := make(chan struct{}, 10) // Limit parallel initialization to 10
+// isLazy reports whether the plugin's module initializes on first access (Module.Lazy)
+func (pm *PluginManager) isLazy(plugin Plugin) bool {
+	moduleProvider, ok := plugin.(ModuleProvider)
+	if !ok || moduleProvider.Module() == nil {
+		return false
+	}
+	return pm.lazyModule(moduleProvider.Module().Name) != nil
+}
+
+// IsInitialized reports whether InitializePlugins has completed successfully
+func (pm *PluginManager) IsInitialized() bool {
+	return pm.initialized.Load()
+}
+
+// Validate checks the plugin wiring without side effects on the running app:
+// module graph and import/export validation, initialization ordering, the dependencies
+// providers declare (see DependencyProvider), resolution of async providers and eager singletons in a throwaway copy of the container, and the
+// injected parameters of the enhanced routes registered so far (see validateRouteHandlers)
+// Plugin Init methods are not called and no routes are registered; all errors are returned joined
+func (pm *PluginManager) Validate(ctx context.Context) error {
+	errs := pm.validateRouteHandlers()
+
+	if err := pm.modules.ValidateGraph(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, module := range pm.modules.GetAllModules() {
+		if err := module.ValidateExports(); err != nil {
+			errs = append(errs, fmt.Errorf("export validation failed: %w", err))
+		}
+		if err := pm.modules.ValidateImports(module); err != nil {
+			errs = append(errs, fmt.Errorf("import validation failed: %w", err))
+		}
+	}
+
+	sortedModules, err := pm.modules.TopologicalSort()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to resolve module dependencies: %w", err))
+		return errors.Join(errs...)
+	}
+
+	errs = append(errs, pm.validateDeclaredDependencies(sortedModules)...)
+
+	container := throwawayContainer(pm.container)
+	defer container.Dispose()
+
+	for _, module := range sortedModules {
+		for _, provider := range module.Providers {
+			if !provider.IsAsync() && !isEagerSingleton(provider) {
+				continue
+			}
+
+			name := provider.GetName()
+			if _, err := container.ResolveWithContext(name, ctx); err != nil {
+				errs = append(errs, fmt.Errorf("provider '%s' in module '%s' failed: %w", name, module.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateRouteHandlers reports every enhanced route handler parameter that matches
+// neither a registered service (by the names of serviceNamesForType), a decorator, nor
+// the struct bound from path parameters, which would otherwise fail at request time
+// Services registered on request containers at request time cannot be seen
+func (pm *PluginManager) validateRouteHandlers() []error {
+	var errs []error
+	for _, route := range pm.registeredRoutes() {
+		for i, param := range route.params {
+			if pm.handlerParamResolvable(route.Module, param) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%w: %s: parameter %d (%s) matches no service %s",
+				ErrUnresolvableHandlerParam, describeRoute(route), i+1, param, describeServiceNames(serviceNamesForType(pm.routeContainer(route.Module), param))))
+		}
+	}
+	return errs
+}
+
+// routeContainer returns the container the handlers of a route registered by module resolve from
+func (pm *PluginManager) routeContainer(module string) DIContainer {
+	var container DIContainer = pm.container
+	if module != "" && pm.app != nil {
+		pm.app.moduleMu.Lock()
+		if mc, exists := pm.app.moduleContainers[module]; exists {
+			container = mc
+		}
+		pm.app.moduleMu.Unlock()
+	}
+	return container
+}
+
+// handlerParamResolvable reports whether withController can resolve the parameter of
+// a route registered by module
+func (pm *PluginManager) handlerParamResolvable(module string, param reflect.Type) bool {
+	container := pm.routeContainer(module)
+	if isMultipartForm(param) || isURIParams(container, param) {
+		return true
+	}
+
+	for _, name := range serviceNamesForType(container, param) {
+		if pm.serviceResolvable(module, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceResolvable reports whether name resolves from the container of module's
+// routes: a registered service, a module decorator, or a request or reply decorator
+func (pm *PluginManager) serviceResolvable(module, name string) bool {
+	container := pm.routeContainer(module)
+	if container.Has(name) {
+		return true
+	}
+	if mc, ok := container.(*ModuleContainer); ok {
+		if _, exists := mc.GetDecorator(name); exists {
+			return true
+		}
+	}
+	if pm.app != nil {
+		if _, exists := pm.app.decoratorManager.GetRequestDecorator(name); exists {
+			return true
+		}
+		if _, exists := pm.app.decoratorManager.GetReplyDecorator(name); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDeclaredDependencies reports every dependency a module provider declares
+// (see DependencyProvider) that does not resolve from the module's scope
+func (pm *PluginManager) validateDeclaredDependencies(modules []*Module) []error {
+	var errs []error
+	for _, module := range modules {
+		for _, provider := range module.Providers {
+			for _, dependency := range providerDependencies(provider) {
+				if !pm.serviceResolvable(module.Name, dependency) {
+					errs = append(errs, fmt.Errorf("%w: provider '%s' in module '%s' depends on '%s'",
+						ErrDependencyNotRegistered, provider.GetName(), module.Name, dependency))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validationCloner is implemented by containers that can copy their registrations,
+// without cached instances, for PluginManager.Validate
+type validationCloner interface {
+	cloneForValidation() DIContainer
+}
+
+// throwawayContainer returns a container with the same providers but no cached instances,
+// so validation builds services without populating the live container; containers
+// that cannot clone themselves are validated through a scope
+func throwawayContainer(container DIContainer) DIContainer {
+	if cloner, ok := container.(validationCloner); ok {
+		return cloner.cloneForValidation()
+	}
+	return container.CreateScope()
+}
+
+// initializeAsyncProviders pre-initializes all async providers but skip
+// Providers start in plugin order and their errors are reported in that order,
+// whichever finishes first
+func (pm *PluginManager) initializeAsyncProviders(ctx context.Context, plugins []Plugin, skip string) error {
+	type asyncProvider struct {
+		provider   Provider
+		moduleName string
+	}
 
 	// Group providers by module dependencies
+	var providers []asyncProvider
 	for _, plugin := range plugins {
 		moduleProvider, ok := plugin.(ModuleProvider)
 		if !ok {
@@ -211,54 +727,104 @@ func (pm *PluginManager) initializeAsyncProviders(ctx context.Context, plugins [
 			continue
 		}
 
-		// Initialize async providers for this module
 		for _, provider := range module.Providers {
-			if !provider.IsAsync() {
-				continue
+			if provider.IsAsync() && provider.GetName() != skip {
+				providers = append(providers, asyncProvider{provider: provider, moduleName: module.Name})
 			}
+		}
+	}
 
+	errs := make([]error, len(providers))
+	initialize := func(i int, p Provider, moduleName string) {
+		name := p.GetName()
+		if _, err := pm.container.ResolveWithContext(name, ctx); err != nil {
+			errs[i] = &AsyncProviderError{Provider: name, Module: moduleName, Err: err}
+		}
+	}
+
+	if pm.asyncInitConcurrency < 1 {
+		// Serial: one at a time, in plugin order
+		for i, entry := range providers {
+			initialize(i, entry.provider, entry.moduleName)
+		}
+	} else {
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, pm.asyncInitConcurrency)
+
+		// Initialize async providers
+		for i, entry := range providers {
 			wg.Add(1)
-			go func(p Provider, moduleName string) {
+			go func(i int, p Provider, moduleName string) {
 				defer wg.Done()
 
 				// Acquire semaphore to limit parallelism
 				semaphore <- struct{}{}
 				defer func() { <-semaphore }()
 
-				name := p.GetName()
-				if _, err := pm.container.(*diContainer).ResolveWithContext(name, ctx); err != nil {
-					errChan <- fmt.Errorf("async provider '%s' in module '%s' failed: %w",
-						name, moduleName, err)
-					return
-				}
-			}(provider, module.Name)
+				initialize(i, p, moduleName)
+			}(i, entry.provider, entry.moduleName)
 		}
-	}
 
-	// Wait for all async providers to complete
-	go func() {
+		// Wait for all async providers to complete
 		wg.Wait()
-		close(errChan)
-	}()
+	}
 
-	// Collect any errors
-	var errors []string
-	for err := range errChan {
-		if err != nil {
-			errors = append(errors, err.Error())
-		}
+	// Nil entries are dropped, so this is nil when every provider succeeded
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("async initialization errors: %w", err)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("async initialization errors: %v", errors)
+	return nil
+}
+
+// AsyncProviderError reports an async provider that failed to initialize; errors.Is
+// and errors.As reach its cause, e.g. context.DeadlineExceeded
+type AsyncProviderError struct {
+	Provider string
+	Module   string
+	Err      error
+}
+
+func (e *AsyncProviderError) Error() string {
+	return fmt.Sprintf("async provider '%s' in module '%s' failed: %v", e.Provider, e.Module, e.Err)
+}
+
+func (e *AsyncProviderError) Unwrap() error {
+	return e.Err
+}
+
+// initializeEagerSingletons resolves providers flagged as eager singletons, but skip
+// Plugins are expected in dependency order, so dependencies are built first
+func (pm *PluginManager) initializeEagerSingletons(ctx context.Context, plugins []Plugin, skip string) error {
+	for _, plugin := range plugins {
+		moduleProvider, ok := plugin.(ModuleProvider)
+		if !ok {
+			continue
+		}
+
+		module := moduleProvider.Module()
+		if module == nil {
+			continue
+		}
+
+		for _, provider := range module.Providers {
+			if !isEagerSingleton(provider) || provider.GetName() == skip {
+				continue
+			}
+
+			name := provider.GetName()
+			if _, err := pm.container.ResolveWithContext(name, ctx); err != nil {
+				return fmt.Errorf("eager singleton '%s' in module '%s' failed: %w", name, module.Name, err)
+			}
+		}
 	}
 
 	return nil
 }
 
-// RegisterRoutes registers routes for all plugins
+// RegisterRoutes registers routes for all plugins, in priority order
 func (pm *PluginManager) RegisterRoutes(router *gin.Engine) error {
-	for _, plugin := range pm.plugins {
+	for _, plugin := range pm.orderedPlugins() {
 		if err := plugin.Routes(router); err != nil {
 			return err
 		}
@@ -266,14 +832,29 @@ func (pm *PluginManager) RegisterRoutes(router *gin.Engine) error {
 	return nil
 }
 
-// ShutdownPlugins shuts down all registered plugins
+// ShutdownPlugins shuts down all registered plugins in reverse order, so a plugin
+// registered after the database (e.g. a queue consumer) stops before it, then
+// disposes the singletons of module containers and of the container
+// All shutdown and dispose errors are collected rather than stopping at the first
 func (pm *PluginManager) ShutdownPlugins() error {
-	for _, plugin := range pm.plugins {
+	var errs []error
+	for _, plugin := range slices.Backward(pm.orderedPlugins()) {
 		if err := plugin.Shutdown(); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("plugin '%s' shutdown failed: %w", plugin.Name(), err))
 		}
 	}
-	return nil
+
+	if pm.app != nil {
+		if err := pm.app.disposeModuleContainers(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := pm.container.Dispose(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
 // GetLifecycleManager returns the lifecycle manager
@@ -288,8 +869,8 @@ func (pm *PluginManager) GetAppHooks() []ApplicationHook {
 
 // ExecuteOnRoute notifies all RouteAwarePlugins about a new route
 func (pm *PluginManager) ExecuteOnRoute(config *RouteConfig) {
-	// Notify plugins implementing RouteAwarePlugin
-	for _, plugin := range pm.plugins {
+	// Notify plugins implementing RouteAwarePlugin, in priority order
+	for _, plugin := range pm.orderedPlugins() {
 		if routeAware, ok := plugin.(RouteAwarePlugin); ok {
 			routeAware.OnRoute(config)
 		}
@@ -299,6 +880,145 @@ func (pm *PluginManager) ExecuteOnRoute(config *RouteConfig) {
 	pm.lifecycle.ExecuteOnRoute(config)
 }
 
+// recordRoute adds a route to the registry returned by GetRoutes
+// A route colliding with a registered one is not recorded; the conflict is returned
+// and kept for RouteConflicts
+func (pm *PluginManager) recordRoute(route RouteInfo) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for _, existing := range pm.routes {
+		if routesConflict(existing, route) {
+			err := fmt.Errorf("%w: %s conflicts with %s", ErrRouteConflict, describeRoute(route), describeRoute(existing))
+			pm.routeConflicts = append(pm.routeConflicts, err)
+			return err
+		}
+	}
+	// gin still has the routes of unregistered plugins and would panic
+	for _, retired := range pm.retiredRoutes {
+		if routesConflict(retired, route) {
+			err := fmt.Errorf("%w: %s conflicts with %s of an unregistered plugin", ErrRouteConflict, describeRoute(route), describeRoute(retired))
+			pm.routeConflicts = append(pm.routeConflicts, err)
+			return err
+		}
+	}
+
+	pm.routes = append(pm.routes, route)
+
+	if isAuth, ok := route.Options["isAuth"].(bool); ok && !isAuth {
+		pm.publicRoutes[route.Method+":"+route.Path] = true
+	}
+	if route.Module != "" {
+		pm.routeModules[route.Method+":"+route.Path] = route.Module
+	}
+	return nil
+}
+
+// routeModule returns the module that registered the route pattern (e.g. c.FullPath()), or ""
+func (pm *PluginManager) routeModule(method, path string) string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if module, exists := pm.routeModules[method+":"+path]; exists {
+		return module
+	}
+	return pm.routeModules[MethodAny+":"+path]
+}
+
+// moduleErrorHandler returns the error handler of the module that registered the
+// route pattern, or nil
+func (pm *PluginManager) moduleErrorHandler(method, path string) ModuleErrorHandler {
+	name := pm.routeModule(method, path)
+	if name == "" {
+		return nil
+	}
+	if module, exists := pm.modules.GetModule(name); exists {
+		return module.ErrorHandler
+	}
+	return nil
+}
+
+// RouteConflicts returns every duplicate route registration detected so far, joined
+// Conflicting routes are skipped instead of being handed to gin, which would panic
+func (pm *PluginManager) RouteConflicts() error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return errors.Join(pm.routeConflicts...)
+}
+
+// routesConflict reports whether gin would reject b after a: same path pattern (wildcard
+// names ignored) and the same method, or either registered for any method
+func routesConflict(a, b RouteInfo) bool {
+	if a.Method != b.Method && a.Method != MethodAny && b.Method != MethodAny {
+		return false
+	}
+	return routePattern(a.Path) == routePattern(b.Path)
+}
+
+// routePattern normalizes ":name" and "*name" segments so /users/:id matches /users/:userId
+func routePattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = segment[:1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// describeRoute formats a route for conflict errors, e.g. "GET /api/v1/users (module 'users')"
+func describeRoute(route RouteInfo) string {
+	description := route.Method + " " + route.Path
+	if route.Module != "" {
+		description += fmt.Sprintf(" (module '%s')", route.Module)
+	}
+	return description
+}
+
+// markPublicRoute lets requests to the route pattern skip authentication, e.g. the
+// routes handing requests to a mounted app, which authenticates them itself
+func (pm *PluginManager) markPublicRoute(method, path string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.publicRoutes[method+":"+path] = true
+}
+
+// IsPublicRoute reports whether the route pattern (e.g. c.FullPath()) was registered with IsAuth: false
+func (pm *PluginManager) IsPublicRoute(method, path string) bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.publicRoutes[method+":"+path] || pm.publicRoutes[MethodAny+":"+path]
+}
+
+// GetRoutes returns every route registered through Router or EnhancedRouter, in registration order
+func (pm *PluginManager) GetRoutes() []RouteInfo {
+	routes := pm.registeredRoutes()
+	for i := range routes {
+		routes[i].params = nil // Kept for Validate only
+	}
+	return routes
+}
+
+// registeredRoutes returns a copy of the route registry
+func (pm *PluginManager) registeredRoutes() []RouteInfo {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return slices.Clone(pm.routes)
+}
+
+// orderedPlugins returns the plugins by priority, then registration order
+func (pm *PluginManager) orderedPlugins() []Plugin {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return slices.Clone(pm.ordered)
+}
+
+// lazyModule returns the lazy module of the given name, or nil
+func (pm *PluginManager) lazyModule(name string) *lazyModule {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.lazyModules[name]
+}
+
 // GetModuleGraph returns the module dependency graph
 func (pm *PluginManager) GetModuleGraph() *ModuleGraph {
 	return pm.modules
@@ -313,7 +1033,7 @@ func (pm *PluginManager) GetInitializationOrder() ([]Plugin, error) {
 
 	result := make([]Plugin, 0, len(sortedModules))
 	for _, module := range sortedModules {
-		if plugin, exists := pm.plugins[module.Name]; exists {
+		if plugin, exists := pm.GetPlugin(module.Name); exists {
 			result = append(result, plugin)
 		}
 	}
@@ -321,22 +1041,18 @@ func (pm *PluginManager) GetInitializationOrder() ([]Plugin, error) {
 	return result, nil
 }
 
-// GetEnhancedRouterForModule creates an EnhancedRouter with the module's prefix
+// GetEnhancedRouterForModule creates an EnhancedRouter with the module's full prefix
+// (including the prefixes of the modules importing it)
 func (pm *PluginManager) GetEnhancedRouterForModule(moduleName string) *EnhancedRouter {
-	prefix, exists := pm.modulePrefixes[moduleName]
-	if !exists {
-		prefix = ""
-	}
-	return NewEnhancedRouterWithPrefix(pm.app.server, pm.container, prefix)
+	router := NewEnhancedRouterWithPrefix(pm.app.server, pm.container, pm.modules.GetFullPrefix(moduleName))
+	router.module = moduleName
+	router.missingRequestContainer = pm.app.missingRequestContainer
+	return router
 }
 
-// GetModulePrefix returns the prefix for a given module
+// GetModulePrefix returns the full prefix for a given module
 func (pm *PluginManager) GetModulePrefix(moduleName string) string {
-	prefix, exists := pm.modulePrefixes[moduleName]
-	if !exists {
-		return ""
-	}
-	return prefix
+	return pm.modules.GetFullPrefix(moduleName)
 }
 
 // Plugin errors
@@ -344,8 +1060,15 @@ var (
 	ErrPluginNil                  = newError("plugin cannot be nil")
 	ErrPluginAlreadyRegistered    = newError("plugin is already registered")
 	ErrPluginNotFound             = newError("plugin not found")
+	ErrPluginHasDependents        = newError("plugin is imported by other modules")
 	ErrPluginRegistrationFailed   = newError("plugin registration failed")
 	ErrPluginInitializationFailed = newError("plugin initialization failed")
+	ErrPluginConfigInvalid        = newError("plugin configuration invalid")
+	ErrRouteConflict              = newError("route already registered")
+	ErrUnresolvableHandlerParam   = newError("route handler parameter cannot be resolved")
+	ErrPrefixCollision            = newError("module prefix overlaps another module's")
+	ErrAmbiguousModuleParent      = newError("module is imported by modules with different prefixes")
+	ErrProviderNameCollision      = newError("provider name is registered by another plugin")
 )
 
 // BasePlugin provides a default implementation for optional plugin methods
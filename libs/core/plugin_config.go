@@ -0,0 +1,63 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ConfigurablePlugin is a plugin configured from its AppOptions.Plugins entry
+// Configure is called by RegisterPlugin, before Register, usually to BindConfig the
+// settings into a struct; an error fails the registration with ErrPluginConfigInvalid
+type ConfigurablePlugin interface {
+	Plugin
+	// Configure receives the Config of the PluginConfig named like the plugin, nested
+	// maps as dotted keys (e.g. "pool.size"); it is empty when there is none
+	Configure(cm ConfigManager) error
+}
+
+// SetPluginConfigs sets the configuration ConfigurablePlugins receive, by plugin name
+// The app sets AppOptions.Plugins; a later entry with the same name wins
+func (pm *PluginManager) SetPluginConfigs(configs []PluginConfig) {
+	pm.pluginConfigs = make(map[string]map[string]interface{}, len(configs))
+	for _, config := range configs {
+		pm.pluginConfigs[config.Name] = config.Config
+	}
+}
+
+// configurePlugin hands a ConfigurablePlugin its configuration
+func (pm *PluginManager) configurePlugin(plugin Plugin) error {
+	configurable, ok := plugin.(ConfigurablePlugin)
+	if !ok {
+		return nil
+	}
+
+	cm := NewConfigManager().(*configManager)
+	for key, value := range cm.flatten(pm.pluginConfigs[plugin.Name()]) {
+		cm.data[key] = value
+	}
+	if err := configurable.Configure(cm); err != nil {
+		return fmt.Errorf("%w: '%s': %w", ErrPluginConfigInvalid, plugin.Name(), err)
+	}
+	return nil
+}
+
+// BindConfig unmarshals a configuration into target, a struct pointer whose preset
+// fields are kept as defaults for absent keys, then validates it with its `binding`
+// tags (go-playground/validator), e.g.
+//
+//	type CacheSettings struct {
+//		TTL  int `json:"ttl" binding:"min=1"`
+//		Pool struct {
+//			Size int `json:"size" binding:"required"`
+//		} `json:"pool"`
+//	}
+func BindConfig(cm ConfigManager, target interface{}) error {
+	if err := cm.Unmarshal(target); err != nil {
+		return fmt.Errorf("failed to bind config: %w", err)
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,924 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moduleTestPlugin is a minimal ModuleProvider registering its module's providers
+type moduleTestPlugin struct {
+	BasePlugin
+	module *Module
+}
+
+func newModuleTestPlugin(module *Module) *moduleTestPlugin {
+	return &moduleTestPlugin{module: module}
+}
+
+func (p *moduleTestPlugin) Name() string           { return p.module.Name }
+func (p *moduleTestPlugin) Version() string        { return p.module.Version }
+func (p *moduleTestPlugin) Hooks() []LifecycleHook { return nil }
+func (p *moduleTestPlugin) Module() *Module        { return p.module }
+func (p *moduleTestPlugin) Register(container DIContainer) error {
+	for _, provider := range p.module.Providers {
+		if err := container.RegisterProvider(provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPluginManager_EagerSingletons(t *testing.T) {
+	container := NewDIContainer()
+	pm := NewPluginManager(nil, container)
+
+	var built []string
+	module := NewModule("cache", "1.0.0").
+		WithProviders(
+			NewEagerSingletonProvider("cachePool", func(c DIContainer) (interface{}, error) {
+				built = append(built, "cachePool")
+				return "pool", nil
+			}),
+			NewFactoryProvider("lazyService", func(c DIContainer) (interface{}, error) {
+				built = append(built, "lazyService")
+				return "lazy", nil
+			}, Singleton),
+		)
+
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+	assert.Empty(t, built, "providers should not be built during registration")
+
+	require.NoError(t, pm.InitializePlugins())
+	assert.Equal(t, []string{"cachePool"}, built)
+
+	// The eager instance is cached as a regular singleton
+	_, err := container.Resolve("cachePool")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cachePool"}, built)
+}
+
+func TestPluginManager_EagerSingletonFailureAbortsStartup(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+
+	module := NewModule("database", "1.0.0").
+		WithProviders(NewEagerSingletonProvider("dbPool", func(c DIContainer) (interface{}, error) {
+			return nil, errors.New("connection refused")
+		}))
+
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+
+	err := pm.InitializePlugins()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "eager singleton 'dbPool' in module 'database' failed")
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+// initRecordingPlugin records whether Init was called
+type initRecordingPlugin struct {
+	*moduleTestPlugin
+	initialized bool
+}
+
+func (p *initRecordingPlugin) Init(app *DoffApp) error {
+	p.initialized = true
+	return nil
+}
+
+func TestPluginManager_ValidateHasNoSideEffects(t *testing.T) {
+	container := NewDIContainer()
+	pm := NewPluginManager(nil, container)
+
+	builds := 0
+	module := NewModule("cache", "1.0.0").
+		WithProviders(
+			NewEagerSingletonProvider("cachePool", func(c DIContainer) (interface{}, error) {
+				builds++
+				return "pool", nil
+			}),
+			NewAsyncProvider("warmCache", func(c DIContainer, ctx context.Context) (interface{}, error) {
+				return c.Resolve("cachePool")
+			}, Singleton),
+		)
+
+	plugin := &initRecordingPlugin{moduleTestPlugin: newModuleTestPlugin(module)}
+	require.NoError(t, pm.RegisterPlugin(plugin))
+
+	require.NoError(t, pm.Validate(context.Background()))
+	assert.Equal(t, 1, builds)
+	assert.False(t, plugin.initialized, "Validate must not call plugin Init")
+
+	// The live container did not cache the instances built during validation
+	_, err := container.Resolve("cachePool")
+	require.NoError(t, err)
+	assert.Equal(t, 2, builds)
+}
+
+func TestPluginManager_DeclaredDependencyMissing(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+
+	built := false
+	reports := NewFactoryProvider("reportService", func(c DIContainer) (interface{}, error) {
+		built = true
+		return c.Resolve("warehouse")
+	}, Singleton)
+	reports.DependsOn = []string{"warehouse"}
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(NewModule("reports", "1.0.0").WithProviders(reports))))
+
+	err := pm.Validate(context.Background())
+	require.ErrorIs(t, err, ErrDependencyNotRegistered)
+	assert.Contains(t, err.Error(), "provider 'reportService' in module 'reports' depends on 'warehouse'")
+
+	err = pm.InitializePlugins()
+	require.ErrorIs(t, err, ErrDependencyNotRegistered)
+	assert.False(t, pm.IsInitialized())
+	assert.False(t, built, "declared dependencies are checked without building the provider")
+
+	// Registering the dependency from another module fixes it
+	warehouse := NewModule("warehouse", "1.0.0").WithProviders(NewValueProvider("warehouse", "bigquery"))
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(warehouse)))
+	require.NoError(t, pm.Validate(context.Background()))
+	require.NoError(t, pm.InitializePlugins())
+}
+
+func TestPluginManager_ValidateAggregatesErrors(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+
+	errDatabase := errors.New("database unreachable")
+	errQueue := errors.New("queue unreachable")
+	module := NewModule("infra", "1.0.0").
+		WithProviders(
+			NewAsyncProvider("database", func(c DIContainer, ctx context.Context) (interface{}, error) {
+				return nil, errDatabase
+			}, Singleton),
+			NewEagerSingletonProvider("queue", func(c DIContainer) (interface{}, error) {
+				return nil, errQueue
+			}),
+		)
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+
+	err := pm.Validate(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errDatabase)
+	assert.ErrorIs(t, err, errQueue)
+	assert.Contains(t, err.Error(), "provider 'database' in module 'infra' failed")
+}
+
+// recordingContainer wraps a DIContainer, recording the names it resolves
+type recordingContainer struct {
+	DIContainer
+	mu       sync.Mutex
+	resolved []string
+}
+
+func (c *recordingContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
+	c.mu.Lock()
+	c.resolved = append(c.resolved, name)
+	c.mu.Unlock()
+	return c.DIContainer.ResolveWithContext(name, ctx)
+}
+
+func TestPluginManager_AsyncInitWithCustomContainer(t *testing.T) {
+	container := &recordingContainer{DIContainer: NewDIContainer()}
+	pm := NewPluginManager(nil, container)
+
+	module := NewModule("infra", "1.0.0").
+		WithProviders(
+			NewAsyncProvider("database", func(c DIContainer, ctx context.Context) (interface{}, error) {
+				return "connected", nil
+			}, Singleton),
+		)
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+
+	require.NoError(t, pm.InitializePlugins())
+	assert.Contains(t, container.resolved, "database")
+
+	db, err := container.Resolve("database")
+	require.NoError(t, err)
+	assert.Equal(t, "connected", db)
+}
+
+// orderedPlugin records its OnRequest hook, Routes and Shutdown calls in a shared log
+type orderedPlugin struct {
+	*moduleTestPlugin
+	log *[]string
+}
+
+func newOrderedPlugin(name string, log *[]string) *orderedPlugin {
+	return &orderedPlugin{moduleTestPlugin: newModuleTestPlugin(NewModule(name, "1.0.0")), log: log}
+}
+
+func (p *orderedPlugin) Hooks() []LifecycleHook {
+	return []LifecycleHook{NewOnRequestHook(func(c *gin.Context) {
+		*p.log = append(*p.log, "request:"+p.Name())
+	})}
+}
+
+func (p *orderedPlugin) Routes(router *gin.Engine) error {
+	*p.log = append(*p.log, "routes:"+p.Name())
+	return nil
+}
+
+func (p *orderedPlugin) Shutdown() error {
+	*p.log = append(*p.log, "shutdown:"+p.Name())
+	return nil
+}
+
+// prioritizedPlugin is an orderedPlugin implementing PrioritizedPlugin
+type prioritizedPlugin struct {
+	*orderedPlugin
+	priority int
+}
+
+func (p *prioritizedPlugin) Priority() int { return p.priority }
+
+func TestPluginManager_HooksFollowRegistrationOrder(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log []string
+	for _, name := range []string{"cors", "auth", "logger", "audit"} {
+		require.NoError(t, app.RegisterPlugin(newOrderedPlugin(name, &log)))
+	}
+	app.GetEngine().GET("/ping", func(c *gin.Context) {})
+
+	require.NoError(t, app.GetPluginManager().RegisterRoutes(app.GetEngine()))
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	assert.Equal(t, []string{
+		"routes:cors", "routes:auth", "routes:logger", "routes:audit",
+		"request:cors", "request:auth", "request:logger", "request:audit",
+	}, log)
+}
+
+func TestPluginManager_PriorityOverridesRegistrationOrder(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log []string
+	require.NoError(t, app.RegisterPlugin(newOrderedPlugin("logger", &log)))
+	require.NoError(t, app.RegisterPlugin(&prioritizedPlugin{orderedPlugin: newOrderedPlugin("auth", &log), priority: -10}))
+	require.NoError(t, app.RegisterPlugin(&prioritizedPlugin{orderedPlugin: newOrderedPlugin("cors", &log), priority: -20}))
+	require.NoError(t, app.RegisterPlugin(&prioritizedPlugin{orderedPlugin: newOrderedPlugin("audit", &log), priority: 10}))
+	require.NoError(t, app.RegisterPlugin(newOrderedPlugin("metrics", &log)))
+	app.GetEngine().GET("/ping", func(c *gin.Context) {})
+
+	require.NoError(t, app.GetPluginManager().RegisterRoutes(app.GetEngine()))
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	assert.Equal(t, []string{
+		"routes:cors", "routes:auth", "routes:logger", "routes:metrics", "routes:audit",
+		"request:cors", "request:auth", "request:logger", "request:metrics", "request:audit",
+	}, log)
+}
+
+func TestPluginManager_RoutesAndShutdownFollowRegistrationOrder(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log []string
+	for _, name := range []string{"database", "cache", "consumer"} {
+		require.NoError(t, app.RegisterPlugin(newOrderedPlugin(name, &log)))
+	}
+
+	pm := app.GetPluginManager()
+	require.NoError(t, pm.RegisterRoutes(app.GetEngine()))
+	require.NoError(t, pm.ShutdownPlugins())
+
+	assert.Equal(t, []string{
+		"routes:database", "routes:cache", "routes:consumer",
+		"shutdown:consumer", "shutdown:cache", "shutdown:database",
+	}, log)
+}
+
+func TestPluginManager_ShutdownDisposesModuleContainers(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var closed []string
+	database := NewModule("database", "1.0.0")
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(database)))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("users", "1.0.0").WithImports(database))))
+	require.NoError(t, app.GetContainer().RegisterSingleton("rootPool", func(c DIContainer) (interface{}, error) {
+		return &disposableService{name: "rootPool", closed: &closed}, nil
+	}))
+	_, err := app.GetContainer().Resolve("rootPool")
+	require.NoError(t, err)
+
+	// Created in the opposite order to the plugins, which decides the dispose order
+	for _, name := range []string{"users", "database"} {
+		mc, exists := app.GetModuleContainer(name)
+		require.True(t, exists)
+		require.NoError(t, mc.RegisterSingleton(name+"Cache", func(c DIContainer) (interface{}, error) {
+			return &disposableService{name: name + "Cache", closed: &closed, err: errors.New(name + " close failed")}, nil
+		}))
+		_, err := mc.Resolve(name + "Cache")
+		require.NoError(t, err)
+	}
+
+	err = app.GetPluginManager().ShutdownPlugins()
+	assert.ErrorContains(t, err, "users close failed")
+	assert.ErrorContains(t, err, "database close failed")
+	assert.Equal(t, []string{"usersCache", "databaseCache", "rootPool"}, closed)
+}
+
+func TestPluginManager_AsyncProviderErrorsFollowPluginOrder(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+
+	var plugins []Plugin
+	for _, name := range []string{"first", "second", "third"} {
+		module := NewModule(name, "1.0.0").WithProviders(
+			NewAsyncProvider(name+"DB", func(c DIContainer, ctx context.Context) (interface{}, error) {
+				if name == "first" {
+					// Finish last, so completion order differs from plugin order
+					time.Sleep(20 * time.Millisecond)
+				}
+				return nil, errors.New(name + " unreachable")
+			}, Singleton),
+		)
+		plugin := newModuleTestPlugin(module)
+		require.NoError(t, pm.RegisterPlugin(plugin))
+		plugins = append(plugins, plugin)
+	}
+
+	err := pm.initializeAsyncProviders(context.Background(), plugins, "")
+	require.Error(t, err)
+	message := err.Error()
+	first := strings.Index(message, "first unreachable")
+	second := strings.Index(message, "second unreachable")
+	third := strings.Index(message, "third unreachable")
+	assert.True(t, first >= 0 && first < second && second < third, message)
+}
+
+func TestPluginManager_AsyncProviderErrorsKeepTheirCause(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+	errRefused := errors.New("connection refused")
+
+	cache := NewModule("cache", "1.0.0").WithProviders(
+		NewAsyncProviderWithTimeout("redis", func(c DIContainer, ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, Singleton, 10*time.Millisecond),
+	)
+	storage := NewModule("storage", "1.0.0").WithProviders(
+		NewAsyncProvider("postgres", func(c DIContainer, ctx context.Context) (interface{}, error) {
+			return nil, errRefused
+		}, Singleton),
+	)
+	plugins := []Plugin{newModuleTestPlugin(cache), newModuleTestPlugin(storage)}
+	for _, plugin := range plugins {
+		require.NoError(t, pm.RegisterPlugin(plugin))
+	}
+
+	err := pm.initializeAsyncProviders(context.Background(), plugins, "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, errRefused)
+
+	var providerErr *AsyncProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, "redis", providerErr.Provider)
+	assert.Equal(t, "cache", providerErr.Module)
+	assert.Contains(t, err.Error(), "async provider 'postgres' in module 'storage' failed")
+}
+
+// concurrencyProbe tracks how many async factories run at once, and their start order
+type concurrencyProbe struct {
+	mu      sync.Mutex
+	active  int
+	max     int
+	started []string
+}
+
+func (p *concurrencyProbe) register(t *testing.T, pm *PluginManager, count int) []Plugin {
+	var plugins []Plugin
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("pool%d", i)
+		module := NewModule(name, "1.0.0").WithProviders(
+			NewAsyncProvider(name, func(c DIContainer, ctx context.Context) (interface{}, error) {
+				p.mu.Lock()
+				p.active++
+				p.max = max(p.max, p.active)
+				p.started = append(p.started, name)
+				p.mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				p.mu.Lock()
+				p.active--
+				p.mu.Unlock()
+				return name, nil
+			}, Singleton),
+		)
+		plugin := newModuleTestPlugin(module)
+		require.NoError(t, pm.RegisterPlugin(plugin))
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
+func TestPluginManager_AsyncInitConcurrencyIsBounded(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+	pm.SetAsyncInitConcurrency(2)
+	probe := &concurrencyProbe{}
+	plugins := probe.register(t, pm, 6)
+
+	require.NoError(t, pm.initializeAsyncProviders(context.Background(), plugins, ""))
+	assert.Equal(t, 2, probe.max)
+	assert.Len(t, probe.started, 6)
+}
+
+func TestPluginManager_AsyncInitSerial(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+	pm.SetAsyncInitConcurrency(0)
+	probe := &concurrencyProbe{}
+	plugins := probe.register(t, pm, 4)
+
+	require.NoError(t, pm.initializeAsyncProviders(context.Background(), plugins, ""))
+	assert.Equal(t, 1, probe.max)
+	assert.Equal(t, []string{"pool0", "pool1", "pool2", "pool3"}, probe.started)
+}
+
+func TestDoffApp_AsyncInitConcurrencyOption(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{Name: "async-init", Mode: gin.TestMode}).(*DoffApp)
+	assert.Equal(t, DefaultAsyncInitConcurrency, app.GetPluginManager().asyncInitConcurrency)
+
+	for _, limit := range []int{4, 0, -1} {
+		app = CreateDoffApp(&AppOptions{Name: "async-init", Mode: gin.TestMode, AsyncInitConcurrency: &limit}).(*DoffApp)
+		assert.Equal(t, limit, app.GetPluginManager().asyncInitConcurrency)
+	}
+
+	// Zero means serial, not the default
+	serial := 0
+	app = CreateDoffApp(&AppOptions{Name: "async-init", Mode: gin.TestMode, AsyncInitConcurrency: &serial}).(*DoffApp)
+	probe := &concurrencyProbe{}
+	plugins := probe.register(t, app.GetPluginManager(), 3)
+	require.NoError(t, app.GetPluginManager().initializeAsyncProviders(context.Background(), plugins, ""))
+	assert.Equal(t, 1, probe.max)
+	assert.Equal(t, []string{"pool0", "pool1", "pool2"}, probe.started)
+}
+
+func TestPluginManager_UnregisterThenReregister(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log, closed []string
+	newCachePlugin := func() *orderedPlugin {
+		module := NewModule("cache", "1.0.0").WithProviders(
+			NewFactoryProvider("cachePool", func(c DIContainer) (interface{}, error) {
+				return &disposableService{name: "cachePool", closed: &closed}, nil
+			}, Singleton),
+		)
+		return &orderedPlugin{moduleTestPlugin: newModuleTestPlugin(module), log: &log}
+	}
+	app.GetEngine().GET("/ping", func(c *gin.Context) {})
+	ping := func() {
+		app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	}
+
+	require.NoError(t, app.RegisterPlugin(newCachePlugin()))
+	_, err := app.GetContainer().Resolve("cachePool")
+	require.NoError(t, err)
+	ping()
+
+	require.NoError(t, app.UnregisterPlugin("cache"))
+	ping()
+	assert.Equal(t, []string{"request:cache", "shutdown:cache"}, log)
+	assert.Equal(t, []string{"cachePool"}, closed, "cached singletons are disposed")
+	assert.False(t, app.GetContainer().Has("cachePool"))
+	_, exists := app.GetPluginManager().GetPlugin("cache")
+	assert.False(t, exists)
+	_, exists = app.GetPluginManager().GetModuleGraph().GetModule("cache")
+	assert.False(t, exists)
+	assert.ErrorIs(t, app.UnregisterPlugin("cache"), ErrPluginNotFound)
+
+	log = nil
+	require.NoError(t, app.RegisterPlugin(newCachePlugin()))
+	ping()
+	assert.Equal(t, []string{"request:cache"}, log)
+	assert.True(t, app.GetContainer().Has("cachePool"))
+}
+
+func TestPluginManager_UnregisterImportedPluginFails(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log []string
+	database := NewModule("database", "1.0.0")
+	require.NoError(t, app.RegisterPlugin(&orderedPlugin{moduleTestPlugin: newModuleTestPlugin(database), log: &log}))
+	require.NoError(t, app.RegisterPlugin(&orderedPlugin{
+		moduleTestPlugin: newModuleTestPlugin(NewModule("users", "1.0.0").WithImports(database)),
+		log:              &log,
+	}))
+
+	err := app.UnregisterPlugin("database")
+	assert.ErrorIs(t, err, ErrPluginHasDependents)
+	assert.Contains(t, err.Error(), "users")
+	assert.Empty(t, log, "a plugin that cannot be removed is not shut down")
+	_, exists := app.GetPluginManager().GetPlugin("database")
+	assert.True(t, exists)
+
+	require.NoError(t, app.UnregisterPlugin("users"))
+	require.NoError(t, app.UnregisterPlugin("database"))
+	assert.Equal(t, []string{"shutdown:users", "shutdown:database"}, log)
+}
+
+// statusModulePlugin serves GET status under its module's prefix
+type statusModulePlugin struct {
+	*moduleTestPlugin
+}
+
+func (p *statusModulePlugin) Init(app *DoffApp) error {
+	app.GetPluginManager().GetEnhancedRouterForModule(p.Name()).GET(RouteConfig{Path: "status"}, func(c *gin.Context, controller *routeTestController) {
+		c.Status(http.StatusOK)
+	})
+	return nil
+}
+
+func TestPluginManager_UnregisterWhileServing(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+	newCachePlugin := func() *statusModulePlugin {
+		module := NewModule("cache", "1.0.0").WithPrefix("/cache").WithProviders(NewValueProvider("cachePool", "pool"))
+		return &statusModulePlugin{moduleTestPlugin: newModuleTestPlugin(module)}
+	}
+	require.NoError(t, app.RegisterPlugin(newCachePlugin()))
+	require.NoError(t, app.Init())
+	require.Equal(t, http.StatusOK, serveMount(app, http.MethodGet, "/cache/status").Code)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				code := serveMount(app, http.MethodGet, "/cache/status").Code
+				assert.Contains(t, []int{http.StatusOK, http.StatusNotFound}, code)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, app.UnregisterPlugin("cache"))
+	close(stop)
+	wg.Wait()
+
+	// The plugin's routes are disabled and leave the registry
+	assert.Equal(t, http.StatusNotFound, serveMount(app, http.MethodGet, "/cache/status").Code)
+	assert.Contains(t, app.DisabledRoutes(), "GET:/cache/status")
+	for _, route := range app.GetRoutes() {
+		assert.NotEqual(t, "/cache/status", route.Path)
+	}
+
+	// gin keeps the old route, so registering it again is reported rather than panicking
+	require.NoError(t, app.RegisterPlugin(newCachePlugin()))
+	assert.NotPanics(t, func() {
+		app.GetPluginManager().GetEnhancedRouterForModule("cache").GET(RouteConfig{Path: "status"}, func(c *gin.Context, controller *routeTestController) {})
+	})
+	assert.ErrorIs(t, app.GetPluginManager().RouteConflicts(), ErrRouteConflict)
+}
+
+// newLazyReportsModule returns a lazy module whose async and eager providers count their builds
+func newLazyReportsModule(asyncBuilds, eagerBuilds *int) *Module {
+	module := NewModule("reports", "1.0.0").
+		WithProviders(
+			NewAsyncProvider("reportEngine", func(c DIContainer, ctx context.Context) (interface{}, error) {
+				*asyncBuilds++
+				return "engine", nil
+			}, Singleton),
+			NewEagerSingletonProvider("reportCache", func(c DIContainer) (interface{}, error) {
+				*eagerBuilds++
+				return "cache", nil
+			}),
+			NewFactoryProvider("reportService", func(c DIContainer) (interface{}, error) {
+				return "service", nil
+			}, Transient),
+		)
+	module.Lazy = true
+	return module
+}
+
+func TestPluginManager_LazyModuleInitializesOnFirstResolve(t *testing.T) {
+	container := NewDIContainer()
+	pm := NewPluginManager(nil, container)
+
+	var asyncBuilds, eagerBuilds int
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(newLazyReportsModule(&asyncBuilds, &eagerBuilds))))
+	require.NoError(t, pm.InitializePlugins())
+	assert.Zero(t, asyncBuilds, "a lazy module's async provider must wait for first access")
+	assert.Zero(t, eagerBuilds)
+
+	for i := 0; i < 2; i++ {
+		service, err := container.Resolve("reportService")
+		require.NoError(t, err)
+		assert.Equal(t, "service", service)
+	}
+	assert.Equal(t, 1, asyncBuilds)
+	assert.Equal(t, 1, eagerBuilds)
+
+	// Resolving the async provider first builds it once
+	container = NewDIContainer()
+	pm = NewPluginManager(nil, container)
+	asyncBuilds, eagerBuilds = 0, 0
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(newLazyReportsModule(&asyncBuilds, &eagerBuilds))))
+	require.NoError(t, pm.InitializePlugins())
+
+	engine, err := container.Resolve("reportEngine")
+	require.NoError(t, err)
+	assert.Equal(t, "engine", engine)
+	assert.Equal(t, 1, asyncBuilds)
+	assert.Equal(t, 1, eagerBuilds)
+}
+
+func TestPluginManager_LazyModuleInitializesOnFirstRequest(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var asyncBuilds, eagerBuilds int
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(newLazyReportsModule(&asyncBuilds, &eagerBuilds))))
+	require.NoError(t, app.Init())
+	app.pluginManager.GetEnhancedRouterForModule("reports").GET(RouteConfig{Path: "/reports"}, func(c *gin.Context, params struct{}) {
+		c.Status(http.StatusOK)
+	})
+	assert.Zero(t, asyncBuilds)
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/reports", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, asyncBuilds)
+	assert.Equal(t, 1, eagerBuilds)
+}
+
+// unconfiguredPlugin registers some of its services, then fails like an integration
+// missing its configuration
+type unconfiguredPlugin struct {
+	*orderedPlugin
+}
+
+func (p *unconfiguredPlugin) Register(container DIContainer) error {
+	if err := p.orderedPlugin.Register(container); err != nil {
+		return err
+	}
+	return errors.New("PAYMENTS_API_KEY is not set")
+}
+
+func TestPluginManager_RegisterOptionalPlugin(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetEngine().GET("/ping", func(c *gin.Context) {})
+	var log []string
+
+	module := NewModule("payments", "1.0.0").WithProviders(NewValueProvider("paymentsClient", "client"))
+	payments := &unconfiguredPlugin{orderedPlugin: &orderedPlugin{moduleTestPlugin: newModuleTestPlugin(module), log: &log}}
+	assert.False(t, app.RegisterOptionalPlugin(payments))
+	assert.True(t, app.RegisterOptionalPlugin(newOrderedPlugin("cache", &log)))
+
+	// The failed plugin leaves nothing behind
+	assert.False(t, app.GetContainer().Has("paymentsClient"))
+	_, exists := app.GetPluginManager().GetPlugin("payments")
+	assert.False(t, exists)
+	_, exists = app.GetPluginManager().GetModuleGraph().GetModule("payments")
+	assert.False(t, exists)
+
+	require.NoError(t, app.GetPluginManager().InitializePlugins())
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, []string{"request:cache"}, log)
+
+	skipped := app.SkippedPlugins()
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "payments", skipped[0].Name)
+	assert.Contains(t, skipped[0].Error, "PAYMENTS_API_KEY is not set")
+
+	// A later successful registration clears the skip
+	require.NoError(t, app.UnregisterPlugin("cache"))
+	module = NewModule("payments", "1.0.0").WithProviders(NewValueProvider("paymentsClient", "client"))
+	assert.True(t, app.RegisterOptionalPlugin(newModuleTestPlugin(module)))
+	assert.Empty(t, app.SkippedPlugins())
+	assert.True(t, app.GetContainer().Has("paymentsClient"))
+}
+
+func TestPluginManager_RegisterPluginFailureWrapsCause(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	var log []string
+	plugin := &unconfiguredPlugin{orderedPlugin: newOrderedPlugin("payments", &log)}
+
+	err := app.RegisterPlugin(plugin)
+	assert.ErrorIs(t, err, ErrPluginRegistrationFailed)
+	assert.Contains(t, err.Error(), "PAYMENTS_API_KEY is not set")
+
+	// Nothing was left registered, so the plugin can be registered again
+	assert.ErrorIs(t, app.RegisterPlugin(plugin), ErrPluginRegistrationFailed)
+	assert.Empty(t, app.SkippedPlugins())
+}
+
+func TestPluginManager_PrefixCollisionMode(t *testing.T) {
+	newApp := func(mode PrefixCollisionMode) *DoffApp {
+		app := CreateDoffApp(&AppOptions{Name: "prefix-test", Mode: gin.TestMode, PrefixCollisions: mode}).(*DoffApp)
+		require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("api", "1.0.0").WithPrefix("/api"))))
+		return app
+	}
+	v1 := func() Plugin {
+		return newModuleTestPlugin(NewModule("v1", "1.0.0").WithPrefix("/api/v1").
+			WithProviders(NewValueProvider("v1Client", "client")))
+	}
+
+	t.Run("enforce", func(t *testing.T) {
+		app := newApp(PrefixCollisionsEnforce)
+		err := app.RegisterPlugin(v1())
+		assert.ErrorIs(t, err, ErrPrefixCollision)
+		assert.ErrorContains(t, err, "module 'v1' (/api/v1) overlaps 'api' (/api)")
+		_, exists := app.GetPluginManager().GetModuleGraph().GetModule("v1")
+		assert.False(t, exists)
+		assert.False(t, app.GetContainer().Has("v1Client"))
+
+		require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("orders", "1.0.0").WithPrefix("/orders"))))
+		assert.ErrorIs(t, app.MountModule("orders", "/api/orders"), ErrPrefixCollision)
+		assert.Equal(t, "/orders", app.GetPluginManager().GetModulePrefix("orders"))
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		app := newApp(PrefixCollisionsWarn)
+		assert.NoError(t, app.RegisterPlugin(v1()))
+	})
+
+	t.Run("allowed by default", func(t *testing.T) {
+		app := newApp(PrefixCollisionsAllowed)
+		assert.NoError(t, app.RegisterPlugin(v1()))
+	})
+}
+
+func TestPluginManager_RejectsAmbiguousModuleParent(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{Name: "parent-test", Mode: gin.TestMode}).(*DoffApp)
+	reports := NewModule("reports", "1.0.0").WithPrefix("/reports")
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(reports)))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(
+		NewModule("users", "1.0.0").WithPrefix("/users").WithImports(reports))))
+
+	err := app.RegisterPlugin(newModuleTestPlugin(NewModule("orders", "1.0.0").WithPrefix("/orders").WithImports(reports)))
+	assert.ErrorIs(t, err, ErrAmbiguousModuleParent)
+	_, exists := app.GetPluginManager().GetModuleGraph().GetModule("orders")
+	assert.False(t, exists)
+	assert.Equal(t, "/users/reports", app.GetPluginManager().GetModulePrefix("reports"))
+
+	// Once mounted, the module no longer depends on its importers' prefixes
+	require.NoError(t, app.MountModule("reports", "/reports"))
+	assert.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("orders", "1.0.0").WithPrefix("/orders").WithImports(reports))))
+}
+
+// cacheSettings are the typed settings of configurableTestPlugin
+type cacheSettings struct {
+	TTL  int `json:"ttl" binding:"min=1"`
+	Pool struct {
+		Size int    `json:"size" binding:"required"`
+		Mode string `json:"mode" binding:"oneof=fifo lru"`
+	} `json:"pool"`
+}
+
+// configurableTestPlugin binds its configuration over defaults
+type configurableTestPlugin struct {
+	BasePlugin
+	settings cacheSettings
+}
+
+func newConfigurableTestPlugin() *configurableTestPlugin {
+	p := &configurableTestPlugin{}
+	p.settings.TTL = 60
+	p.settings.Pool.Mode = "lru"
+	return p
+}
+
+func (p *configurableTestPlugin) Name() string                         { return "cache" }
+func (p *configurableTestPlugin) Version() string                      { return "1.0.0" }
+func (p *configurableTestPlugin) Hooks() []LifecycleHook               { return nil }
+func (p *configurableTestPlugin) Register(container DIContainer) error { return nil }
+func (p *configurableTestPlugin) Configure(cm ConfigManager) error {
+	return BindConfig(cm, &p.settings)
+}
+
+func TestPluginManager_ConfigurablePluginBindsConfig(t *testing.T) {
+	pm := NewPluginManager(nil, NewDIContainer())
+	pm.SetPluginConfigs([]PluginConfig{
+		{Name: "other", Config: map[string]interface{}{"ttl": 1}},
+		{Name: "cache", Config: map[string]interface{}{
+			"pool": map[string]interface{}{"size": 8},
+		}},
+	})
+
+	plugin := newConfigurableTestPlugin()
+	require.NoError(t, pm.RegisterPlugin(plugin))
+
+	assert.Equal(t, 8, plugin.settings.Pool.Size)
+	// Absent keys keep their defaults
+	assert.Equal(t, 60, plugin.settings.TTL)
+	assert.Equal(t, "lru", plugin.settings.Pool.Mode)
+}
+
+func TestPluginManager_ConfigurablePluginRejectsInvalidConfig(t *testing.T) {
+	app := CreateDoffApp(&AppOptions{
+		Name: "plugin-config-test",
+		Mode: gin.TestMode,
+		Plugins: []PluginConfig{{Name: "cache", Config: map[string]interface{}{
+			"ttl":  120,
+			"pool": map[string]interface{}{"size": 4, "mode": "random"},
+		}}},
+	})
+
+	err := app.RegisterPlugin(newConfigurableTestPlugin())
+	require.ErrorIs(t, err, ErrPluginConfigInvalid)
+	assert.Contains(t, err.Error(), "'cache'")
+	assert.Contains(t, err.Error(), "Mode")
+	_, registered := app.(*DoffApp).GetPluginManager().GetPlugin("cache")
+	assert.False(t, registered)
+
+	// Without configuration, the required pool size is missing
+	pm := NewPluginManager(nil, NewDIContainer())
+	assert.ErrorIs(t, pm.RegisterPlugin(newConfigurableTestPlugin()), ErrPluginConfigInvalid)
+}
+
+// loggerPlugin is a legacy plugin (no module) registering a "logger" service
+type loggerPlugin struct {
+	BasePlugin
+	name string
+}
+
+func (p *loggerPlugin) Name() string           { return p.name }
+func (p *loggerPlugin) Version() string        { return "1.0.0" }
+func (p *loggerPlugin) Hooks() []LifecycleHook { return nil }
+func (p *loggerPlugin) Register(container DIContainer) error {
+	return container.RegisterSingleton("logger", func(c DIContainer) (interface{}, error) {
+		return p.name, nil
+	})
+}
+
+func TestPluginManager_RejectsProviderNameCollisions(t *testing.T) {
+	container := NewDIContainer()
+	pm := NewPluginManager(nil, container)
+	require.NoError(t, pm.RegisterPlugin(&loggerPlugin{name: "zap"}))
+
+	err := pm.RegisterPlugin(&loggerPlugin{name: "logrus"})
+	require.ErrorIs(t, err, ErrProviderNameCollision)
+	assert.Contains(t, err.Error(), "service 'logger' of plugin 'logrus' (module 'logrus')")
+	assert.Contains(t, err.Error(), "registered by plugin 'zap' (module 'zap')")
+	_, registered := pm.GetPlugin("logrus")
+	assert.False(t, registered)
+	_, exists := pm.GetModuleGraph().GetModule("logrus")
+	assert.False(t, exists)
+
+	// Providers a module declares are checked before its plugin registers anything
+	module := NewModule("audit", "1.0.0").WithProviders(
+		NewValueProvider("auditLog", "audit"),
+		NewValueProvider("logger", "audit"),
+	)
+	err = pm.RegisterPlugin(newModuleTestPlugin(module))
+	require.ErrorIs(t, err, ErrProviderNameCollision)
+	assert.Contains(t, err.Error(), "plugin 'audit' (module 'audit')")
+	assert.False(t, container.Has("auditLog"))
+
+	// Once the owner is gone, the name is free again
+	require.NoError(t, pm.UnregisterPlugin("zap"))
+	require.NoError(t, pm.RegisterPlugin(&loggerPlugin{name: "logrus"}))
+	logger, err := container.Resolve("logger")
+	require.NoError(t, err)
+	assert.Equal(t, "logrus", logger)
+}
+
+func TestPluginManager_ValidateUsesNamingStrategy(t *testing.T) {
+	container := NewDIContainer()
+	container.(NamingStrategyContainer).SetNamingStrategy(SnakeCaseTypeName)
+	pm := NewPluginManager(nil, container)
+
+	module := NewModule("server", "1.0.0").WithProviders(
+		NewValueProvider("http_server_config", &httpServerConfig{Port: 9000}),
+		NewAsyncProvider("listener", func(c DIContainer, ctx context.Context) (interface{}, error) {
+			value, err := resolveHandlerParam(ctx, c, reflect.TypeOf(&httpServerConfig{}))
+			if err != nil {
+				return nil, err
+			}
+			return value.Interface().(*httpServerConfig).Port, nil
+		}, Singleton),
+	)
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+
+	assert.NoError(t, pm.Validate(context.Background()))
+}
+
+func TestPluginManager_ValidateResolvesInterfaces(t *testing.T) {
+	container := NewDIContainer()
+	pm := NewPluginManager(nil, container)
+
+	module := NewModule("greetings", "1.0.0").WithProviders(
+		NewValueProvider("englishGreeter", &TestService{Value: "world"}),
+		NewEagerSingletonProvider("welcome", func(c DIContainer) (interface{}, error) {
+			return ResolveInterface[greeter](c)
+		}),
+	)
+	require.NoError(t, pm.RegisterPlugin(newModuleTestPlugin(module)))
+	require.NoError(t, container.(InterfaceContainer).BindInterface(reflect.TypeOf((*greeter)(nil)).Elem(), "englishGreeter"))
+
+	assert.NoError(t, pm.Validate(context.Background()))
+	require.NoError(t, pm.InitializePlugins())
+}
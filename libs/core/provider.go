@@ -2,7 +2,9 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"time"
 )
@@ -23,19 +25,32 @@ type Provider interface {
 	IsAsync() bool
 }
 
+// EagerProvider is implemented by providers that can be instantiated at startup
+// instead of on first resolution
+type EagerProvider interface {
+	// IsEager indicates the singleton should be created during plugin initialization
+	IsEager() bool
+}
+
 // FactoryProvider wraps existing Factory functions (backward compatible)
 type FactoryProvider struct {
-	Name     string
-	Factory  Factory  // Existing func(DIContainer) (interface{}, error)
-	Lifetime Lifetime
+	Name           string
+	Factory        Factory  // Existing func(DIContainer) (interface{}, error)
+	Lifetime       Lifetime
+	EagerSingleton bool     // Instantiate at startup so misconfiguration fails fast
+	// DependsOn declares the services Factory resolves; it is metadata only, checked
+	// by Validate and InitializePlugins and listed by ContainerInspector.ListServices
+	DependsOn []string
 }
 
 func (p *FactoryProvider) GetName() string { return p.Name }
 func (p *FactoryProvider) GetLifetime() Lifetime { return p.Lifetime }
 func (p *FactoryProvider) IsAsync() bool { return false }
+func (p *FactoryProvider) IsEager() bool { return p.EagerSingleton && p.Lifetime == Singleton }
 func (p *FactoryProvider) Resolve(container DIContainer, ctx context.Context) (interface{}, error) {
 	return p.Factory(container)
 }
+func (p *FactoryProvider) Dependencies() []string { return p.DependsOn }
 
 // NewFactoryProvider creates a new FactoryProvider
 func NewFactoryProvider(name string, factory Factory, lifetime Lifetime) *FactoryProvider {
@@ -46,30 +61,109 @@ func NewFactoryProvider(name string, factory Factory, lifetime Lifetime) *Factor
 	}
 }
 
+// NewEagerSingletonProvider creates a singleton FactoryProvider that is instantiated at startup
+func NewEagerSingletonProvider(name string, factory Factory) *FactoryProvider {
+	return &FactoryProvider{
+		Name:           name,
+		Factory:        factory,
+		Lifetime:       Singleton,
+		EagerSingleton: true,
+	}
+}
+
+// DependencyProvider is implemented by providers declaring the services they resolve,
+// e.g. FactoryProvider.DependsOn
+type DependencyProvider interface {
+	Dependencies() []string
+}
+
+// providerDependencies returns the services a provider declares it depends on,
+// looking through the wrappers added at registration
+func providerDependencies(provider Provider) []string {
+	for provider != nil {
+		if declaring, ok := provider.(DependencyProvider); ok {
+			return declaring.Dependencies()
+		}
+		provider = wrappedProvider(provider)
+	}
+	return nil
+}
+
+// validateProvider runs the checks of a ValueProvider (see ValueProvider.Validate),
+// looking through the wrappers added at registration
+func validateProvider(provider Provider) error {
+	for provider != nil {
+		if value, ok := provider.(*ValueProvider); ok {
+			return value.Validate()
+		}
+		provider = wrappedProvider(provider)
+	}
+	return nil
+}
+
+// wrappedProvider returns the provider a registration wrapper wraps, or nil
+func wrappedProvider(provider Provider) Provider {
+	switch wrapper := provider.(type) {
+	case *singletonLifetimeWrapper:
+		return wrapper.Provider
+	case *transientLifetimeWrapper:
+		return wrapper.Provider
+	case *scopedLifetimeWrapper:
+		return wrapper.Provider
+	case *lazyProvider:
+		return wrapper.Provider
+	default:
+		return nil
+	}
+}
+
+// isEagerSingleton reports whether a provider asked to be instantiated at startup
+func isEagerSingleton(provider Provider) bool {
+	if provider.GetLifetime() != Singleton {
+		return false
+	}
+	eager, ok := provider.(EagerProvider)
+	return ok && eager.IsEager()
+}
+
 // ClassProvider creates instances via reflection (struct type)
+// Exported fields are populated from the container: a `di:"name"` tag resolves
+// the named service (required), `di:"-"` skips the field, and untagged fields
+// are filled by type when a matching service is registered.
+// If Constructor is set, it is called instead with its parameters resolved by type.
 type ClassProvider struct {
-	Name     string
-	Type     reflect.Type  // e.g., reflect.TypeOf((*UserService)(nil)).Elem()
-	Lifetime Lifetime
+	Name        string
+	Type        reflect.Type  // e.g., reflect.TypeOf((*UserService)(nil)).Elem()
+	Lifetime    Lifetime
+	Constructor interface{}   // Optional func(deps...) T or func(deps...) (T, error)
 }
 
 func (p *ClassProvider) GetName() string { return p.Name }
 func (p *ClassProvider) GetLifetime() Lifetime { return p.Lifetime }
 func (p *ClassProvider) IsAsync() bool { return false }
 func (p *ClassProvider) Resolve(container DIContainer, ctx context.Context) (interface{}, error) {
-	// Phase 2: Simple struct instantiation
-	// Phase 3: Add constructor injection via reflection
+	if p.Constructor != nil {
+		return p.construct(container, ctx)
+	}
 
 	// Check if we have a pointer type (most common case for services)
 	if p.Type.Kind() == reflect.Ptr {
 		// For pointer types, create a new instance each time
 		instance := reflect.New(p.Type.Elem())
+		if p.Type.Elem().Kind() == reflect.Struct {
+			if err := p.injectFields(container, ctx, instance.Elem()); err != nil {
+				return nil, err
+			}
+		}
 		return instance.Interface(), nil
 	}
 
 	// For struct types (non-pointer), create a new instance
 	if p.Type.Kind() == reflect.Struct {
 		instance := reflect.New(p.Type)
+		if err := p.injectFields(container, ctx, instance.Elem()); err != nil {
+			return nil, err
+		}
 		return instance.Interface(), nil
 	}
 
@@ -77,6 +171,137 @@ func (p *ClassProvider) Resolve(container DIContainer, ctx context.Context) (int
 	return nil, fmt.Errorf("cannot create instance of interface type %s, use a concrete type", p.Type)
 }
 
+// injectFields resolves the exported fields of a struct value from the container
+func (p *ClassProvider) injectFields(container DIContainer, ctx context.Context, value reflect.Value) error {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("di")
+		if tag == "-" {
+			continue
+		}
+
+		var dependency interface{}
+		var err error
+
+		if hasTag && tag != "" {
+			// Tagged fields are required dependencies
+			dependency, err = container.ResolveWithContext(tag, ctx)
+			if err != nil {
+				return fmt.Errorf("class provider '%s' failed to resolve field '%s' (service '%s'): %w",
+					p.Name, field.Name, tag, err)
+			}
+		} else {
+			// Untagged fields are only filled when a service is registered for their type
+			name, found := serviceNameForType(container, field.Type)
+			if !found {
+				continue
+			}
+			dependency, err = container.ResolveWithContext(name, ctx)
+			if err != nil {
+				return fmt.Errorf("class provider '%s' failed to resolve field '%s' (service '%s'): %w",
+					p.Name, field.Name, name, err)
+			}
+		}
+
+		if err := assignDependency(value.Field(i), dependency); err != nil {
+			return fmt.Errorf("class provider '%s' cannot inject field '%s': %w", p.Name, field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// construct calls the registered constructor with its parameters resolved by type
+func (p *ClassProvider) construct(container DIContainer, ctx context.Context) (interface{}, error) {
+	ctorValue := reflect.ValueOf(p.Constructor)
+	ctorType := ctorValue.Type()
+
+	if err := validateConstructor(ctorType); err != nil {
+		return nil, fmt.Errorf("class provider '%s': %w", p.Name, err)
+	}
+
+	args := make([]reflect.Value, ctorType.NumIn())
+	for i := 0; i < ctorType.NumIn(); i++ {
+		paramType := ctorType.In(i)
+		name, found := serviceNameForType(container, paramType)
+		if !found {
+			return nil, fmt.Errorf("class provider '%s' cannot resolve constructor parameter %d of type %s: no service registered as %s",
+				p.Name, i, paramType, describeServiceNames(serviceNamesForType(container, paramType)))
+		}
+
+		dependency, err := container.ResolveWithContext(name, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("class provider '%s' failed to resolve constructor parameter %d (service '%s'): %w",
+				p.Name, i, name, err)
+		}
+
+		arg := reflect.New(paramType).Elem()
+		if err := assignDependency(arg, dependency); err != nil {
+			return nil, fmt.Errorf("class provider '%s' cannot pass constructor parameter %d: %w", p.Name, i, err)
+		}
+		args[i] = arg
+	}
+
+	results := ctorValue.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return nil, results[1].Interface().(error)
+	}
+
+	return results[0].Interface(), nil
+}
+
+// validateConstructor checks the constructor is a func returning T or (T, error)
+func validateConstructor(ctorType reflect.Type) error {
+	if ctorType.Kind() != reflect.Func {
+		return fmt.Errorf("constructor must be a function, got %s", ctorType)
+	}
+
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	switch ctorType.NumOut() {
+	case 1:
+		return nil
+	case 2:
+		if ctorType.Out(1) == errorType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("constructor must return (T) or (T, error), got %s", ctorType)
+}
+
+// serviceNameForType finds the registered service name for a type
+// It tries the names of serviceNamesForType in order: the container's naming strategy
+// if set, otherwise the full type string (e.g. *core.UserService), then the short name (UserService)
+func serviceNameForType(container DIContainer, t reflect.Type) (string, bool) {
+	for _, name := range serviceNamesForType(container, t) {
+		if container.Has(name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// assignDependency sets a resolved dependency on a field or argument value
+func assignDependency(target reflect.Value, dependency interface{}) error {
+	if dependency == nil {
+		return nil
+	}
+
+	dependencyValue := reflect.ValueOf(dependency)
+	if !dependencyValue.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("resolved type %s is not assignable to %s", dependencyValue.Type(), target.Type())
+	}
+
+	target.Set(dependencyValue)
+	return nil
+}
+
 // NewClassProvider creates a new ClassProvider
 func NewClassProvider(name string, typ reflect.Type, lifetime Lifetime) *ClassProvider {
 	return &ClassProvider{
@@ -86,6 +311,22 @@ func NewClassProvider(name string, typ reflect.Type, lifetime Lifetime) *ClassPr
 	}
 }
 
+// NewClassProviderWithConstructor creates a ClassProvider that builds instances via a constructor
+// Constructor parameters are resolved from the container by type
+func NewClassProviderWithConstructor(name string, constructor interface{}, lifetime Lifetime) *ClassProvider {
+	var typ reflect.Type
+	if ctorType := reflect.TypeOf(constructor); ctorType != nil && ctorType.Kind() == reflect.Func && ctorType.NumOut() > 0 {
+		typ = ctorType.Out(0)
+	}
+
+	return &ClassProvider{
+		Name:        name,
+		Type:        typ,
+		Lifetime:    lifetime,
+		Constructor: constructor,
+	}
+}
+
 // NewClassProviderByType creates a ClassProvider from a type parameter
 func NewClassProviderByType[T any](name string, lifetime Lifetime) *ClassProvider {
 	var zero T
@@ -113,6 +354,29 @@ func (p *ValueProvider) Resolve(container DIContainer, ctx context.Context) (int
 	return p.Value, nil
 }
 
+// Validate rejects nil values, including typed nils such as a nil *Service,
+// which would otherwise only fail when the resolved value is used
+func (p *ValueProvider) Validate() error {
+	if isNilValue(p.Value) {
+		return fmt.Errorf("value provider '%s' has a nil value", p.Name)
+	}
+	return nil
+}
+
+// isNilValue reports whether value is nil or a nil pointer, map, slice, func, chan or interface
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
 // NewValueProvider creates a new ValueProvider
 func NewValueProvider(name string, value interface{}) *ValueProvider {
 	return &ValueProvider{
@@ -130,6 +394,37 @@ type AsyncProvider struct {
 	Factory  AsyncFactory
 	Lifetime Lifetime
 	Timeout  time.Duration  // Default 30s if not set
+	Retry    *RetryPolicy   // Optional; the factory runs once when nil
+}
+
+// RetryPolicy retries a failing async factory with exponential backoff; every
+// attempt and wait shares the provider's Timeout
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts, the first included
+	BaseDelay   time.Duration // Wait after the first failure
+	MaxDelay    time.Duration // Caps the wait between attempts; 0 means no cap
+	Multiplier  float64       // Growth of the wait per attempt; defaults to 2
+	Jitter      float64       // Fraction (0-1) of each wait that is randomized
+}
+
+// delay returns the wait before the attempt following the given failed one
+func (r *RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	delay := float64(r.BaseDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+	}
+	if r.MaxDelay > 0 && delay > float64(r.MaxDelay) {
+		delay = float64(r.MaxDelay)
+	}
+	if r.Jitter > 0 {
+		delay -= delay * min(r.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
 }
 
 func (p *AsyncProvider) GetName() string { return p.Name }
@@ -144,7 +439,37 @@ func (p *AsyncProvider) Resolve(container DIContainer, ctx context.Context) (int
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	return p.Factory(container, ctx)
+	if p.Retry == nil || p.Retry.MaxAttempts <= 1 {
+		return p.Factory(container, ctx)
+	}
+	return p.resolveWithRetry(container, ctx)
+}
+
+// resolveWithRetry runs the factory until it succeeds, the attempts are used up or
+// ctx is done; circular dependencies are not retried
+func (p *AsyncProvider) resolveWithRetry(container DIContainer, ctx context.Context) (interface{}, error) {
+	for attempt := 1; ; attempt++ {
+		instance, err := p.Factory(container, ctx)
+		if err == nil {
+			return instance, nil
+		}
+
+		var circular *CircularDependencyError
+		if errors.As(err, &circular) {
+			return nil, err
+		}
+		if attempt >= p.Retry.MaxAttempts {
+			return nil, fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(p.Retry.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gave up after %d attempts: %w (last error: %w)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
 }
 
 // NewAsyncProvider creates a new AsyncProvider with default timeout
@@ -165,4 +490,16 @@ func NewAsyncProviderWithTimeout(name string, factory AsyncFactory, lifetime Lif
 		Lifetime: lifetime,
 		Timeout:  timeout,
 	}
-}
\ No newline at end of file
+}
+
+// NewAsyncProviderWithRetry creates a new AsyncProvider retrying its factory per retry;
+// timeout bounds all attempts together
+func NewAsyncProviderWithRetry(name string, factory AsyncFactory, lifetime Lifetime, timeout time.Duration, retry RetryPolicy) *AsyncProvider {
+	return &AsyncProvider{
+		Name:     name,
+		Factory:  factory,
+		Lifetime: lifetime,
+		Timeout:  timeout,
+		Retry:    &retry,
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// checkProviderCollisions reports a provider the module declares under the name of a
+// service another plugin registered; every plugin registers into the root container,
+// where the second registration would fail with a bare "already registered"
+func (pm *PluginManager) checkProviderCollisions(plugin string, module *Module) error {
+	for _, provider := range module.Providers {
+		if err := pm.providerCollision(plugin, module.Name, provider.GetName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// providerCollision returns ErrProviderNameCollision, naming both owners, when another
+// plugin registered the service name
+func (pm *PluginManager) providerCollision(plugin, module, name string) error {
+	owner, ok := pm.serviceOwner(name)
+	if !ok || owner == plugin {
+		return nil
+	}
+	return fmt.Errorf("%w: service '%s' of plugin '%s' (module '%s') is already registered by plugin '%s' (module '%s')",
+		ErrProviderNameCollision, name, plugin, module, owner, pm.pluginModuleName(owner))
+}
+
+// serviceOwner returns the plugin whose Register added the service name
+func (pm *PluginManager) serviceOwner(name string) (string, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	owners := make([]string, 0, len(pm.services))
+	for owner, services := range pm.services {
+		if slices.Contains(services, name) {
+			owners = append(owners, owner)
+		}
+	}
+	if len(owners) == 0 {
+		return "", false
+	}
+	sort.Strings(owners)
+	return owners[0], true
+}
+
+// pluginModuleName returns the name of a registered plugin's module; plugins without
+// one are wrapped in a DefaultModule named like them
+func (pm *PluginManager) pluginModuleName(name string) string {
+	plugin, _ := pm.GetPlugin(name)
+	if provider, ok := plugin.(ModuleProvider); ok {
+		if module := provider.Module(); module != nil {
+			return module.Name
+		}
+	}
+	return name
+}
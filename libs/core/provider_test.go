@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -60,6 +61,94 @@ func TestClassProvider(t *testing.T) {
 	}
 }
 
+// TestInjectedService declares dependencies resolved by ClassProvider
+type TestInjectedService struct {
+	Service  *TestService
+	Named    *TestService `di:"namedService"`
+	Skipped  *TestService `di:"-"`
+	Label    string
+	internal *TestService
+}
+
+func TestClassProviderFieldInjection(t *testing.T) {
+	container := NewDIContainer()
+
+	shared := &TestService{Value: "by-type"}
+	named := &TestService{Value: "by-tag"}
+	container.RegisterProvider(NewValueProvider("TestService", shared))
+	container.RegisterProvider(NewValueProvider("namedService", named))
+	container.RegisterProvider(NewClassProviderByType[*TestInjectedService]("injected", Singleton))
+
+	service, err := container.Resolve("injected")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	injected, ok := service.(*TestInjectedService)
+	if !ok {
+		t.Fatalf("Service is not of type *TestInjectedService, got %T", service)
+	}
+
+	if injected.Service != shared {
+		t.Error("Untagged field was not injected by type")
+	}
+	if injected.Named != named {
+		t.Error("Tagged field was not injected by name")
+	}
+	if injected.Skipped != nil || injected.internal != nil {
+		t.Error("Skipped and unexported fields should not be injected")
+	}
+
+	// Singleton lifetime is respected for the constructed instance
+	again, _ := container.Resolve("injected")
+	if again != service {
+		t.Error("Singleton class provider returned different instances")
+	}
+}
+
+func TestClassProviderMissingDependency(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewClassProviderByType[*TestInjectedService]("injected", Transient))
+
+	_, err := container.Resolve("injected")
+	if err == nil {
+		t.Fatal("Expected error for missing tagged dependency")
+	}
+
+	if !strings.Contains(err.Error(), "field 'Named'") || !strings.Contains(err.Error(), "namedService") {
+		t.Errorf("Error should name the field and service, got: %v", err)
+	}
+}
+
+func TestClassProviderConstructor(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewValueProvider("TestService", &TestService{Value: "dep"}))
+
+	provider := NewClassProviderWithConstructor("service2", func(dep *TestService) (*TestService2, error) {
+		return &TestService2{Service: dep}, nil
+	}, Transient)
+	container.RegisterProvider(provider)
+
+	service, err := container.Resolve("service2")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	service2, ok := service.(*TestService2)
+	if !ok || service2.Service == nil || service2.Service.Value != "dep" {
+		t.Errorf("Constructor dependency was not injected: %#v", service)
+	}
+
+	failing := NewClassProviderWithConstructor("failing", func(dep *TestService2) *TestService {
+		return &TestService{}
+	}, Transient)
+	container.RegisterProvider(failing)
+
+	if _, err := container.Resolve("failing"); err == nil {
+		t.Error("Expected error for unresolvable constructor parameter")
+	}
+}
+
 func TestValueProvider(t *testing.T) {
 	container := NewDIContainer()
 
@@ -94,6 +183,35 @@ func TestValueProvider(t *testing.T) {
 	}
 }
 
+func TestValueProviderRejectsNil(t *testing.T) {
+	container := NewDIContainer()
+
+	var typedNil *TestService
+	for name, value := range map[string]interface{}{"untyped": nil, "typed": typedNil} {
+		err := container.RegisterProvider(NewValueProvider(name, value))
+		if err == nil || !strings.Contains(err.Error(), "nil value") {
+			t.Errorf("Expected nil value error for %s, got %v", name, err)
+		}
+		if container.Has(name) {
+			t.Errorf("Service '%s' should not be registered", name)
+		}
+	}
+}
+
+func TestValueProviderResolveAsTypeMismatch(t *testing.T) {
+	container := NewDIContainer()
+	container.RegisterProvider(NewValueProvider("testService", &TestService{Value: "value"}))
+
+	var target *TestService2
+	err := container.ResolveAs("testService", &target)
+	if err == nil {
+		t.Fatal("Expected type mismatch error")
+	}
+	if !strings.Contains(err.Error(), "*core.TestService") || !strings.Contains(err.Error(), "*core.TestService2") {
+		t.Errorf("Expected error to name both types, got %v", err)
+	}
+}
+
 func TestAsyncProvider(t *testing.T) {
 	container := NewDIContainer()
 
@@ -162,6 +280,78 @@ func TestAsyncProviderTimeout(t *testing.T) {
 	}
 }
 
+func TestAsyncProviderRetry(t *testing.T) {
+	errNotReady := errors.New("database not ready")
+	retry := RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, Jitter: 0.5}
+
+	// Fails twice, then succeeds
+	attempts := 0
+	provider := NewAsyncProviderWithRetry("database", func(c DIContainer, ctx context.Context) (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errNotReady
+		}
+		return &TestService{Value: "connected"}, nil
+	}, Singleton, time.Second, retry)
+
+	service, err := provider.Resolve(NewDIContainer(), context.Background())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if service.(*TestService).Value != "connected" || attempts != 3 {
+		t.Errorf("Expected success on attempt 3, got %d attempts", attempts)
+	}
+
+	// Always fails: the attempts are used up
+	attempts = 0
+	provider.Factory = func(c DIContainer, ctx context.Context) (interface{}, error) {
+		attempts++
+		return nil, errNotReady
+	}
+	_, err = provider.Resolve(NewDIContainer(), context.Background())
+	if !errors.Is(err, errNotReady) || attempts != 3 {
+		t.Errorf("Expected errNotReady after 3 attempts, got %d attempts: %v", attempts, err)
+	}
+}
+
+func TestAsyncProviderRetryBoundedByTimeout(t *testing.T) {
+	errNotReady := errors.New("database not ready")
+	attempts := 0
+	provider := NewAsyncProviderWithRetry("database", func(c DIContainer, ctx context.Context) (interface{}, error) {
+		attempts++
+		return nil, errNotReady
+	}, Singleton, 50*time.Millisecond, RetryPolicy{MaxAttempts: 10, BaseDelay: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err := provider.Resolve(NewDIContainer(), context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errNotReady) {
+		t.Errorf("Expected DeadlineExceeded wrapping the last error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout to bound the retries, took %v", elapsed)
+	}
+	if attempts >= 10 {
+		t.Errorf("Expected fewer than 10 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	retry := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+	for i, want := range expected {
+		if got := retry.delay(i + 1); got != want {
+			t.Errorf("Attempt %d: expected delay %v, got %v", i+1, want, got)
+		}
+	}
+
+	retry.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got := retry.delay(1); got < 5*time.Millisecond || got > 10*time.Millisecond {
+			t.Errorf("Expected jittered delay within [5ms, 10ms], got %v", got)
+		}
+	}
+}
+
 func TestProviderLifetimeSingleton(t *testing.T) {
 	container := NewDIContainer()
 
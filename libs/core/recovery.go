@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// panicReportedKey marks a request whose panic already went through the OnError hooks
+const panicReportedKey = "doffy.panicReported"
+
+// ErrPanic is wrapped by the error OnError hooks receive for a recovered panic
+// OnResponse does not run for such requests, so hooks that pair OnRequest with
+// OnResponse should finish their work in OnError when errors.Is(err, ErrPanic)
+var ErrPanic = errors.New("panic")
+
+// panicError converts a recovered panic value into an error, wrapping panicked errors
+func panicError(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+	return fmt.Errorf("%w: %v", ErrPanic, recovered)
+}
+
+// recoveryMiddleware turns panics anywhere in the middleware chain into a 500 JSON
+// error envelope instead of crashing the server: OnError hooks run with the wrapped panic,
+// the panic is logged with its stack, and the stack is included in the response in
+// debug mode only
+// It must be the first middleware so it also covers the other framework middleware
+func (d *DoffApp) recoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// Deliberate abort of the response; net/http handles it silently
+				panic(recovered)
+			}
+
+			err := panicError(recovered)
+			stack := string(debug.Stack())
+
+			if !c.GetBool(panicReportedKey) && d.pluginManager != nil {
+				d.pluginManager.GetLifecycleManager().executeOnErrorHooks(c, err)
+			}
+
+			d.logger.Infor(&LoggerItem{
+				Level:    LevelError,
+				Event:    "PanicRecovered",
+				Messages: fmt.Sprintf("Recovered from panic in %s %s", c.Request.Method, c.Request.URL.Path),
+				Error:    err,
+				Data:     map[string]string{"stack": stack},
+			})
+
+			if c.Writer.Written() {
+				// Too late for a JSON body; just stop the chain
+				c.Abort()
+				return
+			}
+
+			apiErr := ErrInternal.WithCause(err)
+			if gin.IsDebugging() {
+				apiErr = apiErr.WithDetails(gin.H{"panic": err.Error(), "stack": stack})
+			}
+			respondWithError(c, err, apiErr)
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery_PanicInHandler(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	errBoom := errors.New("boom")
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+	app.GetEngine().GET("/boom", func(c *gin.Context) {
+		panic(errBoom)
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var body struct{ Error APIError }
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "internal_error", body.Error.Code)
+	assert.Equal(t, "Internal Server Error", body.Error.Message)
+	// Test mode is not debug mode, so no stack trace leaks
+	assert.Nil(t, body.Error.Details)
+
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], errBoom)
+	assert.ErrorIs(t, hookErrs[0], ErrPanic)
+}
+
+func TestRecovery_PanicInMiddlewareIncludesStackInDebugMode(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(gin.TestMode)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(&LifecycleHookFunc{
+		OnRequestFunc: func(c *gin.Context) { panic("hook exploded") },
+		OnErrorFunc:   func(c *gin.Context, err error) { hookErrs = append(hookErrs, err) },
+	})
+	app.GetEngine().GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var body struct {
+		Error struct {
+			Details map[string]string
+		}
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "panic: hook exploded", body.Error.Details["panic"])
+	assert.Contains(t, body.Error.Details["stack"], "recovery_test.go")
+	require.Len(t, hookErrs, 1)
+}
+
+func TestRecovery_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:            "recovery-test",
+		Mode:            gin.TestMode,
+		DisableRecovery: true,
+	}).(*DoffApp)
+
+	app.GetEngine().GET("/panic", func(c *gin.Context) {
+		panic("handler exploded")
+	})
+
+	assert.Panics(t, func() {
+		app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	})
+}
@@ -7,6 +7,13 @@ import (
 )
 
 // RequestContainer is a per-request scoped DI container
+// Lifetimes seen through a request container:
+//   - Singleton services of the module are cached on the module container and
+//     shared by every request
+//   - Scoped services of the module are built once per request
+//   - Singleton and Scoped services registered on the request container itself
+//     are built once per request and discarded with it
+//   - Transient services are built on every resolve
 type RequestContainer struct {
 	*diContainer  // Embed base container
 
@@ -17,12 +24,16 @@ type RequestContainer struct {
 }
 
 // NewRequestContainer creates a request-scoped container
+// Scoped services resolved through it are built once per request
 func NewRequestContainer(moduleContainer DIContainer) *RequestContainer {
+	base := &diContainer{
+		services: make(map[string]*ServiceDefinition),
+		parent:   moduleContainer,
+	}
+	base.scope = newResolutionScope(base)
+
 	return &RequestContainer{
-		diContainer: &diContainer{
-			services: make(map[string]*ServiceDefinition),
-			parent:   moduleContainer,
-		},
+		diContainer:  base,
 		module:       moduleContainer,
 		requestData:  make(map[string]interface{}),
 		replyHelpers: make(map[string]interface{}),
@@ -71,6 +82,8 @@ func (rc *RequestContainer) Resolve(name string) (interface{}, error) {
 
 // ResolveWithContext overrides parent resolution to check request data first
 func (rc *RequestContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
+	ctx = rc.enterScope(ctx)
+
 	// Check request-scoped data first
 	if value, exists := rc.GetRequestData(name); exists {
 		return value, nil
@@ -81,27 +94,27 @@ func (rc *RequestContainer) ResolveWithContext(name string, ctx context.Context)
 		return helper, nil
 	}
 
-	// Fall back to parent resolution
-	rc.mu.RLock()
-	service, exists := rc.services[name]
-	rc.mu.RUnlock()
-
-	if exists {
+	// Services registered on the request container itself
+	if service, exists := rc.lookupService(name); exists {
 		provider := service.Provider
 
 		switch provider.GetLifetime() {
-		case Singleton:
-			// For request containers, we don't cache singletons
-			// Each request should get a fresh instance if requested
-			return provider.Resolve(rc, ctx)
+		case Singleton, Scoped:
+			// Services registered on the request container live for the request:
+			// build once, then reuse for every resolve within the same request
+			if instance := rc.cachedInstance(service); instance != nil {
+				return instance, nil
+			}
 
-		case Transient:
-			return provider.Resolve(rc, ctx)
+			instance, err := resolveProvider(rc, name, provider, ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create request service '%s': %w", name, err)
+			}
 
-		case Scoped:
-			// For request containers, scoped means "per request"
-			// So we always create a new instance
-			return provider.Resolve(rc, ctx)
+			return rc.storeSingleton(name, service, instance), nil
+
+		case Transient:
+			return resolveProvider(rc, name, provider, ctx)
 
 		default:
 			return nil, fmt.Errorf("unknown lifetime for service '%s'", name)
@@ -116,7 +129,41 @@ func (rc *RequestContainer) ResolveWithContext(name string, ctx context.Context)
 		return rc.module.Resolve(name)
 	}
 
-	return nil, fmt.Errorf("service '%s' is not registered", name)
+	return nil, &ServiceNotRegisteredError{Name: name}
+}
+
+// ResolveOptional resolves a service, request data included, reporting whether it is registered
+func (rc *RequestContainer) ResolveOptional(name string) (interface{}, bool, error) {
+	return rc.ResolveOptionalWithContext(name, context.Background())
+}
+
+// ResolveOptionalWithContext is ResolveOptional with a context
+func (rc *RequestContainer) ResolveOptionalWithContext(name string, ctx context.Context) (interface{}, bool, error) {
+	return resolveOptional(rc, name, ctx)
+}
+
+// ResolveAs resolves a service, request data included, into the target pointer
+func (rc *RequestContainer) ResolveAs(name string, target interface{}) error {
+	return rc.ResolveAsWithContext(name, context.Background(), target)
+}
+
+// ResolveAsWithContext resolves a service with context, request data included, into the target pointer
+func (rc *RequestContainer) ResolveAsWithContext(name string, ctx context.Context, target interface{}) error {
+	instance, err := rc.ResolveWithContext(name, ctx)
+	if err != nil {
+		return err
+	}
+	return assignResolved(name, instance, target)
+}
+
+// ResolveGroup resolves every member of the group, building Scoped members once per request
+func (rc *RequestContainer) ResolveGroup(group string) ([]interface{}, error) {
+	return rc.ResolveGroupWithContext(group, context.Background())
+}
+
+// ResolveGroupWithContext resolves the module's group members, then the request's own
+func (rc *RequestContainer) ResolveGroupWithContext(group string, ctx context.Context) ([]interface{}, error) {
+	return resolveGroup(rc, rc.diContainer, group, ctx)
 }
 
 // Clear clears all request-scoped data (useful for cleanup)
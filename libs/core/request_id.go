@@ -0,0 +1,12 @@
+package core
+
+import "github.com/gin-gonic/gin"
+
+// RequestIDKey is the key under which the request ID is stored in the gin context
+// and the request container (see the requestid plugin)
+const RequestIDKey = "requestID"
+
+// GetRequestID returns the ID assigned to the request, or "" when none was assigned
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
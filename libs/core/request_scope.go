@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestContainerKey is the default gin context key of request containers
+const RequestContainerKey = "requestContainer"
+
+// RequestContainerOptions enables the built-in middleware giving every request a
+// RequestContainer, created from the module scope of the matched route and
+// initialized with the app's request and reply decorators
+type RequestContainerOptions struct {
+	// Key is the gin context key the container is stored under; defaults to RequestContainerKey
+	Key string
+	// ModuleScope returns the container each request container is created from
+	// Defaults to the module container of the route's module (RouteInfo.Module),
+	// or the root container for routes registered outside a module
+	ModuleScope func(c *gin.Context) DIContainer
+}
+
+// ErrRequestContainerMissing is reported when a handler needs a request container
+// and RequireRequestContainer is set, but no middleware created one
+var ErrRequestContainerMissing = newError("request container middleware not installed")
+
+// MissingRequestContainer decides how EnhancedRouter handlers resolve their
+// parameters when no request container is in context
+type MissingRequestContainer int
+
+const (
+	// FallbackToContainer resolves from the router's container, bypassing module
+	// encapsulation and request decorators
+	FallbackToContainer MissingRequestContainer = iota
+	// CreateRequestContainer creates a request container for the request on the fly,
+	// as the built-in middleware would, and stores it for later handlers
+	CreateRequestContainer
+	// RequireRequestContainer answers 500 with ErrRequestContainerMissing
+	RequireRequestContainer
+)
+
+// GetRequestContainer returns the request's container, stored by the built-in
+// middleware (see AppOptions.RequestContainers) or by SetRequestContainer
+func GetRequestContainer(c *gin.Context) (*RequestContainer, bool) {
+	value, exists := c.Get(requestContainerKey(c))
+	if !exists {
+		return nil, false
+	}
+	rc, ok := value.(*RequestContainer)
+	return rc, ok
+}
+
+// SetRequestContainer stores rc as the request's container, under the app's key, and
+// in the request's context (see RequestContainerFromContext)
+func SetRequestContainer(c *gin.Context, rc *RequestContainer) {
+	c.Set(requestContainerKey(c), rc)
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestContainerContextKey{}, rc))
+	}
+}
+
+// requestContainerContextKey is the context.Context key of request containers
+type requestContainerContextKey struct{}
+
+// RequestContainerFromContext returns the request container carried by ctx, for
+// service code handed the request's context.Context (or one derived from it) rather
+// than the gin context; a *gin.Context is accepted too
+func RequestContainerFromContext(ctx context.Context) (*RequestContainer, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		return GetRequestContainer(c)
+	}
+	rc, ok := ctx.Value(requestContainerContextKey{}).(*RequestContainer)
+	return rc, ok
+}
+
+// requestContainerKey returns the key configured on the request's app
+func requestContainerKey(c *gin.Context) string {
+	if app, exists := c.Get("app"); exists {
+		if doffApp, ok := app.(*DoffApp); ok && doffApp.requestContainers != nil && doffApp.requestContainers.Key != "" {
+			return doffApp.requestContainers.Key
+		}
+	}
+	return RequestContainerKey
+}
+
+// GetModuleContainer returns the container of a registered module, creating it on
+// first use; it is shared by every request container of the module, so its
+// singletons and decorators live for the app
+func (d *DoffApp) GetModuleContainer(name string) (*ModuleContainer, bool) {
+	module, exists := d.pluginManager.GetModuleGraph().GetModule(name)
+	if !exists {
+		return nil, false
+	}
+
+	d.moduleMu.Lock()
+	defer d.moduleMu.Unlock()
+	mc, exists := d.moduleContainers[name]
+	if !exists {
+		mc = NewModuleContainer(module, d.container)
+		d.moduleContainers[name] = mc
+	}
+	return mc, true
+}
+
+// disposeModuleContainers disposes the module containers, closing their cached
+// Disposables, in reverse registration order of their plugins so that importers
+// close before the modules they import; all errors are collected
+func (d *DoffApp) disposeModuleContainers() error {
+	d.moduleMu.Lock()
+	containers := d.moduleContainers
+	d.moduleContainers = make(map[string]*ModuleContainer)
+	d.moduleMu.Unlock()
+
+	var errs []error
+	dispose := func(name string) {
+		mc, exists := containers[name]
+		if !exists {
+			return
+		}
+		delete(containers, name)
+		if err := mc.Dispose(); err != nil {
+			errs = append(errs, fmt.Errorf("module '%s': %w", name, err))
+		}
+	}
+	for _, plugin := range slices.Backward(d.pluginManager.orderedPlugins()) {
+		dispose(d.pluginManager.pluginModuleName(plugin.Name()))
+	}
+	// Modules whose plugin was unregistered
+	for _, name := range slices.Sorted(maps.Keys(containers)) {
+		dispose(name)
+	}
+	return errors.Join(errs...)
+}
+
+// routeScope returns the module container of the matched route, or the root container
+func (d *DoffApp) routeScope(c *gin.Context) DIContainer {
+	if module := d.pluginManager.routeModule(c.Request.Method, c.FullPath()); module != "" {
+		if mc, exists := d.GetModuleContainer(module); exists {
+			return mc
+		}
+	}
+	return d.container
+}
+
+// requestContainerMiddleware creates each request's container before the lifecycle
+// hooks run, so hooks, route middleware and handlers all see it, and disposes it
+// once the request is done, closing its Scoped and request singleton Disposables
+func (d *DoffApp) requestContainerMiddleware() gin.HandlerFunc {
+	scope := d.requestContainers.ModuleScope
+	if scope == nil {
+		scope = d.routeScope
+	}
+
+	return func(c *gin.Context) {
+		rc := d.newRequestContainer(c, scope(c))
+		defer d.disposeRequestContainer(c, rc)
+		c.Next()
+	}
+}
+
+// createdRequestContainerKey stores the request container requestContainerFor
+// created on demand, see disposeCreatedRequestContainer
+const createdRequestContainerKey = "doffy.createdRequestContainer"
+
+// disposeCreatedRequestContainer disposes the request container created on demand
+// during the request (see CreateRequestContainer), if any
+func (d *DoffApp) disposeCreatedRequestContainer(c *gin.Context) {
+	if value, exists := c.Get(createdRequestContainerKey); exists {
+		if rc, ok := value.(*RequestContainer); ok {
+			d.disposeRequestContainer(c, rc)
+		}
+	}
+}
+
+// disposeRequestContainer disposes a request's container, logging close errors as
+// the response is already written
+func (d *DoffApp) disposeRequestContainer(c *gin.Context, rc *RequestContainer) {
+	if err := rc.Dispose(); err != nil && d.logger != nil {
+		d.logger.Infor(&LoggerItem{
+			Level:    LevelError,
+			Event:    "RequestContainerDisposeError",
+			Messages: fmt.Sprintf("%s %s: failed to dispose the request container", c.Request.Method, c.Request.URL.Path),
+			Error:    err,
+		})
+	}
+}
+
+// newRequestContainer creates the request's container from scope, initialized with
+// the app's request and reply decorators, and stores it in the context
+func (d *DoffApp) newRequestContainer(c *gin.Context, scope DIContainer) *RequestContainer {
+	rc := NewRequestContainer(scope)
+	d.decoratorManager.InitializeRequestContainerWithContext(c, rc)
+	d.decoratorManager.InitializeReplyHelpers(rc)
+	SetRequestContainer(c, rc)
+	return rc
+}
+
+// requestContainerFor returns the request's container, or handles its absence as
+// policy says; container is the router's own container
+func requestContainerFor(c *gin.Context, policy MissingRequestContainer, container DIContainer) (DIContainer, error) {
+	if rc, ok := GetRequestContainer(c); ok {
+		return rc, nil
+	}
+
+	switch policy {
+	case CreateRequestContainer:
+		// Created from the route's module scope, as the built-in middleware would, and
+		// disposed by the app once the request is done
+		if app, exists := c.Get("app"); exists {
+			if doffApp, ok := app.(*DoffApp); ok {
+				rc := doffApp.newRequestContainer(c, doffApp.routeScope(c))
+				c.Set(createdRequestContainerKey, rc)
+				return rc, nil
+			}
+		}
+		// Outside an app nothing sees the request end, so nothing disposes it
+		rc := NewRequestContainer(container)
+		SetRequestContainer(c, rc)
+		return rc, nil
+	case RequireRequestContainer:
+		return nil, fmt.Errorf("%w: no container under '%s' for %s %s", ErrRequestContainerMissing, requestContainerKey(c), c.Request.Method, c.FullPath())
+	default:
+		return container, nil
+	}
+}
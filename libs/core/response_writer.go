@@ -0,0 +1,114 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseInfo describes the response written for a request
+// It is passed as the response argument of OnResponse hooks
+type ResponseInfo struct {
+	StatusCode  int
+	Size        int
+	ContentType string
+	// Body is the captured JSON response body; nil unless AppOptions.ResponseBodyLimit
+	// is set, and never captured for non-JSON or encoded (compressed) responses
+	Body []byte
+	// Truncated reports that the body exceeded the limit and Body holds only its start
+	Truncated bool
+}
+
+// ErrResponseBodyNotCaptured is returned by ResponseInfo.DecodeJSON when no complete body was captured
+var ErrResponseBodyNotCaptured = errors.New("response body not captured")
+
+// DecodeJSON unmarshals the captured body into target
+func (r *ResponseInfo) DecodeJSON(target interface{}) error {
+	if r.Body == nil || r.Truncated {
+		return ErrResponseBodyNotCaptured
+	}
+	return json.Unmarshal(r.Body, target)
+}
+
+// responseWriter wraps gin's ResponseWriter to observe what handlers write
+type responseWriter struct {
+	gin.ResponseWriter
+
+	bodyLimit int // Maximum bytes of JSON body to capture; 0 disables capture
+	body      *bytes.Buffer
+	decided   bool // Whether the capture decision was made on the first write
+	truncated bool
+}
+
+// newResponseWriter wraps the given writer, capturing up to bodyLimit bytes of JSON bodies
+func newResponseWriter(writer gin.ResponseWriter, bodyLimit int) *responseWriter {
+	return &responseWriter{
+		ResponseWriter: writer,
+		bodyLimit:      bodyLimit,
+	}
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capture records written bytes up to the limit when the response is JSON
+func (w *responseWriter) capture(data []byte) {
+	if !w.decided {
+		// Headers are final by the first write, so the content type is known
+		w.decided = true
+		// Encoded (e.g. gzipped) bodies are not readable JSON
+		header := w.Header()
+		if w.bodyLimit > 0 && isJSONContentType(header.Get("Content-Type")) && header.Get("Content-Encoding") == "" {
+			w.body = &bytes.Buffer{}
+		}
+	}
+	if w.body == nil || w.truncated {
+		return
+	}
+
+	if remaining := w.bodyLimit - w.body.Len(); len(data) > remaining {
+		w.body.Write(data[:remaining])
+		w.truncated = true
+		return
+	}
+	w.body.Write(data)
+}
+
+// isJSONContentType reports whether the content type is application/json or a +json type
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Info returns a snapshot of the written response
+func (w *responseWriter) Info() *ResponseInfo {
+	size := w.Size()
+	if size < 0 {
+		size = 0
+	}
+
+	info := &ResponseInfo{
+		StatusCode:  w.Status(),
+		Size:        size,
+		ContentType: w.Header().Get("Content-Type"),
+		Truncated:   w.truncated,
+	}
+	if w.body != nil {
+		info.Body = w.body.Bytes()
+	}
+	return info
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrRouteNotRegistered is returned when disabling a route the app does not have
+var ErrRouteNotRegistered = newError("route not registered")
+
+// routeToggles is the set of disabled routes, keyed "METHOD:path" by the route's
+// full path pattern (e.g. "GET:/users/:id"); MethodAny disables every method
+type routeToggles struct {
+	mu       sync.RWMutex
+	disabled map[string]bool
+	err      *APIError // Answered to requests to a disabled route
+}
+
+// newRouteToggles creates the set answering disabled routes with status:
+// http.StatusServiceUnavailable answers 503, anything else 404
+func newRouteToggles(status int) *routeToggles {
+	err := ErrNotFound
+	if status == http.StatusServiceUnavailable {
+		err = ErrServiceUnavailable.WithMessage("route is disabled")
+	}
+	return &routeToggles{disabled: make(map[string]bool), err: err}
+}
+
+// isDisabled reports whether the route matched by method and path is disabled
+func (t *routeToggles) isDisabled(method, path string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.disabled) == 0 {
+		return false
+	}
+	return t.disabled[method+":"+path] || t.disabled[MethodAny+":"+path]
+}
+
+func (t *routeToggles) set(key string, disabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if disabled {
+		t.disabled[key] = true
+	} else {
+		delete(t.disabled, key)
+	}
+}
+
+// keys returns the disabled routes, sorted
+func (t *routeToggles) keys() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	keys := make([]string, 0, len(t.disabled))
+	for key := range t.disabled {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// middleware aborts requests to disabled routes before any hook or handler runs
+func (t *routeToggles) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path := c.FullPath(); path != "" && t.isDisabled(c.Request.Method, path) {
+			AbortWithError(c, t.err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// parseRouteKey reads a disabled route entry, "METHOD /path" or "METHOD:/path"
+func parseRouteKey(entry string) (string, string, error) {
+	entry = strings.TrimSpace(entry)
+	index := strings.IndexAny(entry, " :")
+	if index <= 0 {
+		return "", "", fmt.Errorf("invalid disabled route '%s': expected \"METHOD /path\"", entry)
+	}
+	method, path := strings.ToUpper(entry[:index]), strings.TrimSpace(entry[index+1:])
+	if !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("invalid disabled route '%s': expected \"METHOD /path\"", entry)
+	}
+	return method, path, nil
+}
+
+// DisableRoute makes the route registered for method and its full path pattern,
+// e.g. DisableRoute("GET", "/users/:id"), answer AppOptions.DisabledRouteStatus
+// (404 by default) until EnableRoute is called; MethodAny disables every method
+// Other routes keep working, and the route's hooks and handler do not run
+// It can be called at any time, e.g. from an admin endpoint
+func (d *DoffApp) DisableRoute(method, path string) error {
+	method = strings.ToUpper(method)
+	if !d.routeRegistered(method, path) {
+		return fmt.Errorf("cannot disable %s %s: %w", method, path, ErrRouteNotRegistered)
+	}
+	d.routeToggles.set(method+":"+path, true)
+	return nil
+}
+
+// EnableRoute serves a route disabled by DisableRoute or AppOptions.DisabledRoutes again
+func (d *DoffApp) EnableRoute(method, path string) {
+	d.routeToggles.set(strings.ToUpper(method)+":"+path, false)
+}
+
+// DisabledRoutes returns the disabled routes as "METHOD:path" keys, sorted
+func (d *DoffApp) DisabledRoutes() []string {
+	return d.routeToggles.keys()
+}
+
+// routeRegistered reports whether the engine has a route for method and path
+func (d *DoffApp) routeRegistered(method, path string) bool {
+	for _, route := range d.server.Routes() {
+		if route.Path == path && (method == MethodAny || route.Method == method) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDisabledRoutes reports the disabled routes that match no registered route,
+// e.g. a mistyped AppOptions.DisabledRoutes entry
+func (d *DoffApp) validateDisabledRoutes() error {
+	var errs []error
+	for _, key := range d.routeToggles.keys() {
+		method, path, _ := strings.Cut(key, ":")
+		if !d.routeRegistered(method, path) {
+			errs = append(errs, fmt.Errorf("disabled route %s %s: %w", method, path, ErrRouteNotRegistered))
+		}
+	}
+	return errors.Join(errs...)
+}
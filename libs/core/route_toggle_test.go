@@ -0,0 +1,92 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouteToggleTestApp(t *testing.T, options *AppOptions) *DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	options.Name = "route-toggle-test"
+	options.Mode = gin.TestMode
+	app := CreateDoffApp(options).(*DoffApp)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	handler := func(c *gin.Context, controller *routeTestController) {
+		c.String(http.StatusOK, c.FullPath())
+	}
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/users/:id"}, handler)
+	router.POST(RouteConfig{Path: "/users/:id"}, handler)
+	router.GET(RouteConfig{Path: "/orders"}, handler)
+	return app
+}
+
+func serveRouteToggle(app *DoffApp, method, path string) int {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder.Code
+}
+
+func TestDoffApp_DisableAndEnableRoute(t *testing.T) {
+	app := newRouteToggleTestApp(t, &AppOptions{})
+
+	require.NoError(t, app.DisableRoute("get", "/users/:id"))
+	assert.Equal(t, []string{"GET:/users/:id"}, app.DisabledRoutes())
+
+	assert.Equal(t, http.StatusNotFound, serveRouteToggle(app, http.MethodGet, "/users/42"))
+	// Other methods of the path and other routes keep working
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodPost, "/users/42"))
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodGet, "/orders"))
+
+	app.EnableRoute(http.MethodGet, "/users/:id")
+	assert.Empty(t, app.DisabledRoutes())
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodGet, "/users/42"))
+
+	// MethodAny disables every method of the path
+	require.NoError(t, app.DisableRoute(MethodAny, "/users/:id"))
+	assert.Equal(t, http.StatusNotFound, serveRouteToggle(app, http.MethodGet, "/users/42"))
+	assert.Equal(t, http.StatusNotFound, serveRouteToggle(app, http.MethodPost, "/users/42"))
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodGet, "/orders"))
+}
+
+func TestDoffApp_DisableRouteRejectsUnknownRoutes(t *testing.T) {
+	app := newRouteToggleTestApp(t, &AppOptions{})
+
+	err := app.DisableRoute(http.MethodDelete, "/users/:id")
+	assert.ErrorIs(t, err, ErrRouteNotRegistered)
+	// The concrete path is not a route key
+	assert.ErrorIs(t, app.DisableRoute(http.MethodGet, "/users/42"), ErrRouteNotRegistered)
+	assert.Empty(t, app.DisabledRoutes())
+}
+
+func TestDoffApp_DisabledRoutesFromOptions(t *testing.T) {
+	app := newRouteToggleTestApp(t, &AppOptions{
+		DisabledRoutes:      []string{"GET /orders"},
+		DisabledRouteStatus: http.StatusServiceUnavailable,
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveRouteToggle(app, http.MethodGet, "/orders"))
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodGet, "/users/42"))
+	assert.NoError(t, app.Validate())
+
+	app.EnableRoute(http.MethodGet, "/orders")
+	assert.Equal(t, http.StatusOK, serveRouteToggle(app, http.MethodGet, "/orders"))
+}
+
+func TestDoffApp_ValidateReportsUnknownDisabledRoutes(t *testing.T) {
+	app := newRouteToggleTestApp(t, &AppOptions{DisabledRoutes: []string{"GET /order"}})
+
+	err := app.Validate()
+	assert.ErrorIs(t, err, ErrRouteNotRegistered)
+	assert.Contains(t, err.Error(), "GET /order")
+}
@@ -1,24 +1,48 @@
 package core
 
 import (
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
+// MethodAny is the RouteConfig.Method recorded for routes registered with Any
+const MethodAny = "ANY"
+
 // RouteHandler defines a handler function that has access to the DI container
 type RouteHandler func(c *gin.Context, container DIContainer)
 
 // RouteConfig contains configuration options for a route
 type RouteConfig struct {
-	Path            string
-	IsAuth          *bool
+	// Method is the HTTP method ("GET", "POST", ... or MethodAny); set by the router's verb methods
+	Method string
+	Path   string
+	IsAuth *bool
+	// SchemaValidator is a struct pointer whose type the JSON body is bound into and
+	// validated against (go-playground/validator `binding` tags); see GetValidatedBody
 	SchemaValidator interface{}
-	Options         map[string]interface{}
+	// Options carries per-route settings for plugins and the framework, e.g. ContentTypesOption
+	Options map[string]interface{}
+	// Middlewares run after the global OnRequest hooks and before the route handler
+	// (and controller resolution); aborting in a middleware skips the handler
+	Middlewares []gin.HandlerFunc
+	// Timeout bounds the route middleware and handler, answering 504 when exceeded, or
+	// 503 when it expires while resolving the handler's dependencies
+	// Zero inherits AppOptions.RequestTimeout (no limit when that is zero too);
+	// NoTimeout disables the limit for this route
+	Timeout time.Duration
 }
 
 // Router wraps gin.Engine and provides dependency injection support
 type Router struct {
 	engine    *gin.Engine
 	container DIContainer
+	module    string // Owning module name recorded in the route registry
 }
 
 // NewRouter creates a new router helper
@@ -37,56 +61,103 @@ func (r *Router) Group(relativePath string, handlers ...gin.HandlerFunc) *Router
 	}
 }
 
+// Use installs global middleware with container access, e.g. to resolve a service
+// and c.Set a value for the handlers. The container is the request container when
+// there is one, otherwise the router's container, bound to the request context
+// The middleware runs after the OnRequest hooks (so after authentication) and before
+// route middleware, PreHandler hooks and the handler; aborting skips them. Like
+// gin's Use, it applies to the routes registered after it
+func (r *Router) Use(middleware ...RouteHandler) {
+	for _, handler := range middleware {
+		r.engine.Use(r.wrapMiddleware(handler))
+	}
+}
+
+// wrapMiddleware wraps a RouteHandler used as middleware
+func (r *Router) wrapMiddleware(middleware RouteHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var container DIContainer = r.container
+		if requestContainer, ok := GetRequestContainer(c); ok {
+			container = requestContainer
+		}
+		middleware(c, &boundContainer{DIContainer: container, ctx: c.Request.Context()})
+	}
+}
+
 // GET registers a GET route
 func (r *Router) GET(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.GET(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodGet
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.GET(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // POST registers a POST route
 func (r *Router) POST(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.POST(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodPost
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.POST(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // PUT registers a PUT route
 func (r *Router) PUT(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.PUT(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodPut
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.PUT(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // PATCH registers a PATCH route
 func (r *Router) PATCH(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.PATCH(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodPatch
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.PATCH(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // DELETE registers a DELETE route
 func (r *Router) DELETE(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.DELETE(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodDelete
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.DELETE(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // OPTIONS registers an OPTIONS route
 func (r *Router) OPTIONS(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.OPTIONS(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodOptions
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.OPTIONS(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // HEAD registers a HEAD route
 func (r *Router) HEAD(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.HEAD(config.Path, r.wrapHandler(handler))
+	config.Method = http.MethodHead
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.HEAD(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // Any registers a route that matches all HTTP methods
 func (r *Router) Any(config RouteConfig, handler RouteHandler) {
-	r.triggerOnRoute(&config)
-	r.engine.Any(config.Path, r.wrapHandler(handler))
+	config.Method = MethodAny
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.Any(config.Path, routeHandlers(config, r.wrapHandler(handler))...)
 }
 
 // buildOptions converts RouteConfig to options map
-func (r *Router) buildOptions(config RouteConfig) map[string]interface{} {
+func buildOptions(config RouteConfig) map[string]interface{} {
 	options := make(map[string]interface{})
 
 	if config.Options != nil {
@@ -116,13 +187,34 @@ func (r *Router) StaticFile(relativePath, filepath string) {
 	r.engine.StaticFile(relativePath, filepath)
 }
 
+// routeHandlers builds the gin handler chain for a route: the content type guard and
+// the timeout (when set) first, then route middleware, then body schema validation
+// (when configured), then the handler
+func routeHandlers(config RouteConfig, handler gin.HandlerFunc) []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(config.Middlewares)+4)
+	if contentTypes := routeContentTypes(config); len(contentTypes) > 0 {
+		handlers = append(handlers, contentTypeHandler(contentTypes))
+	}
+	if config.Timeout > 0 {
+		handlers = append(handlers, timeoutHandler(config.Timeout))
+	}
+	handlers = append(handlers, config.Middlewares...)
+	if limit, ok := routeMultipartMaxSize(config); ok {
+		handlers = append(handlers, multipartMaxSizeHandler(limit))
+	}
+	if config.SchemaValidator != nil {
+		handlers = append(handlers, schemaValidationHandler(config.SchemaValidator))
+	}
+	return append(handlers, handler)
+}
+
 // wrapHandler wraps a RouteHandler to provide access to the DI container
 func (r *Router) wrapHandler(handler RouteHandler) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get container from context
 		container, exists := c.Get("container")
 		if !exists {
-			c.JSON(500, gin.H{"error": "DI container not found"})
+			AbortWithError(c, ErrInternal.WithMessage("DI container not found"))
 			return
 		}
 
@@ -136,18 +228,90 @@ func (r *Router) wrapHandler(handler RouteHandler) gin.HandlerFunc {
 			}
 		}
 
-		// Call the handler with the container
-		handler(c, container.(DIContainer))
+		// Call the handler with the container, bound to the request context so that
+		// cancelling the request also cancels async providers it resolves
+		handler(c, &boundContainer{DIContainer: container.(DIContainer), ctx: c.Request.Context()})
 	}
 }
 
-// triggerOnRoute triggers the OnRoute hook
-func (r *Router) triggerOnRoute(config *RouteConfig) {
+// triggerOnRoute records the route in the registry and triggers the OnRoute hook
+// It reports false when the route conflicts with one already registered; the conflict
+// is reported by the plugin manager and the route must not be handed to gin
+func (r *Router) triggerOnRoute(config *RouteConfig) bool {
+	return r.recordAndTriggerRoute(config, nil)
+}
+
+// recordAndTriggerRoute is triggerOnRoute, also recording the parameters the route's
+// handler has injected (see PluginManager.Validate)
+func (r *Router) recordAndTriggerRoute(config *RouteConfig, params []reflect.Type) bool {
 	if pm, err := r.container.Resolve("pluginManager"); err == nil {
 		if pluginManager, ok := pm.(*PluginManager); ok {
+			err := pluginManager.recordRoute(RouteInfo{
+				Method:  config.Method,
+				Path:    config.Path,
+				Module:  r.module,
+				Options: buildOptions(*config),
+				params:  params,
+			})
+			if err != nil {
+				return false
+			}
+			if config.Timeout == 0 && pluginManager.app != nil {
+				config.Timeout = pluginManager.app.requestTimeout
+			}
 			pluginManager.ExecuteOnRoute(config)
 		}
 	}
+	return true
+}
+
+// joinRoutePath joins a group base path and a route path the way gin does
+func joinRoutePath(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+
+	joined := path.Join(base, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
+
+// routePatternMatches reports whether a gin route pattern such as "/users/:id" or
+// "/files/*filepath" matches a request path
+func routePatternMatches(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return len(pathSegments) > i
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
+
+// methodRank orders HTTP methods the way they are usually listed
+func methodRank(method string) int {
+	if i := slices.Index([]string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace,
+	}, method); i >= 0 {
+		return i
+	}
+	return 100 // Unknown methods last, in registration order
 }
 
 // RouterGroup provides helper methods for route groups
@@ -166,50 +330,90 @@ func (rg *RouterGroup) Group(relativePath string, handlers ...gin.HandlerFunc) *
 
 // GET registers a GET route in the group
 func (rg *RouterGroup) GET(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.GET(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodGet
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.GET(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // POST registers a POST route in the group
 func (rg *RouterGroup) POST(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.POST(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPost
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.POST(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // PUT registers a PUT route in the group
 func (rg *RouterGroup) PUT(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.PUT(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPut
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.PUT(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // PATCH registers a PATCH route in the group
 func (rg *RouterGroup) PATCH(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.PATCH(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodPatch
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.PATCH(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // DELETE registers a DELETE route in the group
 func (rg *RouterGroup) DELETE(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.DELETE(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodDelete
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.DELETE(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // OPTIONS registers an OPTIONS route in the group
 func (rg *RouterGroup) OPTIONS(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.OPTIONS(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodOptions
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.OPTIONS(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // HEAD registers a HEAD route in the group
 func (rg *RouterGroup) HEAD(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.HEAD(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodHead
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.HEAD(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // Any registers a route that matches all HTTP methods in the group
 func (rg *RouterGroup) Any(config RouteConfig, handler RouteHandler) {
-	rg.router.triggerOnRoute(&config)
-	rg.group.Any(config.Path, rg.router.wrapHandler(handler))
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = MethodAny
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.Any(path, routeHandlers(config, rg.router.wrapHandler(handler))...)
 }
 
 // Static registers a static file server in the group
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoffApp_GetRoutes(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	noop := func(c *gin.Context, container DIContainer) {}
+	isAuth := true
+
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("users", "1.0.0").WithPrefix("/users"))))
+
+	router := app.GetRouter()
+	router.GET(RouteConfig{Path: "/health"}, noop)
+	router.Group("/admin").POST(RouteConfig{Path: "reports", IsAuth: &isAuth}, noop)
+
+	moduleRouter := app.GetPluginManager().GetEnhancedRouterForModule("users")
+	moduleRouter.DELETE(RouteConfig{
+		Path:    "profile",
+		Options: map[string]interface{}{"audit": true},
+	}, func(c *gin.Context, controller *routeTestController) {})
+
+	routes := app.GetRoutes()
+	require.Len(t, routes, 3)
+
+	assert.Equal(t, RouteInfo{Method: http.MethodGet, Path: "/health", Options: map[string]interface{}{}}, routes[0])
+	assert.Equal(t, RouteInfo{
+		Method:  http.MethodPost,
+		Path:    "/admin/reports",
+		Options: map[string]interface{}{"isAuth": true},
+	}, routes[1])
+	assert.Equal(t, RouteInfo{
+		Method:  http.MethodDelete,
+		Path:    "/users/profile",
+		Module:  "users",
+		Options: map[string]interface{}{"audit": true},
+	}, routes[2])
+
+	// The returned slice is a copy
+	routes[0].Path = "/changed"
+	assert.Equal(t, "/health", app.GetRoutes()[0].Path)
+}
+
+func TestRouter_OnRouteReceivesMethod(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	noop := func(c *gin.Context, container DIContainer) {}
+
+	var registered []string
+	app.GetPluginManager().GetLifecycleManager().AddAppHook(&ApplicationHookFunc{
+		OnRouteFunc: func(config *RouteConfig) {
+			registered = append(registered, config.Method+" "+config.Path)
+		},
+	})
+
+	router := app.GetRouter()
+	router.GET(RouteConfig{Path: "/items"}, noop)
+	router.PUT(RouteConfig{Path: "/items/:id"}, noop)
+	router.Any(RouteConfig{Path: "/proxy"}, noop)
+
+	enhanced := app.GetEnhancedRouter()
+	enhanced.PATCH(RouteConfig{Path: "/orders/:id"}, func(c *gin.Context, controller *routeTestController) {})
+	enhanced.Group("/v2").HEAD(RouteConfig{Path: "status"}, func(c *gin.Context, controller *routeTestController) {})
+
+	assert.Equal(t, []string{
+		"GET /items",
+		"PUT /items/:id",
+		"ANY /proxy",
+		"PATCH /orders/:id",
+		"HEAD /v2/status",
+	}, registered)
+}
+
+func TestPluginManager_GetEnhancedRouterForModuleUsesParentPrefix(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	users := NewModule("users", "1.0.0").WithPrefix("/users")
+	api := NewModule("api", "1.0.0").WithPrefix("/api").WithImports(users)
+
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(users)))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(api)))
+
+	pm := app.GetPluginManager()
+	assert.Equal(t, "/api/users", pm.GetModulePrefix("users"))
+
+	pm.GetEnhancedRouterForModule("users").GET(RouteConfig{Path: "me"}, func(c *gin.Context, controller *routeTestController) {})
+
+	routes := app.GetRoutes()
+	require.Len(t, routes, 1)
+	assert.Equal(t, "/api/users/me", routes[0].Path)
+}
+
+func TestRouter_DuplicateRouteConflict(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	// users is mounted under api: /api + /v1; accounts declares /api/v1 directly
+	users := NewModule("users", "1.0.0").WithPrefix("/v1")
+	api := NewModule("api", "1.0.0").WithPrefix("/api").WithImports(users)
+	accounts := NewModule("accounts", "1.0.0").WithPrefix("/api/v1")
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(users)))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(api)))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(accounts)))
+
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+	pm := app.GetPluginManager()
+	pm.GetEnhancedRouterForModule("users").GET(RouteConfig{Path: "users"}, func(c *gin.Context, controller *routeTestController) {
+		c.String(http.StatusOK, "users")
+	})
+	pm.GetEnhancedRouterForModule("accounts").Any(RouteConfig{Path: "users"}, func(c *gin.Context, controller *routeTestController) {
+		c.String(http.StatusOK, "accounts")
+	})
+	// Wildcard names do not matter to gin either
+	pm.GetEnhancedRouterForModule("users").GET(RouteConfig{Path: "users/:id"}, func(c *gin.Context, controller *routeTestController) {})
+	pm.GetEnhancedRouterForModule("accounts").GET(RouteConfig{Path: "users/:accountId"}, func(c *gin.Context, controller *routeTestController) {})
+	// Different methods on the same path are fine
+	pm.GetEnhancedRouterForModule("accounts").POST(RouteConfig{Path: "users"}, func(c *gin.Context, controller *routeTestController) {})
+
+	require.Len(t, app.GetRoutes(), 3)
+
+	err := app.prepare()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRouteConflict)
+	assert.Contains(t, err.Error(), "ANY /api/v1/users (module 'accounts') conflicts with GET /api/v1/users (module 'users')")
+	assert.Contains(t, err.Error(), "GET /api/v1/users/:accountId (module 'accounts') conflicts with GET /api/v1/users/:id (module 'users')")
+
+	// The first registration keeps serving
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, "users", recorder.Body.String())
+}
+
+func TestRouter_HandlerContainerFollowsRequestContext(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	started, observed := make(chan struct{}), make(chan error, 1)
+	blockingAsyncProvider(t, app.GetContainer(), started, observed)
+
+	router := NewRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/slow"}, func(c *gin.Context, container DIContainer) {
+		_, err := container.Resolve("*core.TestService")
+		AbortWithError(c, err)
+	})
+
+	serveCancelled(app, "/slow", started)
+	assert.ErrorIs(t, <-observed, context.Canceled)
+}
+
+func TestRouter_UseGlobalMiddlewareWithContainer(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	require.NoError(t, app.GetContainer().RegisterSingleton("tenantResolver", func(c DIContainer) (interface{}, error) {
+		return func(host string) string { return "tenant-of-" + host }, nil
+	}))
+
+	var order []string
+	lifecycle := app.GetPluginManager().GetLifecycleManager()
+	lifecycle.AddHook(NewOnRequestHook(func(c *gin.Context) { order = append(order, "OnRequest") }))
+	lifecycle.AddHook(NewPreHandlerHook(func(c *gin.Context) { order = append(order, "PreHandler") }))
+
+	router := app.GetRouter()
+	router.Use(func(c *gin.Context, container DIContainer) {
+		order = append(order, "middleware")
+		resolver, err := container.Resolve("tenantResolver")
+		if err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		c.Set("tenant", resolver.(func(string) string)(c.Request.Host))
+	})
+	router.GET(RouteConfig{Path: "/tenant"}, func(c *gin.Context, container DIContainer) {
+		order = append(order, "handler")
+		c.String(http.StatusOK, c.GetString("tenant"))
+	})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+	request.Host = "acme.example"
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "tenant-of-acme.example", recorder.Body.String())
+	assert.Equal(t, []string{"OnRequest", "middleware", "PreHandler", "handler"}, order)
+}
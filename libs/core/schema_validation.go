@@ -0,0 +1,152 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidatedBodyKey is the key under which the validated request body is stored
+// in the gin context and the request container
+const ValidatedBodyKey = "validatedBody"
+
+// FieldError describes a single field that failed schema validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// GetValidatedBody returns the body parsed by the route's SchemaValidator
+func GetValidatedBody(c *gin.Context) (interface{}, bool) {
+	return c.Get(ValidatedBodyKey)
+}
+
+// schemaValidationHandler binds the JSON body into a fresh copy of the schema struct
+// and rejects the request with 400 when binding or validation fails
+func schemaValidationHandler(schema interface{}) gin.HandlerFunc {
+	schemaType := reflect.TypeOf(schema)
+	validSchema := schemaType.Kind() == reflect.Ptr && schemaType.Elem().Kind() == reflect.Struct
+
+	return func(c *gin.Context) {
+		if !validSchema {
+			AbortWithError(c, ErrInternal.WithMessage(fmt.Sprintf("Invalid schema validator: expected struct pointer, got %s", schemaType)))
+			return
+		}
+
+		body := reflect.New(schemaType.Elem()).Interface()
+		if err := c.ShouldBindJSON(body); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				AbortWithError(c, err)
+				return
+			}
+
+			fields := schemaFieldErrors(schemaType.Elem(), err)
+			if fields == nil {
+				AbortWithError(c, ErrBadRequest.WithMessage(fmt.Sprintf("Invalid request body: %v", err)).WithCause(err))
+				return
+			}
+
+			AbortWithError(c, ErrBadRequest.WithMessage("Validation failed").WithDetails(fields).WithCause(err))
+			return
+		}
+
+		c.Set(ValidatedBodyKey, body)
+		if requestContainer, ok := GetRequestContainer(c); ok {
+			requestContainer.DecorateRequest(ValidatedBodyKey, body)
+		}
+	}
+}
+
+// schemaFieldErrors converts binding errors into field errors
+// It returns nil when the error is not tied to specific fields (e.g. malformed JSON)
+func schemaFieldErrors(schemaType reflect.Type, err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   taggedFieldPath(schemaType, fe.StructNamespace(), "json"),
+				Rule:    fe.Tag(),
+				Message: validationMessage(fe),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return []FieldError{{
+			Field:   field,
+			Rule:    "type",
+			Message: fmt.Sprintf("expected %s but got %s", typeErr.Type, typeErr.Value),
+		}}
+	}
+
+	return nil
+}
+
+// taggedFieldPath maps a validator struct namespace (e.g. "CreateUser.Address.City")
+// to the path clients sent, named by the fields' tag (e.g. "address.city" for json)
+func taggedFieldPath(schemaType reflect.Type, namespace, tagName string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		// Drop the top-level struct name
+		parts = parts[1:]
+	}
+
+	current := schemaType
+	for i, part := range parts {
+		for current.Kind() == reflect.Ptr || current.Kind() == reflect.Slice || current.Kind() == reflect.Map {
+			current = current.Elem()
+		}
+		if current.Kind() != reflect.Struct {
+			break
+		}
+
+		// Strip index suffixes such as "Items[0]"
+		name, index := part, ""
+		if idx := strings.Index(part, "["); idx >= 0 {
+			name, index = part[:idx], part[idx:]
+		}
+
+		field, ok := current.FieldByName(name)
+		if !ok {
+			break
+		}
+		if tag := strings.Split(field.Tag.Get(tagName), ",")[0]; tag != "" && tag != "-" {
+			parts[i] = tag + index
+		}
+		current = field.Type
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// validationMessage renders a human readable message for a failed validation rule
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min", "max", "len", "gt", "gte", "lt", "lte", "oneof":
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("failed '%s=%s' validation", fe.Tag(), fe.Param())
+		}
+		return fmt.Sprintf("failed '%s' validation", fe.Tag())
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrScopeClosed is returned when resolving through a ScopedContainer after Close
+var ErrScopeClosed = newError("scope is closed")
+
+// ScopedContainer is a DI container for a unit of work outside HTTP requests, such
+// as a background job or CLI command:
+//
+//   - Scoped services are built once per scope and reused until it is closed
+//   - Singleton and Transient services keep their lifetime from the parent
+//   - Close disposes the scope's Disposable instances, in reverse creation order
+//
+// For example:
+//
+//	scope := core.NewScopedContainer(app.GetContainer())
+//	defer scope.Close()
+type ScopedContainer struct {
+	*diContainer // Embed base container
+
+	closed atomic.Bool
+}
+
+// NewScopedContainer creates a scope resolving through parent, e.g. the root or a module container
+func NewScopedContainer(parent DIContainer) *ScopedContainer {
+	base := &diContainer{
+		services: make(map[string]*ServiceDefinition),
+		parent:   parent,
+	}
+	base.scope = newResolutionScope(base)
+
+	return &ScopedContainer{diContainer: base}
+}
+
+// Resolve resolves a service by name within the scope
+func (s *ScopedContainer) Resolve(name string) (interface{}, error) {
+	return s.ResolveWithContext(name, context.Background())
+}
+
+// ResolveWithContext resolves a service within the scope; it fails once the scope is closed
+func (s *ScopedContainer) ResolveWithContext(name string, ctx context.Context) (interface{}, error) {
+	if s.closed.Load() {
+		return nil, fmt.Errorf("%w: cannot resolve '%s'", ErrScopeClosed, name)
+	}
+	return s.diContainer.ResolveWithContext(name, ctx)
+}
+
+// ResolveOptional resolves a service within the scope, reporting whether it is registered
+func (s *ScopedContainer) ResolveOptional(name string) (interface{}, bool, error) {
+	return s.ResolveOptionalWithContext(name, context.Background())
+}
+
+// ResolveOptionalWithContext is ResolveOptional with a context; it fails once the scope is closed
+func (s *ScopedContainer) ResolveOptionalWithContext(name string, ctx context.Context) (interface{}, bool, error) {
+	return resolveOptional(s, name, ctx)
+}
+
+// ResolveAs resolves a service within the scope into the target pointer
+func (s *ScopedContainer) ResolveAs(name string, target interface{}) error {
+	return s.ResolveAsWithContext(name, context.Background(), target)
+}
+
+// ResolveAsWithContext resolves a service with context within the scope into the target pointer
+func (s *ScopedContainer) ResolveAsWithContext(name string, ctx context.Context, target interface{}) error {
+	instance, err := s.ResolveWithContext(name, ctx)
+	if err != nil {
+		return err
+	}
+	return assignResolved(name, instance, target)
+}
+
+// ResolveGroup resolves every member of the group within the scope
+func (s *ScopedContainer) ResolveGroup(group string) ([]interface{}, error) {
+	return s.ResolveGroupWithContext(group, context.Background())
+}
+
+// ResolveGroupWithContext resolves the parent's group members, then the scope's
+func (s *ScopedContainer) ResolveGroupWithContext(group string, ctx context.Context) ([]interface{}, error) {
+	if s.closed.Load() {
+		return nil, fmt.Errorf("%w: cannot resolve group '%s'", ErrScopeClosed, group)
+	}
+	return resolveGroup(s, s.diContainer, group, ctx)
+}
+
+// Close disposes the scope's instances; later resolutions fail with ErrScopeClosed
+// Closing an already closed scope does nothing
+func (s *ScopedContainer) Close() error {
+	if !s.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	return s.diContainer.Dispose()
+}
+
+// Dispose closes the scope (see Close)
+func (s *ScopedContainer) Dispose() error {
+	return s.Close()
+}
@@ -1,8 +1,14 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -98,6 +104,35 @@ func TestRequestContainer_ResolveOrder(t *testing.T) {
 	assert.Equal(t, "module-service", service)
 }
 
+func TestRequestContainer_ResolveAsRequestData(t *testing.T) {
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), NewDIContainer())
+	requestContainer := NewRequestContainer(moduleContainer)
+	requestContainer.DecorateRequest("correlationID", "corr-42")
+
+	var correlationID string
+	require.NoError(t, requestContainer.ResolveAs("correlationID", &correlationID))
+	assert.Equal(t, "corr-42", correlationID)
+
+	var wrongType int
+	err := requestContainer.ResolveAs("correlationID", &wrongType)
+	assert.ErrorContains(t, err, "cannot be assigned to target type int")
+}
+
+func TestModuleContainer_ResolveAsDecorator(t *testing.T) {
+	parentModule := NewModuleContainer(DefaultModule("parent", "1.0.0"), NewDIContainer())
+	require.NoError(t, parentModule.Decorate("config", &TestService{Value: "decorated"}))
+	moduleContainer := NewModuleContainer(DefaultModule("child", "1.0.0"), parentModule)
+
+	var config *TestService
+	require.NoError(t, moduleContainer.ResolveAs("config", &config))
+	assert.Equal(t, "decorated", config.Value)
+
+	// Request containers reach module decorators through the same path
+	config = nil
+	require.NoError(t, moduleContainer.CreateRequestScope().ResolveAs("config", &config))
+	assert.Equal(t, "decorated", config.Value)
+}
+
 func TestDecoratorManager_InstanceDecorators(t *testing.T) {
 	dm := NewDecoratorManager()
 
@@ -178,6 +213,83 @@ func TestRequestContainer_InitializeFromManager(t *testing.T) {
 	assert.NotNil(t, helper)
 }
 
+func TestCallReplyHelper(t *testing.T) {
+	dm := NewDecoratorManager()
+	require.NoError(t, RegisterReplyHelper(dm, "envelope", func(data string) map[string]interface{} {
+		return map[string]interface{}{"data": data}
+	}))
+	require.NoError(t, dm.DecorateReply("untyped", "not a function"))
+	assert.Error(t, RegisterReplyHelper[string, string](dm, "nilHelper", nil))
+
+	requestContainer := NewRequestContainer(NewModuleContainer(DefaultModule("test", "1.0.0"), NewDIContainer()))
+	dm.InitializeReplyHelpers(requestContainer)
+
+	response, err := CallReplyHelper[string, map[string]interface{}](requestContainer, "envelope", "ok")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"data": "ok"}, response)
+
+	// Signature mismatch names both types
+	_, err = CallReplyHelper[interface{}, map[string]interface{}](requestContainer, "envelope", "ok")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is of type func(string) map[string]interface {}, not func(interface {}) map[string]interface {}")
+
+	_, err = CallReplyHelper[string, string](requestContainer, "untyped", "ok")
+	assert.Error(t, err)
+
+	_, err = CallReplyHelper[string, string](requestContainer, "missing", "ok")
+	assert.EqualError(t, err, "reply helper 'missing' is not registered")
+
+	_, err = CallReplyHelper[string, string](nil, "envelope", "ok")
+	assert.Error(t, err)
+}
+
+func TestRequestContainer_InitializeFromFactoryDecorator(t *testing.T) {
+	dm := NewDecoratorManager()
+	dm.DecorateRequest("region", "eu")
+
+	var calls int32
+	require.NoError(t, dm.DecorateRequestFactory("correlationID", func(c *gin.Context) interface{} {
+		n := atomic.AddInt32(&calls, 1)
+		return fmt.Sprintf("%s-%d", c.GetHeader("X-Tenant"), n)
+	}))
+	assert.Error(t, dm.DecorateRequestFactory("missing", nil))
+
+	moduleContainer := NewModuleContainer(DefaultModule("test", "1.0.0"), NewDIContainer())
+	newRequest := func() *RequestContainer {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("X-Tenant", "acme")
+
+		rc := NewRequestContainer(moduleContainer)
+		dm.InitializeRequestContainerWithContext(c, rc)
+		return rc
+	}
+
+	// Each request computes its own value; static defaults are still copied
+	seen := make(map[interface{}]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rc := newRequest()
+			id, _ := rc.GetRequestData("correlationID")
+			region, _ := rc.GetRequestData("region")
+			assert.Equal(t, "eu", region)
+
+			mu.Lock()
+			seen[id] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 20)
+	assert.True(t, seen["acme-1"])
+	assert.Equal(t, int32(20), atomic.LoadInt32(&calls))
+}
+
 func TestRequestContainer_ConcurrentAccess(t *testing.T) {
 	module := DefaultModule("test", "1.0.0")
 	moduleContainer := NewModuleContainer(module, NewDIContainer())
@@ -247,4 +359,380 @@ func TestDoffApp_DecoratorMethods(t *testing.T) {
 	helper, exists := app.GetDecoratorManager().GetReplyDecorator("replyKey")
 	require.True(t, exists)
 	assert.NotNil(t, helper)
-}
\ No newline at end of file
+}
+
+func TestDoffApp_OverrideDecorators(t *testing.T) {
+	app := &DoffApp{decoratorManager: NewDecoratorManager()}
+
+	// A plugin provides defaults
+	require.NoError(t, app.Decorate("apiVersion", "v1"))
+	require.NoError(t, app.DecorateRequest("timeout", 30))
+	require.NoError(t, app.DecorateReply("envelope", "plugin"))
+
+	// Registering again still fails; overriding replaces the value
+	assert.Error(t, app.Decorate("apiVersion", "v2"))
+	app.OverrideDecorate("apiVersion", "v2")
+	app.OverrideDecorateRequest("timeout", 60)
+	app.OverrideDecorateReply("envelope", "app")
+	app.OverrideDecorate("newKey", "added")
+
+	dm := app.GetDecoratorManager()
+	value, _ := dm.GetInstanceDecorator("apiVersion")
+	assert.Equal(t, "v2", value)
+	value, _ = dm.GetRequestDecorator("timeout")
+	assert.Equal(t, 60, value)
+	value, _ = dm.GetReplyDecorator("envelope")
+	assert.Equal(t, "app", value)
+	value, exists := dm.GetInstanceDecorator("newKey")
+	assert.True(t, exists)
+	assert.Equal(t, "added", value)
+
+	instanceCount, requestCount, replyCount := dm.GetDecoratorStats()
+	assert.Equal(t, []int{2, 1, 1}, []int{instanceCount, requestCount, replyCount})
+}
+
+func TestRequestContainer_CachesRequestServices(t *testing.T) {
+	module := DefaultModule("test", "1.0.0")
+	parentContainer := NewDIContainer()
+	moduleContainer := NewModuleContainer(module, parentContainer)
+
+	// Module-level service resolved through the request container
+	moduleContainer.RegisterTransient("moduleTransient", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "module"}, nil
+	})
+
+	requestContainer := NewRequestContainer(moduleContainer)
+
+	builds := 0
+	requestContainer.RegisterSingleton("requestState", func(container DIContainer) (interface{}, error) {
+		builds++
+		return &TestService{Value: "state"}, nil
+	})
+	requestContainer.RegisterTransient("requestTransient", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "transient"}, nil
+	})
+
+	first, err := requestContainer.Resolve("requestState")
+	require.NoError(t, err)
+	second, err := requestContainer.Resolve("requestState")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, builds)
+
+	transient1, _ := requestContainer.Resolve("requestTransient")
+	transient2, _ := requestContainer.Resolve("requestTransient")
+	assert.NotSame(t, transient1, transient2)
+
+	// Services not registered locally still fall through to the module container
+	moduleService, err := requestContainer.Resolve("moduleTransient")
+	require.NoError(t, err)
+	assert.Equal(t, "module", moduleService.(*TestService).Value)
+}
+
+func TestRequestContainer_ScopedServicesPerRequest(t *testing.T) {
+	module := DefaultModule("test", "1.0.0")
+	moduleContainer := NewModuleContainer(module, NewDIContainer())
+
+	moduleContainer.RegisterScoped("unitOfWork", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "uow"}, nil
+	})
+	moduleContainer.RegisterScoped("repository", func(container DIContainer) (interface{}, error) {
+		uow, err := container.Resolve("unitOfWork")
+		if err != nil {
+			return nil, err
+		}
+		return uow, nil
+	})
+	moduleContainer.RegisterTransient("transient", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "transient"}, nil
+	})
+
+	request1 := moduleContainer.CreateRequestScope()
+	first, err := request1.Resolve("unitOfWork")
+	require.NoError(t, err)
+	second, err := request1.Resolve("unitOfWork")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// Scoped dependencies of scoped services come from the same request
+	repository, err := request1.Resolve("repository")
+	require.NoError(t, err)
+	assert.Same(t, first, repository)
+
+	request2 := moduleContainer.CreateRequestScope()
+	other, err := request2.Resolve("unitOfWork")
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	// Transient keeps building fresh instances within a request
+	transient1, _ := request1.Resolve("transient")
+	transient2, _ := request1.Resolve("transient")
+	assert.NotSame(t, transient1, transient2)
+
+	// Outside a request scope, scoped services are not cached
+	outside1, _ := moduleContainer.Resolve("unitOfWork")
+	outside2, _ := moduleContainer.Resolve("unitOfWork")
+	assert.NotSame(t, outside1, outside2)
+}
+
+func TestRequestContainer_SharesModuleSingletons(t *testing.T) {
+	module := DefaultModule("test", "1.0.0")
+	moduleContainer := NewModuleContainer(module, NewDIContainer())
+
+	var builds int32
+	moduleContainer.RegisterSingleton("cache", func(container DIContainer) (interface{}, error) {
+		atomic.AddInt32(&builds, 1)
+		return &TestService{Value: "cache"}, nil
+	})
+
+	request1 := moduleContainer.CreateRequestScope()
+	request2 := moduleContainer.CreateRequestScope()
+	request1.RegisterSingleton("requestState", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "state"}, nil
+	})
+	request2.RegisterSingleton("requestState", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "state"}, nil
+	})
+
+	first, err := request1.Resolve("cache")
+	require.NoError(t, err)
+	second, err := request2.Resolve("cache")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	fromModule, err := moduleContainer.Resolve("cache")
+	require.NoError(t, err)
+	assert.Same(t, first, fromModule)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&builds))
+
+	// Singletons registered on a request container stay with that request
+	state1, _ := request1.Resolve("requestState")
+	state1Again, _ := request1.Resolve("requestState")
+	state2, _ := request2.Resolve("requestState")
+	assert.Same(t, state1, state1Again)
+	assert.NotSame(t, state1, state2)
+}
+
+func TestRequestContainer_ConcurrentModuleSingleton(t *testing.T) {
+	module := DefaultModule("test", "1.0.0")
+	moduleContainer := NewModuleContainer(module, NewDIContainer())
+	moduleContainer.RegisterSingleton("cache", func(container DIContainer) (interface{}, error) {
+		return &TestService{Value: "cache"}, nil
+	})
+
+	const requests = 20
+	instances := make([]interface{}, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			instances[i], _ = moduleContainer.CreateRequestScope().Resolve("cache")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, instance := range instances {
+		assert.Same(t, instances[0], instance)
+	}
+}
+
+func TestDoffApp_RequestContainersDisabledByDefault(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetEngine().GET("/plain", func(c *gin.Context) {
+		_, ok := GetRequestContainer(c)
+		assert.False(t, ok)
+	})
+
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/plain", nil))
+}
+
+func TestDoffApp_RequestContainerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:              "request-containers",
+		Mode:              gin.TestMode,
+		RequestContainers: &RequestContainerOptions{Key: "scope"},
+	}).(*DoffApp)
+	require.NoError(t, app.DecorateRequestFactory("correlationID", func(c *gin.Context) interface{} {
+		return c.GetHeader("X-Correlation-ID")
+	}))
+	require.NoError(t, app.GetContainer().RegisterSingleton("*core.TestService", func(c DIContainer) (interface{}, error) {
+		return &TestService{Value: "shared"}, nil
+	}))
+	require.NoError(t, app.RegisterPlugin(newModuleTestPlugin(NewModule("orders", "1.0.0"))))
+
+	var scopes []DIContainer
+	router := app.GetPluginManager().GetEnhancedRouterForModule("orders")
+	router.GET(RouteConfig{Path: "/orders"}, func(c *gin.Context, service *TestService) {
+		rc, ok := GetRequestContainer(c)
+		require.True(t, ok)
+		assert.Same(t, rc, c.MustGet("scope"))
+		scopes = append(scopes, rc.GetModule())
+
+		var correlationID string
+		require.NoError(t, rc.ResolveAs("correlationID", &correlationID))
+		c.String(http.StatusOK, correlationID+" "+service.Value)
+	})
+	app.GetEngine().GET("/outside", func(c *gin.Context) {
+		rc, ok := GetRequestContainer(c)
+		require.True(t, ok)
+		scopes = append(scopes, rc.GetModule())
+	})
+
+	for _, id := range []string{"corr-1", "corr-2"} {
+		request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		request.Header.Set("X-Correlation-ID", id)
+		recorder := httptest.NewRecorder()
+		app.GetEngine().ServeHTTP(recorder, request)
+		assert.Equal(t, id+" shared", recorder.Body.String())
+	}
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/outside", nil))
+
+	// Routes of a module share its container; other routes use the root container
+	orders, exists := app.GetModuleContainer("orders")
+	require.True(t, exists)
+	require.Len(t, scopes, 3)
+	assert.Same(t, orders, scopes[0])
+	assert.Same(t, orders, scopes[1])
+	assert.Same(t, app.GetContainer(), scopes[2])
+}
+
+func TestDoffApp_RequestContainerMiddlewareDisposesScopedServices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:              "request-dispose",
+		Mode:              gin.TestMode,
+		RequestContainers: &RequestContainerOptions{},
+	}).(*DoffApp)
+
+	var closed []string
+	built := 0
+	require.NoError(t, app.GetContainer().RegisterScoped("unitOfWork", func(c DIContainer) (interface{}, error) {
+		built++
+		return &disposableService{name: fmt.Sprintf("uow-%d", built), closed: &closed}, nil
+	}))
+	app.GetEngine().GET("/orders", func(c *gin.Context) {
+		rc, ok := GetRequestContainer(c)
+		require.True(t, ok)
+		first, err := rc.Resolve("unitOfWork")
+		require.NoError(t, err)
+		second, err := rc.Resolve("unitOfWork")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Len(t, closed, built-1, "only earlier requests' instances are closed")
+	})
+
+	for range 3 {
+		app.GetEngine().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	}
+
+	assert.Equal(t, []string{"uow-1", "uow-2", "uow-3"}, closed)
+}
+
+func TestRequestContainerFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:              "request-context",
+		Mode:              gin.TestMode,
+		RequestContainers: &RequestContainerOptions{},
+	}).(*DoffApp)
+	require.NoError(t, app.DecorateRequestFactory("tenant", func(c *gin.Context) interface{} {
+		return c.GetHeader("X-Tenant")
+	}))
+
+	// tenantOf stands for service code that only receives a context.Context
+	tenantOf := func(ctx context.Context) string {
+		rc, ok := RequestContainerFromContext(ctx)
+		require.True(t, ok)
+		var tenant string
+		require.NoError(t, rc.ResolveAs("tenant", &tenant))
+		return tenant
+	}
+
+	app.GetEngine().GET("/tenant", func(c *gin.Context) {
+		rc, ok := GetRequestContainer(c)
+		require.True(t, ok)
+		fromGin, ok := RequestContainerFromContext(c)
+		require.True(t, ok)
+		assert.Same(t, rc, fromGin)
+		fromRequest, ok := RequestContainerFromContext(c.Request.Context())
+		require.True(t, ok)
+		assert.Same(t, rc, fromRequest)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second)
+		defer cancel()
+		c.String(http.StatusOK, tenantOf(ctx))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+	request.Header.Set("X-Tenant", "acme")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	assert.Equal(t, "acme", recorder.Body.String())
+
+	_, ok := RequestContainerFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+// unitOfWork is a Scoped service shared by everything one job resolves
+type unitOfWork struct {
+	*disposableService
+}
+
+func TestScopedContainer_WorkerSharesScopedInstance(t *testing.T) {
+	root := NewDIContainer()
+	var closed []string
+	builds := 0
+	require.NoError(t, root.RegisterScoped("unitOfWork", func(c DIContainer) (interface{}, error) {
+		builds++
+		return &unitOfWork{&disposableService{name: fmt.Sprintf("unitOfWork-%d", builds), closed: &closed}}, nil
+	}))
+
+	// worker resolves the unit of work twice, as a repository and a handler would
+	worker := func(scope DIContainer) (*unitOfWork, *unitOfWork) {
+		var first, second *unitOfWork
+		require.NoError(t, scope.ResolveAs("unitOfWork", &first))
+		require.NoError(t, scope.ResolveAs("unitOfWork", &second))
+		return first, second
+	}
+
+	scope := NewScopedContainer(root)
+	first, second := worker(scope)
+	assert.Same(t, first, second)
+
+	next := root.CreateScope().(*ScopedContainer)
+	other, _ := worker(next)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, 2, builds)
+
+	require.NoError(t, scope.Close())
+	require.NoError(t, scope.Close(), "closing twice is a no-op")
+	assert.Equal(t, []string{"unitOfWork-1"}, closed)
+
+	_, err := scope.Resolve("unitOfWork")
+	assert.ErrorIs(t, err, ErrScopeClosed)
+	_, err = next.Resolve("unitOfWork")
+	assert.NoError(t, err)
+}
+
+func TestModuleContainer_CreateScopeResolvesThroughModule(t *testing.T) {
+	module := NewModuleContainer(NewModule("jobs", "1.0.0"), NewDIContainer())
+	require.NoError(t, module.Decorate("queue", "jobs-queue"))
+	require.NoError(t, module.RegisterScoped("batch", func(c DIContainer) (interface{}, error) {
+		return &TestService{Value: "batch"}, nil
+	}))
+
+	scope := module.CreateScope()
+	defer scope.Dispose()
+
+	queue, err := scope.Resolve("queue")
+	require.NoError(t, err)
+	assert.Equal(t, "jobs-queue", queue)
+
+	first, err := scope.Resolve("batch")
+	require.NoError(t, err)
+	second, err := scope.Resolve("batch")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+}
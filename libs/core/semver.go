@@ -0,0 +1,188 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed MAJOR.MINOR.PATCH version; pre-release and build suffixes are ignored
+type semver struct {
+	major, minor, patch int
+}
+
+// parseSemver parses versions like "1.2.3", "v1.2" or "1.2.3-beta"
+// Missing minor or patch parts default to 0
+func parseSemver(version string) (semver, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if v == "" || len(parts) > 3 {
+		return semver{}, fmt.Errorf("invalid version '%s'", version)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("invalid version '%s'", version)
+		}
+		numbers[i] = n
+	}
+
+	return semver{major: numbers[0], minor: numbers[1], patch: numbers[2]}, nil
+}
+
+// compare returns -1, 0 or 1 when v is lower than, equal to or greater than other
+func (v semver) compare(other semver) int {
+	for _, diff := range []int{v.major - other.major, v.minor - other.minor, v.patch - other.patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionConstraint is a parsed version range: alternatives joined by "||",
+// each a set of comparisons that must all hold
+type versionConstraint [][]versionComparison
+
+// versionComparison is one comparison such as ">=1.2.0"
+type versionComparison struct {
+	op      string
+	version semver
+}
+
+// parseVersionConstraint parses ranges such as "^1.0.0", "~1.2.0", ">=1.2.0 <2.0.0",
+// "1.2.3" (exact) and "1.x || ^2.1.0"; an empty range or "*" matches any version
+func parseVersionConstraint(constraint string) (versionConstraint, error) {
+	var parsed versionConstraint
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		var comparisons []versionComparison
+		for _, term := range strings.Fields(alternative) {
+			termComparisons, err := parseVersionTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+			}
+			comparisons = append(comparisons, termComparisons...)
+		}
+		parsed = append(parsed, comparisons)
+	}
+
+	return parsed, nil
+}
+
+// parseVersionTerm expands one term of a range into plain comparisons
+func parseVersionTerm(term string) ([]versionComparison, error) {
+	if term == "*" || term == "x" {
+		return nil, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(term, op) {
+			version, err := parseSemver(term[len(op):])
+			if err != nil {
+				return nil, err
+			}
+			return []versionComparison{{op: op, version: version}}, nil
+		}
+	}
+
+	switch term[0] {
+	case '^':
+		// Compatible with: same major, or same minor/patch for 0.x versions
+		version, err := parseSemver(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := semver{major: version.major + 1}
+		if version.major == 0 {
+			upper = semver{minor: version.minor + 1}
+			if version.minor == 0 {
+				upper = semver{patch: version.patch + 1}
+			}
+		}
+		return []versionComparison{{op: ">=", version: version}, {op: "<", version: upper}}, nil
+
+	case '~':
+		// Patch-level changes only
+		version, err := parseSemver(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := semver{major: version.major, minor: version.minor + 1}
+		return []versionComparison{{op: ">=", version: version}, {op: "<", version: upper}}, nil
+	}
+
+	// Wildcards: "1.x" and "1.2.x"
+	if trimmed := strings.TrimSuffix(strings.TrimSuffix(term, ".x"), ".*"); trimmed != term {
+		version, err := parseSemver(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		upper := semver{major: version.major + 1}
+		if strings.Count(trimmed, ".") == 1 {
+			upper = semver{major: version.major, minor: version.minor + 1}
+		}
+		return []versionComparison{{op: ">=", version: version}, {op: "<", version: upper}}, nil
+	}
+
+	version, err := parseSemver(term)
+	if err != nil {
+		return nil, err
+	}
+	return []versionComparison{{op: "=", version: version}}, nil
+}
+
+// matches reports whether the version satisfies any alternative of the constraint
+func (c versionConstraint) matches(version semver) bool {
+	for _, comparisons := range c {
+		satisfied := true
+		for _, comparison := range comparisons {
+			if !comparison.matches(version) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+func (c versionComparison) matches(version semver) bool {
+	cmp := version.compare(c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// satisfiesVersion reports whether version satisfies the constraint
+func satisfiesVersion(version, constraint string) (bool, error) {
+	parsed, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v, err := parseSemver(version)
+	if err != nil {
+		return false, err
+	}
+	return parsed.matches(v), nil
+}
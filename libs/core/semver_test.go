@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestSatisfiesVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.4.2", "", true},
+		{"1.4.2", "*", true},
+		{"1.2.0", ">=1.2.0", true},
+		{"1.1.9", ">=1.2.0", false},
+		{"1.9.0", "^1.0.0", true},
+		{"2.0.0", "^1.0.0", false},
+		{"0.2.5", "^0.2.1", true},
+		{"0.3.0", "^0.2.1", false},
+		{"1.2.9", "~1.2.0", true},
+		{"1.3.0", "~1.2.0", false},
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "=1.2.3", true},
+		{"1.2.4", "1.2.3", false},
+		{"1.7.0", "1.x", true},
+		{"1.2.7", "1.2.x", true},
+		{"1.3.0", "1.2.x", false},
+		{"3.1.0", "^1.0.0 || ^3.0.0", true},
+		{"2.1.0", "^1.0.0 || ^3.0.0", false},
+		{"1.2.0-beta", ">1.1.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			got, err := satisfiesVersion(tt.version, tt.constraint)
+			if err != nil {
+				t.Fatalf("satisfiesVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("satisfiesVersion(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfiesVersionInvalid(t *testing.T) {
+	for _, tt := range []struct{ version, constraint string }{
+		{"1.0.0", ">=one"},
+		{"1.0.0", "^"},
+		{"latest", ">=1.0.0"},
+		{"1.2.3.4", "*"},
+	} {
+		if _, err := satisfiesVersion(tt.version, tt.constraint); err == nil {
+			t.Errorf("satisfiesVersion(%q, %q) expected error", tt.version, tt.constraint)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamType is the reflect type of the stream parameter of SSE handlers
+var eventStreamType = reflect.TypeOf((*EventStream)(nil))
+
+// EventStream writes Server-Sent Events to the client, flushing after each event
+type EventStream struct {
+	c  *gin.Context
+	mu sync.Mutex
+}
+
+// newEventStream writes the event-stream headers and returns the stream
+func newEventStream(c *gin.Context) *EventStream {
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Disable proxy buffering (nginx) so events reach the client immediately
+	header.Set("X-Accel-Buffering", "no")
+
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	return &EventStream{c: c}
+}
+
+// Send writes one event and flushes it to the client
+// Strings and byte slices are sent as-is, other values as JSON; an empty event
+// name sends an unnamed "message" event
+// It returns the context error once the client has disconnected
+func (s *EventStream) Send(event string, data interface{}) error {
+	if err := s.Context().Err(); err != nil {
+		return err
+	}
+
+	payload, err := eventData(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event '%s': %w", event, err)
+	}
+
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	for _, line := range strings.Split(payload, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.c.Writer.WriteString(b.String()); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// Context returns the request context, cancelled when the client disconnects
+func (s *EventStream) Context() context.Context {
+	return s.c.Request.Context()
+}
+
+// Done is closed when the client disconnects
+func (s *EventStream) Done() <-chan struct{} {
+	return s.Context().Done()
+}
+
+// eventData renders an event payload
+func eventData(data interface{}) (string, error) {
+	switch v := data.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// SSE registers a Server-Sent Events route with automatic controller injection
+// The handler's first two parameters must be *gin.Context and *EventStream; every
+// other parameter is resolved like HTTP handlers,
+// e.g. func(c *gin.Context, stream *core.EventStream, feed *FeedController)
+// The stream ends when the handler returns
+func (r *EnhancedRouter) SSE(config RouteConfig, handler interface{}) {
+	prefixedPath := r.applyPrefix(config.Path)
+	config.Path = prefixedPath
+	config.Method = http.MethodGet
+	if config.Timeout == 0 {
+		// Long-lived connections do not inherit the app's request timeout
+		config.Timeout = NoTimeout
+	}
+
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.GET(prefixedPath, routeHandlers(config, r.withEventStream(handler))...)
+}
+
+// SSE registers a Server-Sent Events route in the group with automatic controller injection
+func (rg *EnhancedRouterGroup) SSE(config RouteConfig, handler interface{}) {
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodGet
+	if config.Timeout == 0 {
+		// Long-lived connections do not inherit the app's request timeout
+		config.Timeout = NoTimeout
+	}
+
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.GET(path, routeHandlers(config, rg.router.withEventStream(handler))...)
+}
+
+// withEventStream resolves the handler's dependencies, opens the event stream and calls the handler
+func (r *EnhancedRouter) withEventStream(handler interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handlerValue := reflect.ValueOf(handler)
+		handlerType := handlerValue.Type()
+
+		if handlerType.Kind() != reflect.Func || handlerType.NumIn() < 2 ||
+			handlerType.In(0) != reflect.TypeOf(c) || handlerType.In(1) != eventStreamType {
+			AbortWithError(c, ErrInternal.WithMessage("Invalid handler signature"))
+			return
+		}
+
+		// Resolve dependencies before streaming so failures still get a JSON response
+		args := make([]reflect.Value, handlerType.NumIn())
+		args[0] = reflect.ValueOf(c)
+		if !r.resolveHandlerArgs(c, handlerType, args, 2) || !runPreHandlerHooks(c) {
+			return
+		}
+
+		args[1] = reflect.ValueOf(newEventStream(c))
+		handlerValue.Call(args)
+	}
+}
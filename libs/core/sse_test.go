@@ -0,0 +1,71 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnhancedRouter_SSESendsEvents(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouterWithPrefix(app.GetEngine(), app.GetContainer(), "/feed")
+	router.SSE(RouteConfig{Path: "events"}, func(c *gin.Context, stream *EventStream, controller *routeTestController) {
+		require.NotNil(t, controller)
+		require.NoError(t, stream.Send("greeting", "hello\nworld"))
+		require.NoError(t, stream.Send("", map[string]int{"count": 1}))
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/feed/events", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "event: greeting\ndata: hello\ndata: world\n\ndata: {\"count\":1}\n\n", recorder.Body.String())
+}
+
+func TestEnhancedRouter_SSEStopsOnDisconnect(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	stopped := make(chan error, 1)
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.SSE(RouteConfig{Path: "/ticks"}, func(c *gin.Context, stream *EventStream) {
+		stream.Send("tick", "1")
+		<-stream.Done()
+		stopped <- stream.Send("tick", "2")
+	})
+
+	server := httptest.NewServer(app.GetEngine())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ticks", nil)
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	line, err := bufio.NewReader(response.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: tick\n", line)
+
+	cancel()
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not observe the client disconnect")
+	}
+}
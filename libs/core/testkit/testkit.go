@@ -0,0 +1,148 @@
+// Package testkit builds DoffApps for handler tests that serve requests in-process,
+// without binding a port or waiting for the server to start
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// App is an initialized DoffApp serving requests through its engine
+type App struct {
+	*core.DoffApp
+	tb testing.TB
+}
+
+// settings collects the Options of New
+type settings struct {
+	options   core.AppOptions
+	plugins   []core.Plugin
+	overrides []core.Provider
+	setup     []func(app *core.DoffApp)
+}
+
+// Option configures New
+type Option func(*settings)
+
+// WithAppOptions sets the options the app is created with; Mode is always gin.TestMode
+func WithAppOptions(options core.AppOptions) Option {
+	return func(s *settings) {
+		s.options = options
+	}
+}
+
+// WithPlugins registers plugins, in order, before the app is initialized
+func WithPlugins(plugins ...core.Plugin) Option {
+	return func(s *settings) {
+		s.plugins = append(s.plugins, plugins...)
+	}
+}
+
+// WithOverride replaces a service registered by the plugins, e.g. with a mock,
+// before async providers and eager singletons are built
+func WithOverride(provider core.Provider) Option {
+	return func(s *settings) {
+		s.overrides = append(s.overrides, provider)
+	}
+}
+
+// WithSetup runs fn after the plugins are registered and before the app is
+// initialized, e.g. to register routes or extra services
+func WithSetup(fn func(app *core.DoffApp)) Option {
+	return func(s *settings) {
+		s.setup = append(s.setup, fn)
+	}
+}
+
+// New creates an app in test mode, registers the plugins, applies the overrides and
+// setup functions, then initializes it synchronously; any failure fails the test
+// The app is shut down (PreClose, OnClose and plugin Shutdown) when the test ends
+// OnListen hooks never run, as nothing listens
+func New(tb testing.TB, opts ...Option) *App {
+	tb.Helper()
+
+	s := &settings{options: core.AppOptions{Name: tb.Name()}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.options.Mode = gin.TestMode
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&s.options).(*core.DoffApp)
+	for _, plugin := range s.plugins {
+		if err := app.RegisterPlugin(plugin); err != nil {
+			tb.Fatalf("testkit: failed to register plugin '%s': %v", plugin.Name(), err)
+		}
+	}
+	for _, provider := range s.overrides {
+		if err := app.GetContainer().OverrideProvider(provider); err != nil {
+			tb.Fatalf("testkit: failed to override '%s': %v", provider.GetName(), err)
+		}
+	}
+	for _, fn := range s.setup {
+		fn(app)
+	}
+
+	if err := app.Init(); err != nil {
+		tb.Fatalf("testkit: failed to initialize app: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := app.Shutdown(context.Background()); err != nil {
+			tb.Errorf("testkit: failed to shut down app: %v", err)
+		}
+	})
+
+	return &App{DoffApp: app, tb: tb}
+}
+
+// Handler returns the app's engine, for use with httptest.NewServer and the like
+func (a *App) Handler() http.Handler {
+	return a.GetEngine()
+}
+
+// Container returns the app's root DI container
+func (a *App) Container() core.DIContainer {
+	return a.GetContainer()
+}
+
+// Do serves the request in-process and returns the recorded response
+func (a *App) Do(request *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	a.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// Request serves a request with the given method and path; a non-nil body is
+// sent as is when it is an io.Reader, otherwise encoded as JSON
+func (a *App) Request(method, path string, body interface{}) *httptest.ResponseRecorder {
+	a.tb.Helper()
+
+	var reader io.Reader
+	isJSON := false
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			a.tb.Fatalf("testkit: failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+		isJSON = true
+	}
+
+	request := httptest.NewRequest(method, path, reader)
+	if isJSON {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return a.Do(request)
+}
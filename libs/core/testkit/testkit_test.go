@@ -0,0 +1,121 @@
+package testkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// greeter is the service the test plugin's route depends on
+type greeter interface {
+	Greet(name string) string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet(name string) string { return "hello " + name }
+
+type mockGreeter struct{}
+
+func (mockGreeter) Greet(name string) string { return "mocked " + name }
+
+// greeterPlugin provides the greeter asynchronously and serves it on /greet/:name
+type greeterPlugin struct {
+	core.BasePlugin
+	initialized bool
+	shutdown    bool
+}
+
+func (p *greeterPlugin) Name() string                { return "greeter" }
+func (p *greeterPlugin) Version() string             { return "1.0.0" }
+func (p *greeterPlugin) Hooks() []core.LifecycleHook { return nil }
+
+func (p *greeterPlugin) Module() *core.Module {
+	return core.NewModule("greeter", "1.0.0").WithProviders(
+		core.NewAsyncProvider("greeter", func(c core.DIContainer, ctx context.Context) (interface{}, error) {
+			return englishGreeter{}, nil
+		}, core.Singleton),
+	)
+}
+
+func (p *greeterPlugin) Register(container core.DIContainer) error {
+	for _, provider := range p.Module().Providers {
+		if err := container.RegisterProvider(provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *greeterPlugin) Init(app *core.DoffApp) error {
+	p.initialized = true
+	return nil
+}
+
+func (p *greeterPlugin) Routes(router *gin.Engine) error {
+	router.GET("/greet/:name", func(c *gin.Context) {
+		service, err := c.MustGet("container").(core.DIContainer).Resolve("greeter")
+		if err != nil {
+			core.AbortWithError(c, err)
+			return
+		}
+		c.String(http.StatusOK, service.(greeter).Greet(c.Param("name")))
+	})
+	return nil
+}
+
+func (p *greeterPlugin) Shutdown() error {
+	p.shutdown = true
+	return nil
+}
+
+func TestNew_InitializesPluginsWithoutListening(t *testing.T) {
+	plugin := &greeterPlugin{}
+
+	t.Run("app", func(t *testing.T) {
+		app := New(t, WithPlugins(plugin))
+		assert.True(t, plugin.initialized)
+
+		recorder := app.Request(http.MethodGet, "/greet/ana", nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "hello ana", recorder.Body.String())
+	})
+
+	assert.True(t, plugin.shutdown, "the app must be shut down when the test ends")
+}
+
+func TestNew_OverridesServices(t *testing.T) {
+	app := New(t,
+		WithPlugins(&greeterPlugin{}),
+		WithOverride(core.NewValueProvider("greeter", greeter(mockGreeter{}))),
+	)
+
+	recorder := app.Request(http.MethodGet, "/greet/ana", nil)
+	assert.Equal(t, "mocked ana", recorder.Body.String())
+}
+
+func TestApp_RequestEncodesJSONBodies(t *testing.T) {
+	type order struct {
+		Item string `json:"item"`
+	}
+
+	app := New(t, WithSetup(func(app *core.DoffApp) {
+		app.GetRouter().POST(core.RouteConfig{Path: "/orders"}, func(c *gin.Context, container core.DIContainer) {
+			var body order
+			if err := c.ShouldBindJSON(&body); err != nil {
+				core.AbortWithError(c, core.ErrBadRequest.WithCause(err))
+				return
+			}
+			c.JSON(http.StatusCreated, body)
+		})
+	}))
+
+	recorder := app.Request(http.MethodPost, "/orders", order{Item: "book"})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	assert.JSONEq(t, `{"item":"book"}`, recorder.Body.String())
+}
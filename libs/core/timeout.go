@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoTimeout disables AppOptions.RequestTimeout for a route when used as RouteConfig.Timeout
+const NoTimeout time.Duration = -1
+
+// ErrRouteTimeout is reported to OnError hooks when a route exceeds its timeout
+var ErrRouteTimeout = errors.New("route timeout exceeded")
+
+// ErrResolutionTimeout is reported to OnError hooks, and answered with 503, when a
+// route's timeout expires while resolving its handler's dependencies, e.g. behind a
+// slow async controller factory
+var ErrResolutionTimeout = errors.New("handler dependency resolution timed out")
+
+// timeoutHandler gives the rest of the route chain a context with the deadline
+// (c.Request.Context()), so context-aware work such as async provider resolution is
+// cancelled. Enforcement is cooperative: the handler is not interrupted, but anything it
+// writes after the deadline is discarded and the client gets a 504 instead; the timeout
+// is recorded with c.Error so OnError hooks see it
+func timeoutHandler(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.ResponseWriter.Written() || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+
+		if last := c.Errors.Last(); last != nil && errors.Is(last.Err, ErrResolutionTimeout) {
+			// The handler never ran: answer the error recorded while resolving, which
+			// the timeout writer discarded
+			respondWithError(c, last.Err, ToAPIError(last.Err))
+			return
+		}
+
+		AbortWithError(c, fmt.Errorf("%w: %s %s took longer than %s", ErrRouteTimeout, c.Request.Method, c.FullPath(), timeout))
+	}
+}
+
+// timeoutWriter drops writes that start after the deadline, leaving the response to timeoutHandler
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+// expired reports whether the deadline passed before anything was written
+func (w *timeoutWriter) expired() bool {
+	return !w.ResponseWriter.Written() && w.ctx.Err() != nil
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if !w.expired() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if !w.expired() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
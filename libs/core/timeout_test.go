@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteTimeout_SlowHandlerGets504(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+
+	app.GetRouter().GET(RouteConfig{Path: "/slow", Timeout: 20 * time.Millisecond}, func(c *gin.Context, container DIContainer) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusOK, gin.H{"status": "late"})
+	})
+	app.GetRouter().GET(RouteConfig{Path: "/fast", Timeout: time.Second}, func(c *gin.Context, container DIContainer) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "late")
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], ErrRouteTimeout)
+
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, hookErrs, 1)
+}
+
+func TestRouteTimeout_InheritsAppDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := CreateDoffApp(&AppOptions{
+		Name:           "timeout-test",
+		Mode:           gin.TestMode,
+		RequestTimeout: 20 * time.Millisecond,
+	}).(*DoffApp)
+
+	slow := func(c *gin.Context, container DIContainer) {
+		time.Sleep(50 * time.Millisecond)
+		c.Status(http.StatusOK)
+	}
+	app.GetRouter().GET(RouteConfig{Path: "/default"}, slow)
+	app.GetRouter().GET(RouteConfig{Path: "/unlimited", Timeout: NoTimeout}, slow)
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/default", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unlimited", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRouteTimeout_EnhancedRouterDeadline(t *testing.T) {
+	app := newLifecycleTestApp(t)
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		return &routeTestController{}, nil
+	})
+
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/deadline", Timeout: time.Second}, func(c *gin.Context, controller *routeTestController) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"deadline": hasDeadline})
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/deadline", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"deadline":true}`, recorder.Body.String())
+}
+
+func TestRouteTimeout_SlowControllerResolutionGets503(t *testing.T) {
+	app := newLifecycleTestApp(t)
+
+	var hookErrs []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrs = append(hookErrs, err)
+	}))
+
+	require.NoError(t, app.GetContainer().RegisterProvider(NewAsyncProvider("*core.routeTestController",
+		func(c DIContainer, ctx context.Context) (interface{}, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return &routeTestController{}, nil
+			}
+		}, Transient)))
+
+	called := false
+	router := NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(RouteConfig{Path: "/slow-controller", Timeout: 20 * time.Millisecond}, func(c *gin.Context, controller *routeTestController) {
+		called = true
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow-controller", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "service_unavailable")
+	assert.False(t, called)
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], ErrResolutionTimeout)
+	assert.ErrorIs(t, hookErrs[0], context.DeadlineExceeded)
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// wsConnType is the reflect type of the connection parameter of WebSocket handlers
+var wsConnType = reflect.TypeOf((*websocket.Conn)(nil))
+
+// WS registers a WebSocket route with automatic controller injection
+// The handler's first two parameters must be *gin.Context and *websocket.Conn; every
+// other parameter is resolved like HTTP handlers,
+// e.g. func(c *gin.Context, conn *websocket.Conn, chat *ChatController)
+// The connection is closed when the handler returns
+func (r *EnhancedRouter) WS(config RouteConfig, handler interface{}) {
+	prefixedPath := r.applyPrefix(config.Path)
+	config.Path = prefixedPath
+	config.Method = http.MethodGet
+	if config.Timeout == 0 {
+		// Long-lived connections do not inherit the app's request timeout
+		config.Timeout = NoTimeout
+	}
+
+	if !r.triggerOnRoute(&config) {
+		return
+	}
+	r.engine.GET(prefixedPath, routeHandlers(config, r.withWebSocket(handler))...)
+}
+
+// WS registers a WebSocket route in the group with automatic controller injection
+func (rg *EnhancedRouterGroup) WS(config RouteConfig, handler interface{}) {
+	path := config.Path
+	config.Path = joinRoutePath(rg.group.BasePath(), path)
+	config.Method = http.MethodGet
+	if config.Timeout == 0 {
+		// Long-lived connections do not inherit the app's request timeout
+		config.Timeout = NoTimeout
+	}
+
+	if !rg.router.triggerOnRoute(&config) {
+		return
+	}
+	rg.group.GET(path, routeHandlers(config, rg.router.withWebSocket(handler))...)
+}
+
+// withWebSocket resolves the handler's dependencies, upgrades the connection and calls the handler
+func (r *EnhancedRouter) withWebSocket(handler interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handlerValue := reflect.ValueOf(handler)
+		handlerType := handlerValue.Type()
+
+		if handlerType.Kind() != reflect.Func || handlerType.NumIn() < 2 ||
+			handlerType.In(0) != reflect.TypeOf(c) || handlerType.In(1) != wsConnType {
+			AbortWithError(c, ErrInternal.WithMessage("Invalid handler signature"))
+			return
+		}
+
+		// Resolve dependencies before upgrading so failures still get a JSON response
+		args := make([]reflect.Value, handlerType.NumIn())
+		args[0] = reflect.ValueOf(c)
+		if !r.resolveHandlerArgs(c, handlerType, args, 2) || !runPreHandlerHooks(c) {
+			return
+		}
+
+		upgrader := websocket.Upgrader{
+			CheckOrigin: r.websocketOriginChecker(c),
+			Error: func(w http.ResponseWriter, req *http.Request, status int, reason error) {
+				AbortWithError(c, websocketUpgradeError(status, reason))
+			},
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// The upgrader has already written the error response
+			return
+		}
+		defer conn.Close()
+
+		args[1] = reflect.ValueOf(conn)
+		handlerValue.Call(args)
+	}
+}
+
+// websocketOriginChecker returns an origin check backed by the app's CORS configuration
+// Without CORS configured, the upgrader's default same-origin check applies
+func (r *EnhancedRouter) websocketOriginChecker(c *gin.Context) func(*http.Request) bool {
+	container := r.container
+	if value, exists := c.Get("container"); exists {
+		if appContainer, ok := value.(DIContainer); ok {
+			container = appContainer
+		}
+	}
+	if container == nil {
+		return nil
+	}
+
+	instance, err := container.Resolve("corsService")
+	if err != nil {
+		return nil
+	}
+	corsService, ok := instance.(*CorsService)
+	if !ok {
+		return nil
+	}
+
+	return func(req *http.Request) bool {
+		origin := req.Header.Get("Origin")
+		// Non-browser clients send no Origin
+		return origin == "" || corsService.IsOriginAllowed(origin)
+	}
+}
+
+// websocketUpgradeError maps a failed upgrade to an API error with the upgrader's status;
+// server-side failures keep the generic 500 message
+func websocketUpgradeError(status int, reason error) *APIError {
+	switch status {
+	case http.StatusInternalServerError:
+		return ErrInternal.WithCause(reason)
+	case http.StatusBadRequest:
+		return ErrBadRequest.WithMessage(reason.Error()).WithCause(reason)
+	case http.StatusForbidden:
+		return ErrForbidden.WithMessage(reason.Error()).WithCause(reason)
+	}
+	return NewAPIError(status, "upgrade_failed", reason.Error()).WithCause(reason)
+}
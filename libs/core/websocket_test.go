@@ -0,0 +1,99 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWebSocketTestServer registers an echo WS route under the "chat" module prefix
+func newWebSocketTestServer(t *testing.T, cors *CorsOptions) (*httptest.Server, *int) {
+	t.Helper()
+	app := newLifecycleTestApp(t)
+
+	resolved := 0
+	app.GetContainer().RegisterTransient("*core.routeTestController", func(c DIContainer) (interface{}, error) {
+		resolved++
+		return &routeTestController{}, nil
+	})
+	if cors != nil {
+		app.GetContainer().RegisterSingleton("corsService", func(c DIContainer) (interface{}, error) {
+			return NewCorsService(cors), nil
+		})
+	}
+
+	router := NewEnhancedRouterWithPrefix(app.GetEngine(), app.GetContainer(), "/chat")
+	router.WS(RouteConfig{Path: "echo"}, func(c *gin.Context, conn *websocket.Conn, controller *routeTestController) {
+		require.NotNil(t, controller)
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(messageType, append([]byte("echo: "), message...))
+	})
+
+	server := httptest.NewServer(app.GetEngine())
+	t.Cleanup(server.Close)
+	return server, &resolved
+}
+
+func dialWebSocket(server *httptest.Server, path, origin string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, header)
+}
+
+func TestEnhancedRouter_WebSocketEcho(t *testing.T) {
+	server, resolved := newWebSocketTestServer(t, nil)
+
+	conn, _, err := dialWebSocket(server, "/chat/echo", "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "echo: hello", string(message))
+	assert.Equal(t, 1, *resolved)
+}
+
+func TestEnhancedRouter_WebSocketUpgradeFailure(t *testing.T) {
+	server, _ := newWebSocketTestServer(t, nil)
+
+	// A plain GET cannot be upgraded and gets the standard JSON error
+	response, err := http.Get(server.URL + "/chat/echo")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Header.Get("Content-Type"), "application/json")
+
+	var body struct {
+		Error APIError `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, "bad_request", body.Error.Code)
+	assert.NotEmpty(t, body.Error.Message)
+}
+
+func TestEnhancedRouter_WebSocketOriginUsesCors(t *testing.T) {
+	server, _ := newWebSocketTestServer(t, &CorsOptions{AllowOrigins: []string{"https://app.example.com"}})
+
+	conn, _, err := dialWebSocket(server, "/chat/echo", "https://app.example.com")
+	require.NoError(t, err)
+	conn.Close()
+
+	_, response, err := dialWebSocket(server, "/chat/echo", "https://evil.com")
+	require.Error(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// Token errors
+var (
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenUnverifiable     = errors.New("token signing algorithm is not accepted")
+	ErrTokenSignatureInvalid = errors.New("token signature is invalid")
+	ErrTokenExpired          = errors.New("token is expired")
+	ErrTokenNotValidYet      = errors.New("token is not valid yet")
+)
+
+// Claims are the registered JWT claims plus the scopes and roles used for authorization
+type Claims struct {
+	Subject   string   `json:"sub,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Scopes    []string `json:"-"`
+	Roles     []string `json:"roles,omitempty"`
+
+	// Raw holds every claim in the payload, including custom ones
+	Raw map[string]interface{} `json:"-"`
+}
+
+// UserID returns the subject of the token
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+// HasScope reports whether the token grants the given scope
+func (c *Claims) HasScope(scope string) bool {
+	return contains(c.Scopes, scope)
+}
+
+// HasRole reports whether the token carries the given role
+func (c *Claims) HasRole(role string) bool {
+	return contains(c.Roles, role)
+}
+
+// JWTAuthenticator validates HS256 or RS256 signed tokens
+// The algorithm is fixed by the key it is constructed with; tokens signed with any other
+// algorithm are rejected
+type JWTAuthenticator struct {
+	algorithm string
+	secret    []byte
+	publicKey *rsa.PublicKey
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+// JWTOption configures a JWTAuthenticator
+type JWTOption func(*JWTAuthenticator)
+
+// WithLeeway allows for clock skew when checking exp and nbf
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.leeway = leeway
+	}
+}
+
+// NewHS256Authenticator creates an authenticator for tokens signed with the shared secret
+func NewHS256Authenticator(secret []byte, opts ...JWTOption) *JWTAuthenticator {
+	return newJWTAuthenticator(&JWTAuthenticator{
+		algorithm: AlgorithmHS256,
+		secret:    secret,
+	}, opts)
+}
+
+// NewRS256Authenticator creates an authenticator for tokens signed with the matching private key
+func NewRS256Authenticator(publicKey *rsa.PublicKey, opts ...JWTOption) *JWTAuthenticator {
+	return newJWTAuthenticator(&JWTAuthenticator{
+		algorithm: AlgorithmRS256,
+		publicKey: publicKey,
+	}, opts)
+}
+
+func newJWTAuthenticator(a *JWTAuthenticator, opts []JWTOption) *JWTAuthenticator {
+	a.now = time.Now
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authenticate implements core.Authenticator
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (bool, error) {
+	if _, err := a.Parse(token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ParseClaims implements core.ClaimsAuthenticator so handlers receive *Claims in the AuthResult
+func (a *JWTAuthenticator) ParseClaims(ctx context.Context, token string) (interface{}, error) {
+	return a.Parse(token)
+}
+
+// Assert validates the token and checks the scopes and roles required by the context
+// (see WithRequiredScopes and WithRequiredRoles); a valid token missing one returns false, nil
+func (a *JWTAuthenticator) Assert(ctx context.Context, token string) (bool, error) {
+	claims, err := a.Parse(token)
+	if err != nil {
+		return false, err
+	}
+
+	for _, scope := range requiredScopes(ctx) {
+		if !claims.HasScope(scope) {
+			return false, nil
+		}
+	}
+	for _, role := range requiredRoles(ctx) {
+		if !claims.HasRole(role) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Parse verifies the token signature and time claims and returns its claims
+func (a *JWTAuthenticator) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Algorithm != a.algorithm {
+		return nil, fmt.Errorf("%w: got '%s', want '%s'", ErrTokenUnverifiable, header.Algorithm, a.algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	if err := a.verify(parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	if err := decodeSegment(parts[1], &claims.Raw); err != nil {
+		return nil, err
+	}
+	claims.Scopes = scopesFromRaw(claims.Raw)
+
+	now := a.now()
+	if claims.ExpiresAt != 0 && !now.Before(time.Unix(claims.ExpiresAt, 0).Add(a.leeway)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(a.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrTokenNotValidYet
+	}
+
+	return claims, nil
+}
+
+// verify checks the signature over the signing input with the configured key
+func (a *JWTAuthenticator) verify(signingInput string, signature []byte) error {
+	switch a.algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, a.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrTokenSignatureInvalid
+		}
+	case AlgorithmRS256:
+		if a.publicKey == nil {
+			return fmt.Errorf("%w: no public key configured", ErrTokenUnverifiable)
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(a.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrTokenSignatureInvalid
+		}
+	default:
+		return ErrTokenUnverifiable
+	}
+	return nil
+}
+
+// GetClaims returns the claims of the authenticated request
+func GetClaims(c *gin.Context) (*Claims, bool) {
+	result, ok := core.GetAuthResult(c)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := result.Claims.(*Claims)
+	return claims, ok
+}
+
+type requiredScopesKey struct{}
+type requiredRolesKey struct{}
+
+// WithRequiredScopes returns a context that makes Assert require all the given scopes
+func WithRequiredScopes(ctx context.Context, scopes ...string) context.Context {
+	return context.WithValue(ctx, requiredScopesKey{}, scopes)
+}
+
+// WithRequiredRoles returns a context that makes Assert require all the given roles
+func WithRequiredRoles(ctx context.Context, roles ...string) context.Context {
+	return context.WithValue(ctx, requiredRolesKey{}, roles)
+}
+
+func requiredScopes(ctx context.Context) []string {
+	scopes, _ := ctx.Value(requiredScopesKey{}).([]string)
+	return scopes
+}
+
+func requiredRoles(ctx context.Context) []string {
+	roles, _ := ctx.Value(requiredRolesKey{}).([]string)
+	return roles
+}
+
+// scopesFromRaw reads the "scope" claim (space separated string, per RFC 8693)
+// or the "scp" claim (array), whichever is present
+func scopesFromRaw(raw map[string]interface{}) []string {
+	if scope, ok := raw["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+
+	var scopes []string
+	if values, ok := raw["scp"].([]interface{}); ok {
+		for _, value := range values {
+			if scope, ok := value.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// decodeSegment base64url-decodes a token segment and unmarshals its JSON
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test-secret")
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": AlgorithmHS256, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	signingInput := encodeSegment(t, map[string]string{"alg": AlgorithmRS256, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-42",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "users:read users:write",
+		"roles": []string{"admin"},
+	}
+}
+
+func TestJWTAuthenticator_HS256(t *testing.T) {
+	authenticator := NewHS256Authenticator(testSecret)
+
+	claims, err := authenticator.Parse(signHS256(t, testSecret, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", claims.UserID())
+	assert.Equal(t, []string{"users:read", "users:write"}, claims.Scopes)
+	assert.True(t, claims.HasRole("admin"))
+}
+
+func TestJWTAuthenticator_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator := NewRS256Authenticator(&key.PublicKey)
+
+	claims, err := authenticator.Parse(signRS256(t, key, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", claims.Subject)
+
+	_, err = authenticator.Parse(signRS256(t, otherKey, validClaims()))
+	assert.ErrorIs(t, err, ErrTokenSignatureInvalid)
+
+	// An HS256 token must not be accepted by an RS256 authenticator
+	_, err = authenticator.Parse(signHS256(t, testSecret, validClaims()))
+	assert.ErrorIs(t, err, ErrTokenUnverifiable)
+}
+
+func TestJWTAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	authenticator := NewHS256Authenticator(testSecret)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	notYetValid := validClaims()
+	notYetValid["nbf"] = time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"expired", signHS256(t, testSecret, expired), ErrTokenExpired},
+		{"not valid yet", signHS256(t, testSecret, notYetValid), ErrTokenNotValidYet},
+		{"bad signature", signHS256(t, []byte("other-secret"), validClaims()), ErrTokenSignatureInvalid},
+		{"malformed", "not-a-token", ErrTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := authenticator.Authenticate(context.Background(), tt.token)
+			assert.False(t, ok)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestJWTAuthenticator_Leeway(t *testing.T) {
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	token := signHS256(t, testSecret, claims)
+
+	_, err := NewHS256Authenticator(testSecret).Parse(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+
+	_, err = NewHS256Authenticator(testSecret, WithLeeway(time.Minute)).Parse(token)
+	assert.NoError(t, err)
+}
+
+func TestJWTAuthenticator_Assert(t *testing.T) {
+	authenticator := NewHS256Authenticator(testSecret)
+	token := signHS256(t, testSecret, validClaims())
+
+	ok, err := authenticator.Assert(WithRequiredScopes(context.Background(), "users:read"), token)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = authenticator.Assert(WithRequiredScopes(context.Background(), "users:delete"), token)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = authenticator.Assert(WithRequiredRoles(context.Background(), "admin"), token)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = authenticator.Assert(WithRequiredRoles(context.Background(), "owner"), token)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestJWTAuthenticator_ClaimsReachHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{
+		Name:          "jwt-test",
+		Mode:          gin.TestMode,
+		Authenticator: NewHS256Authenticator(testSecret),
+	}).(*core.DoffApp)
+
+	app.GetRouter().GET(core.RouteConfig{Path: "/me"}, func(c *gin.Context, container core.DIContainer) {
+		claims, ok := GetClaims(c)
+		require.True(t, ok)
+		c.String(http.StatusOK, claims.UserID())
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/me", nil)
+	request.Header.Set("Authorization", "Bearer "+signHS256(t, testSecret, validClaims()))
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "user-42", recorder.Body.String())
+
+	request = httptest.NewRequest(http.MethodGet, "/me", nil)
+	request.Header.Set("Authorization", "Bearer "+signHS256(t, []byte("other-secret"), validClaims()))
+	recorder = httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
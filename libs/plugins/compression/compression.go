@@ -0,0 +1,338 @@
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMinSize is the smallest response body compressed by default; smaller
+// bodies gain little and cost CPU
+const DefaultMinSize = 1024
+
+// DefaultContentTypes are the media types compressed by default
+var DefaultContentTypes = []string{
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/css",
+	"text/html",
+	"text/javascript",
+	"text/plain",
+	"text/xml",
+}
+
+// writerKey stores the request's gzip writer in the gin context
+const writerKey = "doffy.compressionWriter"
+
+// CompressionPlugin gzips responses for clients sending "Accept-Encoding: gzip"
+// Responses that are already encoded, smaller than the minimum size, of a type
+// outside the allowlist, Server-Sent Events or WebSocket upgrades are left alone
+type CompressionPlugin struct {
+	core.BasePlugin
+
+	minSize      int
+	level        int
+	contentTypes map[string]bool
+	pool         sync.Pool
+	err          error
+}
+
+// Option configures a CompressionPlugin
+type Option func(*CompressionPlugin)
+
+// WithMinSize sets the smallest body size, in bytes, that is compressed
+func WithMinSize(size int) Option {
+	return func(p *CompressionPlugin) {
+		p.minSize = size
+	}
+}
+
+// WithLevel sets the gzip compression level, from gzip.BestSpeed to gzip.BestCompression
+func WithLevel(level int) Option {
+	return func(p *CompressionPlugin) {
+		p.level = level
+	}
+}
+
+// WithContentTypes replaces the allowlist of media types to compress
+func WithContentTypes(contentTypes ...string) Option {
+	return func(p *CompressionPlugin) {
+		p.contentTypes = make(map[string]bool, len(contentTypes))
+		for _, contentType := range contentTypes {
+			p.contentTypes[strings.ToLower(contentType)] = true
+		}
+	}
+}
+
+// NewCompressionPlugin creates a compression plugin using DefaultMinSize,
+// DefaultContentTypes and gzip.DefaultCompression
+func NewCompressionPlugin(opts ...Option) *CompressionPlugin {
+	p := &CompressionPlugin{
+		minSize: DefaultMinSize,
+		level:   gzip.DefaultCompression,
+	}
+	WithContentTypes(DefaultContentTypes...)(p)
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// Validate the level once; Register reports the error
+	if _, err := gzip.NewWriterLevel(io.Discard, p.level); err != nil {
+		p.err = fmt.Errorf("invalid compression level %d: %w", p.level, err)
+	}
+	p.pool.New = func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, p.level)
+		return w
+	}
+	return p
+}
+
+func (p *CompressionPlugin) Name() string {
+	return "compression"
+}
+
+func (p *CompressionPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *CompressionPlugin) Register(container core.DIContainer) error {
+	return p.err
+}
+
+func (p *CompressionPlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&CompressionHook{plugin: p},
+	}
+}
+
+// CompressionHook wraps the response writer in OnRequest; the framework finishes
+// the gzip stream (see core.ResponseFinisher) before OnResponse
+type CompressionHook struct {
+	plugin *CompressionPlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *CompressionHook) OnRequest(c *gin.Context) {
+	if !acceptsGzip(c.GetHeader("Accept-Encoding")) || isStreamingRequest(c.Request) {
+		return
+	}
+
+	writer := &gzipWriter{ResponseWriter: c.Writer, plugin: h.plugin}
+	c.Set(writerKey, writer)
+	c.Writer = writer
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *CompressionHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *CompressionHook) OnResponse(c *gin.Context, response interface{}) {
+	if writer, ok := requestWriter(c); ok {
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// OnError implements core.LifecycleHook
+// When nothing was written yet, the error response (or the panic recovery
+// response, which runs without OnResponse) is sent uncompressed
+func (h *CompressionHook) OnError(c *gin.Context, err error) {
+	if writer, ok := requestWriter(c); ok && !writer.Written() {
+		writer.disable()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// requestWriter returns the gzip writer installed for the request
+func requestWriter(c *gin.Context) (*gzipWriter, bool) {
+	value, exists := c.Get(writerKey)
+	if !exists {
+		return nil, false
+	}
+	writer, ok := value.(*gzipWriter)
+	return writer, ok
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		// "gzip;q=0" explicitly refuses gzip
+		if _, q, found := strings.Cut(params, "q="); found {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(q), 64); err == nil && value == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isStreamingRequest reports WebSocket upgrades and Server-Sent Events requests
+func isStreamingRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// gzipWriter buffers the start of the body until it knows whether compressing is
+// worthwhile, then either gzips or passes the body through unchanged
+type gzipWriter struct {
+	gin.ResponseWriter
+
+	plugin      *CompressionPlugin
+	buffer      []byte
+	decided     bool
+	compressing bool
+	gz          *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buffer = append(w.buffer, data...)
+		if len(w.buffer) < w.plugin.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.compressing {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow commits the headers; a body written later is not compressed
+func (w *gzipWriter) WriteHeaderNow() {
+	if !w.decided && len(w.buffer) == 0 {
+		w.disable()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written reports buffered bodies as written, so nothing else renders a second response
+func (w *gzipWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends buffered data; streamed responses are decided on the first flush
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.compressing {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Finish implements core.ResponseFinisher: short bodies are sent as-is, compressed
+// ones get the gzip trailer, then a writer beneath that buffers too is finished
+func (w *gzipWriter) Finish() error {
+	err := w.finishGzip()
+	if finisher, ok := w.ResponseWriter.(core.ResponseFinisher); ok {
+		if finishErr := finisher.Finish(); err == nil {
+			err = finishErr
+		}
+	}
+	return err
+}
+
+// finishGzip writes what is buffered and closes the gzip stream
+func (w *gzipWriter) finishGzip() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if !w.compressing {
+		return nil
+	}
+
+	err := w.gz.Close()
+	w.gz.Reset(io.Discard)
+	w.plugin.pool.Put(w.gz)
+	w.gz, w.compressing = nil, false
+	return err
+}
+
+// disable passes the response through uncompressed
+func (w *gzipWriter) disable() {
+	w.decided = true
+}
+
+// decide picks compression from the response headers and status, then writes the buffer
+func (w *gzipWriter) decide() error {
+	w.decided = true
+	if w.shouldCompress() {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+
+		w.gz = w.plugin.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		w.compressing = true
+	}
+
+	buffered := w.buffer
+	w.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	var err error
+	if w.compressing {
+		_, err = w.gz.Write(buffered)
+	} else {
+		_, err = w.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+// shouldCompress reports whether the buffered response qualifies for compression
+func (w *gzipWriter) shouldCompress() bool {
+	if len(w.buffer) < w.plugin.minSize || w.ResponseWriter.Written() {
+		return false
+	}
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		status == http.StatusPartialContent {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		// Already encoded by the handler
+		return false
+	}
+	if header.Get("Content-Range") != "" {
+		// Byte ranges refer to the uncompressed body
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buffer)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && w.plugin.contentTypes[mediaType]
+}
@@ -0,0 +1,191 @@
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var largeText = strings.Repeat("doffy compresses repetitive payloads well. ", 100)
+
+func newCompressionTestApp(tb testing.TB, plugin *CompressionPlugin) *core.DoffApp {
+	tb.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{Name: "compression-test", Mode: gin.TestMode}).(*core.DoffApp)
+	if plugin != nil {
+		require.NoError(tb, app.RegisterPlugin(plugin))
+	}
+
+	engine := app.GetEngine()
+	engine.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": largeText})
+	})
+	engine.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": "short"})
+	})
+	engine.GET("/binary", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte(largeText))
+	})
+	engine.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "br")
+		c.Data(http.StatusOK, "text/plain", []byte(largeText))
+	})
+	engine.GET("/partial", func(c *gin.Context) {
+		c.Header("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(largeText)-1, len(largeText)*2))
+		c.Data(http.StatusPartialContent, "text/plain", []byte(largeText))
+	})
+	engine.GET("/ranged", func(c *gin.Context) {
+		c.Header("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(largeText)-1, len(largeText)))
+		c.Data(http.StatusOK, "text/plain", []byte(largeText))
+	})
+	engine.GET("/fail", func(c *gin.Context) {
+		c.Error(core.ErrNotFound)
+	})
+	return app
+}
+
+func serve(app *core.DoffApp, path, acceptEncoding string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCompressionPlugin_GzipsLargeResponses(t *testing.T) {
+	app := newCompressionTestApp(t, NewCompressionPlugin())
+
+	recorder := serve(app, "/large", "deflate, gzip;q=0.8")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+	assert.Less(t, recorder.Body.Len(), len(largeText))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), largeText)
+}
+
+func TestCompressionPlugin_SkipsIneligibleResponses(t *testing.T) {
+	app := newCompressionTestApp(t, NewCompressionPlugin())
+
+	tests := []struct {
+		name, path, acceptEncoding string
+	}{
+		{"client without gzip", "/large", ""},
+		{"gzip refused", "/large", "gzip;q=0"},
+		{"below minimum size", "/small", "gzip"},
+		{"content type not allowed", "/binary", "gzip"},
+		{"already encoded", "/encoded", "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(app, tt.path, tt.acceptEncoding)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.NotEqual(t, "gzip", recorder.Header().Get("Content-Encoding"))
+			assert.NotContains(t, recorder.Body.String(), "\x1f\x8b")
+		})
+	}
+}
+
+func TestCompressionPlugin_SkipsRangeResponses(t *testing.T) {
+	app := newCompressionTestApp(t, NewCompressionPlugin())
+
+	tests := []struct {
+		name, path string
+		status     int
+	}{
+		{"partial content", "/partial", http.StatusPartialContent},
+		{"content range header", "/ranged", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(app, tt.path, "gzip")
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+			assert.NotEmpty(t, recorder.Header().Get("Content-Range"))
+			assert.Equal(t, largeText, recorder.Body.String())
+		})
+	}
+}
+
+func TestCompressionPlugin_ErrorEnvelopeIsNotCompressed(t *testing.T) {
+	app := newCompressionTestApp(t, NewCompressionPlugin(WithMinSize(0)))
+
+	recorder := serve(app, "/fail", "gzip")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Contains(t, recorder.Body.String(), `"code":"not_found"`)
+}
+
+func TestCompressionPlugin_OnResponseSeesCompressedSize(t *testing.T) {
+	app := newCompressionTestApp(t, NewCompressionPlugin())
+
+	var size int
+	app.GetPluginManager().GetLifecycleManager().AddHook(core.NewOnResponseHook(func(c *gin.Context, response interface{}) {
+		size = response.(*core.ResponseInfo).Size
+	}))
+
+	recorder := serve(app, "/large", "gzip")
+	assert.Equal(t, recorder.Body.Len(), size)
+}
+
+func TestCompressionPlugin_InvalidLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := core.CreateDoffApp(&core.AppOptions{Name: "compression-test", Mode: gin.TestMode}).(*core.DoffApp)
+
+	err := NewCompressionPlugin(WithLevel(42)).Register(app.GetContainer())
+	assert.ErrorContains(t, err, "invalid compression level 42")
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"gzip":                true,
+		"br, GZIP":            true,
+		"*":                   true,
+		"gzip;q=0, br":        false,
+		"identity":            false,
+		"":                    false,
+		"deflate, gzip;q=0.5": true,
+	} {
+		assert.Equal(t, want, acceptsGzip(header), strconv.Quote(header))
+	}
+}
+
+func benchmarkResponses(b *testing.B, plugin *CompressionPlugin) {
+	app := newCompressionTestApp(b, plugin)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		serve(app, "/large", "gzip")
+	}
+}
+
+func BenchmarkResponse_Uncompressed(b *testing.B) {
+	benchmarkResponses(b, nil)
+}
+
+func BenchmarkResponse_GzipDefault(b *testing.B) {
+	benchmarkResponses(b, NewCompressionPlugin())
+}
+
+func BenchmarkResponse_GzipBestSpeed(b *testing.B) {
+	benchmarkResponses(b, NewCompressionPlugin(WithLevel(gzip.BestSpeed)))
+}
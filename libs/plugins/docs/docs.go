@@ -0,0 +1,172 @@
+package docs
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPath is where the Swagger UI page is served
+const DefaultPath = "/docs"
+
+// DefaultAssetsURL serves the Swagger UI bundle and stylesheet the page loads
+// Point WithAssetsURL at a self-hosted copy of swagger-ui-dist for offline use
+const DefaultAssetsURL = "https://unpkg.com/swagger-ui-dist@5"
+
+// static holds the page and the script starting Swagger UI; importing this package
+// is what brings them into the binary
+//
+//go:embed static
+var static embed.FS
+
+var (
+	pageTemplate = template.Must(template.ParseFS(static, "static/index.html"))
+	initScript   = mustReadStatic("static/init.js")
+)
+
+func mustReadStatic(name string) []byte {
+	data, err := static.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Settings configure the plugin from its AppOptions.Plugins entry, e.g.
+// {Name: "docs", Config: map[string]interface{}{"enabled": false}}
+// Options passed to NewDocsPlugin are the defaults of absent keys
+type Settings struct {
+	Enabled   bool   `json:"enabled"`
+	Path      string `json:"path" binding:"required,startswith=/"`
+	AssetsURL string `json:"assetsURL" binding:"required"`
+}
+
+// DocsPlugin serves Swagger UI for the app's generated OpenAPI document (see
+// core.DoffApp.OpenAPIDocument): the routes registered through the app's routers,
+// their SchemaValidator bodies and which of them require authentication
+// It also serves core.OpenAPIPath when AppOptions.OpenAPI does not; both routes are
+// public
+type DocsPlugin struct {
+	core.BasePlugin
+
+	settings Settings
+}
+
+// Option configures a DocsPlugin
+type Option func(*DocsPlugin)
+
+// WithEnabled turns the docs on or off, e.g. from a flag to keep them out of production
+func WithEnabled(enabled bool) Option {
+	return func(p *DocsPlugin) {
+		p.settings.Enabled = enabled
+	}
+}
+
+// WithPath sets the path the Swagger UI page is served on
+func WithPath(path string) Option {
+	return func(p *DocsPlugin) {
+		p.settings.Path = path
+	}
+}
+
+// WithAssetsURL sets the base URL of swagger-ui-bundle.js and swagger-ui.css
+func WithAssetsURL(url string) Option {
+	return func(p *DocsPlugin) {
+		p.settings.AssetsURL = url
+	}
+}
+
+// NewDocsPlugin creates an enabled docs plugin serving DefaultPath
+func NewDocsPlugin(opts ...Option) *DocsPlugin {
+	p := &DocsPlugin{
+		settings: Settings{Enabled: true, Path: DefaultPath, AssetsURL: DefaultAssetsURL},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *DocsPlugin) Name() string {
+	return "docs"
+}
+
+func (p *DocsPlugin) Version() string {
+	return "1.0.0"
+}
+
+// Configure implements core.ConfigurablePlugin
+func (p *DocsPlugin) Configure(cm core.ConfigManager) error {
+	if err := core.BindConfig(cm, &p.settings); err != nil {
+		return err
+	}
+	p.settings.Path = strings.TrimSuffix(p.settings.Path, "/")
+	p.settings.AssetsURL = strings.TrimSuffix(p.settings.AssetsURL, "/")
+	return nil
+}
+
+func (p *DocsPlugin) Register(container core.DIContainer) error {
+	return nil
+}
+
+func (p *DocsPlugin) Hooks() []core.LifecycleHook {
+	return nil
+}
+
+// Init registers the docs routes, once the app's routes are known
+func (p *DocsPlugin) Init(app *core.DoffApp) error {
+	if !p.settings.Enabled {
+		return nil
+	}
+
+	public := false
+	router := app.GetRouter()
+	if !servesOpenAPI(app) {
+		router.GET(core.RouteConfig{Path: core.OpenAPIPath, IsAuth: &public}, func(c *gin.Context, container core.DIContainer) {
+			c.JSON(http.StatusOK, app.OpenAPIDocument())
+		})
+	}
+
+	page, err := p.renderPage(app.OpenAPIDocument().Info.Title)
+	if err != nil {
+		return err
+	}
+	router.GET(core.RouteConfig{Path: p.settings.Path, IsAuth: &public}, func(c *gin.Context, container core.DIContainer) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	})
+	router.GET(core.RouteConfig{Path: p.settings.Path + "/init.js", IsAuth: &public}, func(c *gin.Context, container core.DIContainer) {
+		c.Data(http.StatusOK, "text/javascript; charset=utf-8", initScript)
+	})
+	return nil
+}
+
+// renderPage renders the Swagger UI page once; it only depends on the settings
+func (p *DocsPlugin) renderPage(title string) ([]byte, error) {
+	var page strings.Builder
+	err := pageTemplate.Execute(&page, map[string]string{
+		"Title":     title,
+		"Path":      p.settings.Path,
+		"AssetsURL": p.settings.AssetsURL,
+		"SpecURL":   core.OpenAPIPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render docs page: %w", err)
+	}
+	return []byte(page.String()), nil
+}
+
+// servesOpenAPI reports whether the app already serves the OpenAPI document
+// (AppOptions.OpenAPI)
+func servesOpenAPI(app *core.DoffApp) bool {
+	for _, route := range app.GetRoutes() {
+		if route.Path == core.OpenAPIPath && (route.Method == http.MethodGet || route.Method == core.MethodAny) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createOrder struct {
+	Item string `json:"item" binding:"required"`
+}
+
+func newDocsTestApp(t *testing.T, options *core.AppOptions, plugin *DocsPlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	options.Name = "shop"
+	options.Mode = gin.TestMode
+	app := core.CreateDoffApp(options).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	noop := func(c *gin.Context, container core.DIContainer) {}
+	router := app.GetRouter()
+	router.GET(core.RouteConfig{Path: "/users/:id"}, noop)
+	router.POST(core.RouteConfig{Path: "/orders", SchemaValidator: &createOrder{}}, noop)
+
+	require.NoError(t, app.Init())
+	return app
+}
+
+func serve(app *core.DoffApp, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestDocsPlugin_ServesSwaggerUIAndDocument(t *testing.T) {
+	app := newDocsTestApp(t, &core.AppOptions{}, NewDocsPlugin(WithAssetsURL("/assets/swagger/")))
+
+	recorder := serve(app, "/docs")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	page := recorder.Body.String()
+	assert.Contains(t, page, "<title>shop API docs</title>")
+	assert.Contains(t, page, `data-spec-url="/openapi.json"`)
+	assert.Contains(t, page, `src="/assets/swagger/swagger-ui-bundle.js"`)
+	assert.Contains(t, page, `src="/docs/init.js"`)
+
+	recorder = serve(app, "/docs/init.js")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "SwaggerUIBundle")
+
+	recorder = serve(app, core.OpenAPIPath)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var document core.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+	assert.Equal(t, "shop", document.Info.Title)
+	require.Contains(t, document.Paths, "/users/{id}")
+	assert.Contains(t, document.Paths["/users/{id}"], "get")
+	require.Contains(t, document.Paths, "/orders")
+	require.NotNil(t, document.Paths["/orders"]["post"].RequestBody)
+	assert.Equal(t, []string{"item"}, document.Paths["/orders"]["post"].RequestBody.Content["application/json"].Schema.Required)
+}
+
+func TestDocsPlugin_UsesAppOpenAPIRoute(t *testing.T) {
+	app := newDocsTestApp(t, &core.AppOptions{OpenAPI: true}, NewDocsPlugin())
+
+	assert.NoError(t, app.GetPluginManager().RouteConflicts())
+	assert.Equal(t, http.StatusOK, serve(app, core.OpenAPIPath).Code)
+	assert.Contains(t, serve(app, "/docs").Body.String(), DefaultAssetsURL+"/swagger-ui.css")
+}
+
+func TestDocsPlugin_EmbedsOnlyPageAndInitScript(t *testing.T) {
+	// The Swagger UI bundle is not vendored, so the default page must load it from
+	// DefaultAssetsURL rather than from the package
+	entries, err := static.ReadDir("static")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"index.html", "init.js"}, names)
+
+	app := newDocsTestApp(t, &core.AppOptions{}, NewDocsPlugin())
+	assert.Contains(t, serve(app, "/docs").Body.String(), `src="`+DefaultAssetsURL+`/swagger-ui-bundle.js"`)
+	assert.Equal(t, http.StatusNotFound, serve(app, "/docs/assets/swagger-ui-bundle.js").Code)
+}
+
+func TestDocsPlugin_DisabledByConfig(t *testing.T) {
+	app := newDocsTestApp(t, &core.AppOptions{
+		Plugins: []core.PluginConfig{{Name: "docs", Config: map[string]interface{}{"enabled": false}}},
+	}, NewDocsPlugin())
+
+	assert.Equal(t, http.StatusNotFound, serve(app, "/docs").Code)
+	assert.Equal(t, http.StatusNotFound, serve(app, core.OpenAPIPath).Code)
+}
+
+func TestDocsPlugin_RejectsInvalidPath(t *testing.T) {
+	app := core.CreateDoffApp(&core.AppOptions{
+		Name:    "shop",
+		Mode:    gin.TestMode,
+		Plugins: []core.PluginConfig{{Name: "docs", Config: map[string]interface{}{"path": "docs"}}},
+	})
+
+	assert.ErrorIs(t, app.RegisterPlugin(NewDocsPlugin()), core.ErrPluginConfigInvalid)
+}
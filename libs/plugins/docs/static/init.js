@@ -0,0 +1,10 @@
+// Starts Swagger UI on the document the page's data-spec-url points to
+window.addEventListener("load", function () {
+  var element = document.getElementById("swagger-ui");
+  window.ui = SwaggerUIBundle({
+    url: element.dataset.specUrl,
+    domNode: element,
+    deepLinking: true,
+    persistAuthorization: true
+  });
+});
@@ -0,0 +1,265 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// OptionKey opts a route out of the envelope through RouteConfig.Options, e.g.
+// RouteConfig{Options: map[string]interface{}{envelope.OptionKey: false}}
+const OptionKey = "envelope"
+
+// Priority runs the plugin's hooks after those of default priority plugins, so its
+// writer wraps theirs (e.g. compression) and they see the enveloped body
+const Priority = 100
+
+// writerKey stores the request's envelope writer in the gin context
+const writerKey = "doffy.envelopeWriter"
+
+// Envelope is the default envelope, e.g. {"success": true, "data": {...}, "version": "v1"}
+type Envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Version string          `json:"version,omitempty"`
+}
+
+// EnvelopeFunc builds the envelope of a successful JSON response from its status and
+// the JSON the handler wrote; the result is serialized as JSON
+type EnvelopeFunc func(c *gin.Context, status int, data json.RawMessage) interface{}
+
+// EnvelopePlugin wraps the JSON bodies of successful (2xx) responses in an envelope,
+// so handlers write c.JSON(http.StatusOK, data) and the envelope is applied centrally
+// Non-JSON, encoded and error responses, and routes with OptionKey set to false, are
+// left alone
+type EnvelopePlugin struct {
+	core.BasePlugin
+
+	version  string
+	envelope EnvelopeFunc
+
+	mu       sync.RWMutex
+	disabled map[string]bool // "METHOD:path" of the routes opted out with OptionKey
+}
+
+// Option configures an EnvelopePlugin
+type Option func(*EnvelopePlugin)
+
+// WithVersion sets the Version of the default envelope
+func WithVersion(version string) Option {
+	return func(p *EnvelopePlugin) {
+		p.version = version
+	}
+}
+
+// WithEnvelope replaces the default envelope
+func WithEnvelope(envelope EnvelopeFunc) Option {
+	return func(p *EnvelopePlugin) {
+		p.envelope = envelope
+	}
+}
+
+// NewEnvelopePlugin creates an envelope plugin wrapping responses in an Envelope
+func NewEnvelopePlugin(opts ...Option) *EnvelopePlugin {
+	p := &EnvelopePlugin{
+		disabled: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.envelope == nil {
+		p.envelope = func(c *gin.Context, status int, data json.RawMessage) interface{} {
+			return Envelope{Success: true, Data: data, Version: p.version}
+		}
+	}
+	return p
+}
+
+func (p *EnvelopePlugin) Name() string {
+	return "envelope"
+}
+
+func (p *EnvelopePlugin) Version() string {
+	return "1.0.0"
+}
+
+// Priority implements core.PrioritizedPlugin
+func (p *EnvelopePlugin) Priority() int {
+	return Priority
+}
+
+func (p *EnvelopePlugin) Register(container core.DIContainer) error {
+	return nil
+}
+
+func (p *EnvelopePlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&EnvelopeHook{plugin: p},
+	}
+}
+
+// AppHooks records the routes opted out with OptionKey
+func (p *EnvelopePlugin) AppHooks() []core.ApplicationHook {
+	return []core.ApplicationHook{
+		&core.ApplicationHookFunc{
+			OnRouteFunc: func(config *core.RouteConfig) {
+				if enabled, ok := config.Options[OptionKey].(bool); ok && !enabled {
+					p.mu.Lock()
+					defer p.mu.Unlock()
+					p.disabled[config.Method+":"+config.Path] = true
+				}
+			},
+		},
+	}
+}
+
+// enabledFor reports whether the matched route is enveloped
+func (p *EnvelopePlugin) enabledFor(c *gin.Context) bool {
+	path := c.FullPath()
+	if path == "" {
+		return true
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return !p.disabled[c.Request.Method+":"+path] && !p.disabled[core.MethodAny+":"+path]
+}
+
+// EnvelopeHook wraps the response writer in OnRequest; the framework finishes it
+// (see core.ResponseFinisher), writing the envelope, before OnResponse
+type EnvelopeHook struct {
+	plugin *EnvelopePlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *EnvelopeHook) OnRequest(c *gin.Context) {
+	if !h.plugin.enabledFor(c) || strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		return
+	}
+
+	writer := &envelopeWriter{ResponseWriter: c.Writer, plugin: h.plugin, context: c}
+	c.Set(writerKey, writer)
+	c.Writer = writer
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *EnvelopeHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *EnvelopeHook) OnResponse(c *gin.Context, response interface{}) {
+	if writer, ok := requestWriter(c); ok {
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// OnError implements core.LifecycleHook
+func (h *EnvelopeHook) OnError(c *gin.Context, err error) {
+}
+
+// requestWriter returns the envelope writer installed for the request
+func requestWriter(c *gin.Context) (*envelopeWriter, bool) {
+	value, exists := c.Get(writerKey)
+	if !exists {
+		return nil, false
+	}
+	writer, ok := value.(*envelopeWriter)
+	return writer, ok
+}
+
+// envelopeWriter buffers successful JSON bodies until Finish envelopes them; other
+// responses pass through, decided on the first write
+type envelopeWriter struct {
+	gin.ResponseWriter
+
+	plugin    *EnvelopePlugin
+	context   *gin.Context
+	buffer    bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.shouldEnvelope()
+	}
+	if w.buffering {
+		return w.buffer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports buffered bodies as written, so nothing else renders a second response
+func (w *envelopeWriter) Written() bool {
+	return w.buffer.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush gives up on the envelope: a streamed response is sent as written
+func (w *envelopeWriter) Flush() {
+	w.passThrough()
+	w.ResponseWriter.Flush()
+}
+
+// Finish implements core.ResponseFinisher: the buffered body is written in its
+// envelope, then a writer beneath that buffers too (e.g. compression) is finished
+func (w *envelopeWriter) Finish() error {
+	var err error
+	if w.buffering {
+		w.buffering = false
+		body := w.buffer.Bytes()
+		if json.Valid(body) {
+			if enveloped, marshalErr := json.Marshal(w.plugin.envelope(w.context, w.Status(), json.RawMessage(body))); marshalErr == nil {
+				body = enveloped
+			}
+		}
+		w.Header().Del("Content-Length")
+		_, err = w.ResponseWriter.Write(body)
+		w.buffer.Reset()
+	}
+
+	if finisher, ok := w.ResponseWriter.(core.ResponseFinisher); ok {
+		if finishErr := finisher.Finish(); err == nil {
+			err = finishErr
+		}
+	}
+	return err
+}
+
+// passThrough writes what was buffered unchanged and stops buffering
+func (w *envelopeWriter) passThrough() {
+	w.decided = true
+	if !w.buffering {
+		return
+	}
+	w.buffering = false
+	w.ResponseWriter.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+}
+
+// shouldEnvelope reports whether the response about to be written is a successful,
+// unencoded JSON response
+func (w *envelopeWriter) shouldEnvelope() bool {
+	if w.ResponseWriter.Written() {
+		return false
+	}
+	status := w.Status()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices || status == http.StatusNoContent {
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,86 @@
+package envelope
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEnvelopeTestApp(t *testing.T, plugin *EnvelopePlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{Name: "envelope-test", Mode: gin.TestMode}).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	router := app.GetRouter()
+	router.GET(core.RouteConfig{Path: "/users"}, func(c *gin.Context, container core.DIContainer) {
+		c.JSON(http.StatusOK, gin.H{"users": []string{"alice", "bob"}})
+	})
+	router.GET(core.RouteConfig{
+		Path:    "/raw",
+		Options: map[string]interface{}{OptionKey: false},
+	}, func(c *gin.Context, container core.DIContainer) {
+		c.JSON(http.StatusOK, gin.H{"raw": true})
+	})
+	router.GET(core.RouteConfig{Path: "/text"}, func(c *gin.Context, container core.DIContainer) {
+		c.String(http.StatusOK, "plain")
+	})
+	router.GET(core.RouteConfig{Path: "/missing"}, func(c *gin.Context, container core.DIContainer) {
+		core.AbortWithError(c, core.ErrNotFound)
+	})
+	return app
+}
+
+func serve(app *core.DoffApp, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestEnvelopePlugin_WrapsJSONResponses(t *testing.T) {
+	app := newEnvelopeTestApp(t, NewEnvelopePlugin(WithVersion("v1")))
+
+	var sizes []int
+	app.GetPluginManager().GetLifecycleManager().AddHook(core.NewOnResponseHook(func(c *gin.Context, response interface{}) {
+		sizes = append(sizes, response.(*core.ResponseInfo).Size)
+	}))
+
+	recorder := serve(app, "/users")
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"success": true, "data": {"users": ["alice", "bob"]}, "version": "v1"}`, recorder.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	// OnResponse hooks see the enveloped body
+	assert.Equal(t, []int{recorder.Body.Len()}, sizes)
+}
+
+func TestEnvelopePlugin_SkipsOptedOutAndOtherResponses(t *testing.T) {
+	app := newEnvelopeTestApp(t, NewEnvelopePlugin())
+
+	assert.JSONEq(t, `{"raw": true}`, serve(app, "/raw").Body.String())
+	assert.Equal(t, "plain", serve(app, "/text").Body.String())
+
+	// Errors keep the framework's error envelope
+	recorder := serve(app, "/missing")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Contains(t, body, "error")
+	assert.NotContains(t, body, "success")
+}
+
+func TestEnvelopePlugin_CustomEnvelope(t *testing.T) {
+	app := newEnvelopeTestApp(t, NewEnvelopePlugin(WithEnvelope(func(c *gin.Context, status int, data json.RawMessage) interface{} {
+		return gin.H{"status": status, "path": c.FullPath(), "result": data}
+	})))
+
+	recorder := serve(app, "/users")
+	assert.JSONEq(t, `{"status": 200, "path": "/users", "result": {"users": ["alice", "bob"]}}`, recorder.Body.String())
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
@@ -9,14 +10,66 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Field is an access log field, the key it is logged under
+type Field string
+
+// Access log fields
+const (
+	FieldRequestID Field = "request_id"
+	FieldMethod    Field = "method"
+	FieldPath      Field = "path"
+	FieldRoute     Field = "route" // Matched route template, e.g. /users/:id
+	FieldStatus    Field = "status_code"
+	FieldDuration  Field = "duration"
+	FieldBytes     Field = "bytes" // Response body bytes written
+	FieldClientIP  Field = "client_ip"
+	FieldUserAgent Field = "user_agent"
+	FieldErrors    Field = "errors" // Errors recorded with c.Error
+)
+
+// DefaultFields are logged unless WithFields or WithoutFields changes them
+var DefaultFields = []Field{
+	FieldRequestID, FieldMethod, FieldPath, FieldRoute, FieldStatus,
+	FieldDuration, FieldBytes, FieldClientIP, FieldUserAgent, FieldErrors,
+}
+
 // LoggerPlugin implements the Plugin interface for request logging
 type LoggerPlugin struct {
 	core.BasePlugin
+
+	opts []Option
+}
+
+// Option configures the access log of a LoggerPlugin
+type Option func(*RequestLogger)
+
+// WithFields logs only the given fields
+func WithFields(fields ...Field) Option {
+	return func(l *RequestLogger) {
+		l.fields = fields
+	}
 }
 
-// NewLoggerPlugin creates a new logger plugin
-func NewLoggerPlugin() *LoggerPlugin {
-	return &LoggerPlugin{}
+// WithoutFields drops fields from the access log, e.g. WithoutFields(FieldUserAgent)
+func WithoutFields(fields ...Field) Option {
+	return func(l *RequestLogger) {
+		l.fields = slices.DeleteFunc(slices.Clone(l.fields), func(field Field) bool {
+			return slices.Contains(fields, field)
+		})
+	}
+}
+
+// WithRequestIDKey sets the request container data the request ID is read from when
+// the request has no core.RequestIDKey in its context, e.g. "correlationID"
+func WithRequestIDKey(key string) Option {
+	return func(l *RequestLogger) {
+		l.requestIDKey = key
+	}
+}
+
+// NewLoggerPlugin creates a new logger plugin logging DefaultFields
+func NewLoggerPlugin(opts ...Option) *LoggerPlugin {
+	return &LoggerPlugin{opts: opts}
 }
 
 // Name returns the plugin name
@@ -33,7 +86,7 @@ func (p *LoggerPlugin) Version() string {
 func (p *LoggerPlugin) Register(container core.DIContainer) error {
 	return container.RegisterSingleton("requestLogger", func(c core.DIContainer) (interface{}, error) {
 		logger, _ := c.Resolve("logger")
-		return NewRequestLogger(logger.(core.Logger)), nil
+		return NewRequestLogger(logger.(core.Logger), p.opts...), nil
 	})
 }
 
@@ -46,41 +99,95 @@ func (p *LoggerPlugin) Hooks() []core.LifecycleHook {
 
 // RequestLogger provides request logging functionality
 type RequestLogger struct {
-	logger core.Logger
+	logger       core.Logger
+	fields       []Field
+	requestIDKey string
 }
 
 // NewRequestLogger creates a new request logger
-func NewRequestLogger(logger core.Logger) *RequestLogger {
-	return &RequestLogger{
-		logger: logger,
+func NewRequestLogger(logger core.Logger, opts ...Option) *RequestLogger {
+	l := &RequestLogger{
+		logger:       logger,
+		fields:       DefaultFields,
+		requestIDKey: core.RequestIDKey,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // LogRequest logs a request
 func (l *RequestLogger) LogRequest(c *gin.Context, start time.Time) {
+	l.LogResponse(c, start, nil)
+}
+
+// LogResponse logs a request with the response OnResponse hooks receive; without
+// one the status and size are read from c.Writer
+func (l *RequestLogger) LogResponse(c *gin.Context, start time.Time, response *core.ResponseInfo) {
 	duration := time.Since(start)
 
+	status, size := c.Writer.Status(), c.Writer.Size()
+	if response != nil {
+		status, size = response.StatusCode, response.Size
+	}
+	if size < 0 {
+		size = 0
+	}
+
+	data := make(map[string]interface{}, len(l.fields))
+	for _, field := range l.fields {
+		switch field {
+		case FieldRequestID:
+			if requestID := l.requestID(c); requestID != "" {
+				data[string(field)] = requestID
+			}
+		case FieldMethod:
+			data[string(field)] = c.Request.Method
+		case FieldPath:
+			data[string(field)] = c.Request.URL.Path
+		case FieldRoute:
+			if route := c.FullPath(); route != "" {
+				data[string(field)] = route
+			}
+		case FieldStatus:
+			data[string(field)] = status
+		case FieldDuration:
+			data[string(field)] = duration
+		case FieldBytes:
+			data[string(field)] = size
+		case FieldClientIP:
+			data[string(field)] = core.ClientIP(c)
+		case FieldUserAgent:
+			data[string(field)] = c.GetHeader("User-Agent")
+		case FieldErrors:
+			if len(c.Errors) > 0 {
+				data[string(field)] = c.Errors.Errors()
+			}
+		}
+	}
+
 	l.logger.Infor(&core.LoggerItem{
 		Event:    "Request",
-		Messages: fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path),
-		Data: struct {
-			Method     string        `json:"method"`
-			Path       string        `json:"path"`
-			StatusCode int           `json:"status_code"`
-			Duration   time.Duration `json:"duration"`
-			ClientIP   string        `json:"client_ip"`
-			UserAgent  string        `json:"user_agent"`
-		}{
-			Method:     c.Request.Method,
-			Path:       c.Request.URL.Path,
-			StatusCode: c.Writer.Status(),
-			Duration:   duration,
-			ClientIP:   c.ClientIP(),
-			UserAgent:  c.GetHeader("User-Agent"),
-		},
+		Messages: fmt.Sprintf("%s %s %d", c.Request.Method, c.Request.URL.Path, status),
+		Data:     data,
 	})
 }
 
+// requestID returns the request's ID from the context, or from the request container
+func (l *RequestLogger) requestID(c *gin.Context) string {
+	if requestID := core.GetRequestID(c); requestID != "" {
+		return requestID
+	}
+	if rc, ok := core.GetRequestContainer(c); ok {
+		if value, exists := rc.GetRequestData(l.requestIDKey); exists {
+			requestID, _ := value.(string)
+			return requestID
+		}
+	}
+	return ""
+}
+
 // LoggerHook implements the LifecycleHook interface for request logging
 type LoggerHook struct {
 	requestLogger *RequestLogger
@@ -111,7 +218,8 @@ func (h *LoggerHook) OnResponse(c *gin.Context, response interface{}) {
 			requestLogger, err := c.MustGet("container").(core.DIContainer).Resolve("requestLogger")
 			if err == nil {
 				if logger, ok := requestLogger.(*RequestLogger); ok {
-					logger.LogRequest(c, start)
+					info, _ := response.(*core.ResponseInfo)
+					logger.LogResponse(c, start, info)
 				}
 			}
 		}
@@ -123,10 +231,15 @@ func (h *LoggerHook) OnError(c *gin.Context, err error) {
 	// Log the error
 	logger, _ := c.MustGet("container").(core.DIContainer).Resolve("logger")
 	if l, ok := logger.(core.Logger); ok {
-		l.Infor(&core.LoggerItem{
+		item := &core.LoggerItem{
+			Level:    core.LevelError,
 			Event:    "Error",
 			Messages: fmt.Sprintf("Error handling %s %s: %v", c.Request.Method, c.Request.URL.Path, err),
 			Error:    err,
-		})
+		}
+		if requestID := core.GetRequestID(c); requestID != "" {
+			item.Data = map[string]string{"request_id": requestID}
+		}
+		l.Infor(item)
 	}
 }
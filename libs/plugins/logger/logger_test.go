@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogger records the items logged through it
+type captureLogger struct {
+	mu    sync.Mutex
+	items []*core.LoggerItem
+}
+
+func (l *captureLogger) Infor(item *core.LoggerItem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = append(l.items, item)
+}
+
+// accessLogs returns the data of the access log items
+func (l *captureLogger) accessLogs() []map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var logs []map[string]interface{}
+	for _, item := range l.items {
+		if item.Event == "Request" {
+			logs = append(logs, item.Data.(map[string]interface{}))
+		}
+	}
+	return logs
+}
+
+func newLoggerTestApp(t *testing.T, opts ...Option) (*core.DoffApp, *captureLogger) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	logs := &captureLogger{}
+	app := core.CreateDoffApp(&core.AppOptions{
+		Name:              "logger-test",
+		Mode:              gin.TestMode,
+		UseLogger:         true,
+		Logger:            logs,
+		RequestContainers: &core.RequestContainerOptions{},
+	}).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(NewLoggerPlugin(opts...)))
+	return app, logs
+}
+
+func serve(app *core.DoffApp, method, path string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("User-Agent", "test-agent")
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestLoggerPlugin_AccessLogForBothRouters(t *testing.T) {
+	app, logs := newLoggerTestApp(t)
+	app.GetRouter().GET(core.RouteConfig{Path: "/users/:id"}, func(c *gin.Context, container core.DIContainer) {
+		c.String(http.StatusOK, "user")
+	})
+	core.NewEnhancedRouter(app.GetEngine(), app.GetContainer()).DELETE(core.RouteConfig{Path: "/orders/:id"}, func(c *gin.Context, params struct {
+		ID string `uri:"id"`
+	}) {
+		core.AbortWithError(c, core.ErrConflict.WithCause(errors.New("order "+params.ID+" is shipped")))
+	})
+
+	serve(app, http.MethodGet, "/users/42")
+	serve(app, http.MethodDelete, "/orders/7")
+
+	entries := logs.accessLogs()
+	require.Len(t, entries, 2)
+
+	user := entries[0]
+	assert.Equal(t, http.MethodGet, user["method"])
+	assert.Equal(t, "/users/42", user["path"])
+	assert.Equal(t, "/users/:id", user["route"])
+	assert.Equal(t, http.StatusOK, user["status_code"])
+	assert.Equal(t, len("user"), user["bytes"])
+	assert.Equal(t, "test-agent", user["user_agent"])
+	assert.Contains(t, user, "duration")
+	assert.NotContains(t, user, "errors")
+
+	order := entries[1]
+	assert.Equal(t, "/orders/:id", order["route"])
+	assert.Equal(t, http.StatusConflict, order["status_code"])
+	assert.Positive(t, order["bytes"])
+	assert.Equal(t, []string{"Conflict: order 7 is shipped"}, order["errors"])
+}
+
+func TestLoggerPlugin_ConfigurableFields(t *testing.T) {
+	app, logs := newLoggerTestApp(t, WithoutFields(FieldUserAgent, FieldClientIP), WithRequestIDKey("correlationID"))
+	require.NoError(t, app.DecorateRequestFactory("correlationID", func(c *gin.Context) interface{} {
+		return c.GetHeader("X-Correlation-ID")
+	}))
+	app.GetEngine().GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	request.Header.Set("X-Correlation-ID", "corr-1")
+	app.GetEngine().ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := logs.accessLogs()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "corr-1", entries[0]["request_id"])
+	assert.NotContains(t, entries[0], "user_agent")
+	assert.NotContains(t, entries[0], "client_ip")
+
+	app, logs = newLoggerTestApp(t, WithFields(FieldMethod, FieldStatus))
+	app.GetEngine().GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	serve(app, http.MethodGet, "/ping")
+	assert.Equal(t, []map[string]interface{}{{"method": http.MethodGet, "status_code": http.StatusNoContent}}, logs.accessLogs())
+}
@@ -0,0 +1,333 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPath is where the metrics are served
+const DefaultPath = "/metrics"
+
+// DefaultBuckets are the latency histogram upper bounds, in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// ContentType is the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	// unmatchedPath labels requests that matched no route, so 404 probes cannot
+	// create a series per URL
+	unmatchedPath = "<unmatched>"
+	// otherMethod labels non-standard methods for the same reason
+	otherMethod = "OTHER"
+	// startKey stores the request start time in the gin context
+	startKey = "doffy.metricsStart"
+)
+
+// knownMethods are the methods used as label values as-is
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// MetricsPlugin records per-route request counts, latency histograms and
+// in-flight gauges, and serves them in the Prometheus text format
+// Routes are labelled by their template (c.FullPath()), never the raw URL, so
+// path parameters do not create new series
+type MetricsPlugin struct {
+	core.BasePlugin
+
+	path    string
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	durations map[routeKey]*histogram
+	inFlight  map[routeKey]int64
+}
+
+// routeKey identifies a route by method and path template
+type routeKey struct {
+	method, path string
+}
+
+// requestKey identifies a request counter series
+type requestKey struct {
+	routeKey
+	status string // Status class, e.g. "2xx"
+}
+
+// histogram counts observations per bucket; counts are cumulated when written
+type histogram struct {
+	counts []uint64 // One per bucket, plus +Inf
+	sum    float64
+	count  uint64
+}
+
+// Option configures a MetricsPlugin
+type Option func(*MetricsPlugin)
+
+// WithPath sets the path the metrics are served on
+func WithPath(path string) Option {
+	return func(p *MetricsPlugin) {
+		p.path = path
+	}
+}
+
+// WithBuckets sets the latency histogram upper bounds, in seconds
+func WithBuckets(buckets ...float64) Option {
+	return func(p *MetricsPlugin) {
+		p.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(p.buckets)
+	}
+}
+
+// NewMetricsPlugin creates a metrics plugin serving DefaultPath with DefaultBuckets
+func NewMetricsPlugin(opts ...Option) *MetricsPlugin {
+	p := &MetricsPlugin{
+		path:      DefaultPath,
+		buckets:   DefaultBuckets,
+		requests:  make(map[requestKey]uint64),
+		durations: make(map[routeKey]*histogram),
+		inFlight:  make(map[routeKey]int64),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *MetricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *MetricsPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *MetricsPlugin) Register(container core.DIContainer) error {
+	return nil
+}
+
+func (p *MetricsPlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&MetricsHook{plugin: p},
+	}
+}
+
+// AppHooks starts every route registered after the plugin at zero, so its
+// series exist before the first request
+func (p *MetricsPlugin) AppHooks() []core.ApplicationHook {
+	return []core.ApplicationHook{
+		&core.ApplicationHookFunc{
+			OnRouteFunc: func(config *core.RouteConfig) {
+				if !knownMethods[config.Method] {
+					// e.g. Any routes; their series appear with the first request
+					return
+				}
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				p.route(routeKey{method: config.Method, path: config.Path})
+			},
+		},
+	}
+}
+
+// Routes serves the metrics
+func (p *MetricsPlugin) Routes(router *gin.Engine) error {
+	router.GET(p.path, p.Handler())
+	return nil
+}
+
+// Handler returns the handler serving the metrics, for mounting elsewhere
+func (p *MetricsPlugin) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", ContentType)
+		c.Status(http.StatusOK)
+		p.WriteMetrics(c.Writer)
+	}
+}
+
+// route returns the histogram of a route, creating its series on first use
+// Callers must hold p.mu
+func (p *MetricsPlugin) route(key routeKey) *histogram {
+	h, exists := p.durations[key]
+	if !exists {
+		h = &histogram{counts: make([]uint64, len(p.buckets)+1)}
+		p.durations[key] = h
+		p.inFlight[key] = 0
+	}
+	return h
+}
+
+// started records a request entering a route
+func (p *MetricsPlugin) started(key routeKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.route(key)
+	p.inFlight[key]++
+}
+
+// finished records a completed request
+func (p *MetricsPlugin) finished(key routeKey, status int, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h := p.route(key)
+	p.inFlight[key]--
+	p.requests[requestKey{routeKey: key, status: statusClass(status)}]++
+
+	seconds := duration.Seconds()
+	bucket := sort.SearchFloat64s(p.buckets, seconds)
+	h.counts[bucket]++
+	h.sum += seconds
+	h.count++
+}
+
+// WriteMetrics writes every metric in the Prometheus text exposition format
+func (p *MetricsPlugin) WriteMetrics(w io.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	routes := make([]routeKey, 0, len(p.durations))
+	for key := range p.durations {
+		routes = append(routes, key)
+	}
+	sort.Slice(routes, func(i, j int) bool { return lessRoute(routes[i], routes[j]) })
+
+	requests := make([]requestKey, 0, len(p.requests))
+	for key := range p.requests {
+		requests = append(requests, key)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].routeKey != requests[j].routeKey {
+			return lessRoute(requests[i].routeKey, requests[j].routeKey)
+		}
+		return requests[i].status < requests[j].status
+	})
+
+	fmt.Fprintln(w, "# HELP doffy_http_requests_total Total HTTP requests by route and status class.")
+	fmt.Fprintln(w, "# TYPE doffy_http_requests_total counter")
+	for _, key := range requests {
+		fmt.Fprintf(w, "doffy_http_requests_total{%s,status=%s} %d\n", routeLabels(key.routeKey), quote(key.status), p.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP doffy_http_request_duration_seconds HTTP request latency by route.")
+	fmt.Fprintln(w, "# TYPE doffy_http_request_duration_seconds histogram")
+	for _, key := range routes {
+		h := p.durations[key]
+		labels := routeLabels(key)
+		var cumulative uint64
+		for i, count := range h.counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(p.buckets) {
+				le = strconv.FormatFloat(p.buckets[i], 'g', -1, 64)
+			}
+			fmt.Fprintf(w, "doffy_http_request_duration_seconds_bucket{%s,le=%s} %d\n", labels, quote(le), cumulative)
+		}
+		fmt.Fprintf(w, "doffy_http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "doffy_http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP doffy_http_requests_in_flight HTTP requests currently being served by route.")
+	fmt.Fprintln(w, "# TYPE doffy_http_requests_in_flight gauge")
+	for _, key := range routes {
+		fmt.Fprintf(w, "doffy_http_requests_in_flight{%s} %d\n", routeLabels(key), p.inFlight[key])
+	}
+}
+
+// MetricsHook counts requests in OnRequest and records them in OnResponse
+type MetricsHook struct {
+	plugin *MetricsPlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *MetricsHook) OnRequest(c *gin.Context) {
+	c.Set(startKey, time.Now())
+	h.plugin.started(requestRoute(c))
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *MetricsHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *MetricsHook) OnResponse(c *gin.Context, response interface{}) {
+	status := c.Writer.Status()
+	if info, ok := response.(*core.ResponseInfo); ok {
+		status = info.StatusCode
+	}
+	h.finish(c, status)
+}
+
+// OnError implements core.LifecycleHook
+// Panicking requests never reach OnResponse; they are recorded as 500s here
+func (h *MetricsHook) OnError(c *gin.Context, err error) {
+	if errors.Is(err, core.ErrPanic) {
+		h.finish(c, http.StatusInternalServerError)
+	}
+}
+
+// finish records the request once
+func (h *MetricsHook) finish(c *gin.Context, status int) {
+	value, _ := c.Get(startKey)
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	c.Set(startKey, nil)
+	h.plugin.finished(requestRoute(c), status, time.Since(start))
+}
+
+// requestRoute returns the bounded-cardinality labels of the request
+func requestRoute(c *gin.Context) routeKey {
+	path := c.FullPath()
+	if path == "" {
+		path = unmatchedPath
+	}
+	return routeKey{method: methodLabel(c.Request.Method), path: path}
+}
+
+func methodLabel(method string) string {
+	method = strings.ToUpper(method)
+	if knownMethods[method] {
+		return method
+	}
+	return otherMethod
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+func lessRoute(a, b routeKey) bool {
+	if a.path != b.path {
+		return a.path < b.path
+	}
+	return a.method < b.method
+}
+
+func routeLabels(key routeKey) string {
+	return "method=" + quote(key.method) + ",path=" + quote(key.path)
+}
+
+// quote escapes a label value as the exposition format requires
+func quote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
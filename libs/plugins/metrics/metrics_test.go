@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetricsTestApp(t *testing.T, plugin *MetricsPlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{Name: "metrics-test", Mode: gin.TestMode}).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+	require.NoError(t, plugin.Routes(app.GetEngine()))
+
+	router := app.GetRouter()
+	router.GET(core.RouteConfig{Path: "/users/:id"}, func(c *gin.Context, container core.DIContainer) {
+		if c.Param("id") == "missing" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.POST(core.RouteConfig{Path: "/users"}, func(c *gin.Context, container core.DIContainer) {
+		c.Status(http.StatusCreated)
+	})
+	app.GetEngine().GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return app
+}
+
+func request(app *core.DoffApp, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestMetricsPlugin_ScrapeAfterRequests(t *testing.T) {
+	app := newMetricsTestApp(t, NewMetricsPlugin(WithBuckets(0.1, 1)))
+
+	request(app, http.MethodGet, "/users/1")
+	request(app, http.MethodGet, "/users/2")
+	request(app, http.MethodGet, "/users/missing")
+	request(app, http.MethodGet, "/no/such/route")
+	request(app, "PROPFIND", "/users/3")
+	request(app, http.MethodGet, "/panic")
+
+	scrape := request(app, http.MethodGet, DefaultPath)
+	require.Equal(t, http.StatusOK, scrape.Code)
+	assert.Equal(t, ContentType, scrape.Header().Get("Content-Type"))
+	body := scrape.Body.String()
+
+	// Path parameters collapse into the route template
+	assert.Contains(t, body, `doffy_http_requests_total{method="GET",path="/users/:id",status="2xx"} 2`+"\n")
+	assert.Contains(t, body, `doffy_http_requests_total{method="GET",path="/users/:id",status="4xx"} 1`+"\n")
+	assert.NotContains(t, body, "/users/1")
+
+	// Unmatched paths and unknown methods get fixed labels
+	assert.Contains(t, body, `doffy_http_requests_total{method="GET",path="<unmatched>",status="4xx"} 1`+"\n")
+	assert.Contains(t, body, `doffy_http_requests_total{method="OTHER",path="<unmatched>",status="4xx"} 1`+"\n")
+	assert.NotContains(t, body, "/no/such/route")
+
+	// Panics are counted as 500s and leave nothing in flight
+	assert.Contains(t, body, `doffy_http_requests_total{method="GET",path="/panic",status="5xx"} 1`+"\n")
+	assert.Contains(t, body, `doffy_http_requests_in_flight{method="GET",path="/panic"} 0`+"\n")
+
+	assert.Contains(t, body, "# TYPE doffy_http_request_duration_seconds histogram\n")
+	assert.Contains(t, body, `doffy_http_request_duration_seconds_bucket{method="GET",path="/users/:id",le="+Inf"} 3`+"\n")
+	assert.Contains(t, body, `doffy_http_request_duration_seconds_count{method="GET",path="/users/:id"} 3`+"\n")
+
+	// The scrape itself is in flight while the metrics are written
+	assert.Contains(t, body, `doffy_http_requests_in_flight{method="GET",path="/metrics"} 1`+"\n")
+}
+
+func TestMetricsPlugin_RegisteredRoutesStartAtZero(t *testing.T) {
+	app := newMetricsTestApp(t, NewMetricsPlugin())
+
+	body := request(app, http.MethodGet, DefaultPath).Body.String()
+	assert.Contains(t, body, `doffy_http_requests_in_flight{method="POST",path="/users"} 0`+"\n")
+	assert.Contains(t, body, `doffy_http_request_duration_seconds_count{method="POST",path="/users"} 0`+"\n")
+}
+
+func TestQuoteEscapesLabelValues(t *testing.T) {
+	assert.Equal(t, `"a\"b\\c\nd"`, quote("a\"b\\c\nd"))
+}
@@ -0,0 +1,208 @@
+package negotiation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// ReplyHelperName is the reply helper the plugin registers; call it through Respond
+const ReplyHelperName = "negotiate"
+
+// Format is a response serialization offered to clients
+type Format struct {
+	// MediaTypes are matched against the Accept header; the first is the one offered
+	MediaTypes []string
+	// Render writes data with the status code
+	Render func(c *gin.Context, status int, data interface{})
+}
+
+// Built-in formats
+var (
+	JSON = Format{
+		MediaTypes: []string{binding.MIMEJSON},
+		Render:     func(c *gin.Context, status int, data interface{}) { c.JSON(status, data) },
+	}
+	XML = Format{
+		MediaTypes: []string{binding.MIMEXML, binding.MIMEXML2},
+		Render:     func(c *gin.Context, status int, data interface{}) { c.XML(status, data) },
+	}
+	MsgPack = Format{
+		MediaTypes: []string{binding.MIMEMSGPACK2, binding.MIMEMSGPACK},
+		Render: func(c *gin.Context, status int, data interface{}) {
+			c.Render(status, render.MsgPack{Data: data})
+		},
+	}
+)
+
+// DefaultFormats are offered unless WithFormats replaces them; JSON is the default
+var DefaultFormats = []Format{JSON, XML, MsgPack}
+
+// Response is what handlers reply with; see Respond
+type Response struct {
+	Context *gin.Context
+	Status  int
+	Data    interface{}
+}
+
+// NegotiationPlugin registers the ReplyHelperName reply helper, which serializes a
+// response in the format the client's Accept header prefers (quality values included)
+// The first format is used when the client accepts anything or sends no Accept header;
+// a request accepting none of the formats gets 406
+type NegotiationPlugin struct {
+	core.BasePlugin
+
+	formats []Format
+}
+
+// Option configures a NegotiationPlugin
+type Option func(*NegotiationPlugin)
+
+// WithFormats replaces the offered formats, in order of preference; the first is the default
+func WithFormats(formats ...Format) Option {
+	return func(p *NegotiationPlugin) {
+		p.formats = formats
+	}
+}
+
+// NewNegotiationPlugin creates a negotiation plugin offering DefaultFormats
+func NewNegotiationPlugin(opts ...Option) *NegotiationPlugin {
+	p := &NegotiationPlugin{
+		formats: DefaultFormats,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *NegotiationPlugin) Name() string {
+	return "negotiation"
+}
+
+func (p *NegotiationPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *NegotiationPlugin) Register(container core.DIContainer) error {
+	return nil
+}
+
+func (p *NegotiationPlugin) Hooks() []core.LifecycleHook {
+	return nil
+}
+
+// Init registers the reply helper; request containers pick it up from the app's
+// decorator manager (see core.AppOptions.RequestContainers)
+func (p *NegotiationPlugin) Init(app *core.DoffApp) error {
+	return core.RegisterReplyHelper(app.GetDecoratorManager(), ReplyHelperName, p.reply)
+}
+
+func (p *NegotiationPlugin) reply(response Response) error {
+	return negotiate(response.Context, p.formats, response.Status, response.Data)
+}
+
+// Respond writes data in the negotiated format, using the plugin's reply helper when
+// the request container has it and DefaultFormats otherwise
+func Respond(c *gin.Context, status int, data interface{}) {
+	response := Response{Context: c, Status: status, Data: data}
+	if rc, ok := core.GetRequestContainer(c); ok {
+		if _, exists := rc.GetReplyHelper(ReplyHelperName); exists {
+			if _, err := core.CallReplyHelper[Response, error](rc, ReplyHelperName, response); err != nil {
+				core.AbortWithError(c, err)
+			}
+			return
+		}
+	}
+	negotiate(c, DefaultFormats, status, data)
+}
+
+// negotiate renders data in the format the request prefers, or answers 406
+func negotiate(c *gin.Context, formats []Format, status int, data interface{}) error {
+	format, ok := selectFormat(c.GetHeader("Accept"), formats)
+	if !ok {
+		err := core.ErrNotAcceptable.WithCause(fmt.Errorf("no format matches Accept '%s'", c.GetHeader("Accept")))
+		core.AbortWithError(c, err)
+		return err
+	}
+	format.Render(c, status, data)
+	return nil
+}
+
+// acceptRange is a media range of an Accept header
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// selectFormat returns the format for the highest quality media range that one
+// matches; ranges of equal quality keep their order in the header, and media types
+// sent with q=0 are never selected
+func selectFormat(accept string, formats []Format) (Format, bool) {
+	if len(formats) == 0 {
+		return Format{}, false
+	}
+	ranges, refused := parseAccept(accept)
+	if len(ranges) == 0 && len(refused) == 0 {
+		return formats[0], true
+	}
+
+	for _, r := range ranges {
+		for _, format := range formats {
+			for _, mediaType := range format.MediaTypes {
+				if mediaRangeMatches(r.mediaType, mediaType) && !refused[mediaType] {
+					return format, true
+				}
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// parseAccept returns the acceptable media ranges of an Accept header by descending
+// quality, and the media ranges refused with q=0
+func parseAccept(accept string) ([]acceptRange, map[string]bool) {
+	var ranges []acceptRange
+	refused := make(map[string]bool)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+		} else {
+			refused[mediaType] = true
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+	return ranges, refused
+}
+
+// mediaRangeMatches reports whether a media range such as "application/*" covers mediaType
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	rangeType, rangeSubtype, _ := strings.Cut(mediaRange, "/")
+	typ, _, _ := strings.Cut(mediaType, "/")
+	return rangeSubtype == "*" && rangeType == typ
+}
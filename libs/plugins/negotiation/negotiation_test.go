@@ -0,0 +1,103 @@
+package negotiation
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	XMLName xml.Name `json:"-" xml:"user" codec:"-"`
+	ID      int      `json:"id" xml:"id" codec:"id"`
+	Name    string   `json:"name" xml:"name" codec:"name"`
+}
+
+func newNegotiationTestApp(t *testing.T, plugin *NegotiationPlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{
+		Name:              "negotiation-test",
+		Mode:              gin.TestMode,
+		RequestContainers: &core.RequestContainerOptions{},
+	}).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+	require.NoError(t, app.Init())
+
+	app.GetRouter().GET(core.RouteConfig{Path: "/users/1"}, func(c *gin.Context, container core.DIContainer) {
+		Respond(c, http.StatusOK, user{ID: 1, Name: "ana"})
+	})
+	return app
+}
+
+func serve(app *core.DoffApp, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRespond_NegotiatesFormat(t *testing.T) {
+	app := newNegotiationTestApp(t, NewNegotiationPlugin())
+
+	t.Run("json by default", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", "application/json"} {
+			recorder := serve(app, accept)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Contains(t, recorder.Header().Get("Content-Type"), binding.MIMEJSON)
+			assert.JSONEq(t, `{"id":1,"name":"ana"}`, recorder.Body.String())
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		recorder := serve(app, "text/xml")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), binding.MIMEXML)
+
+		var decoded user
+		require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &decoded))
+		assert.Equal(t, "ana", decoded.Name)
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		recorder := serve(app, "application/msgpack")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), binding.MIMEMSGPACK2)
+
+		var decoded user
+		require.NoError(t, binding.MsgPack.BindBody(recorder.Body.Bytes(), &decoded))
+		assert.Equal(t, user{ID: 1, Name: "ana"}, decoded)
+	})
+
+	t.Run("quality values", func(t *testing.T) {
+		recorder := serve(app, "application/json;q=0.5, application/xml;q=0.9, text/html")
+		assert.Contains(t, recorder.Header().Get("Content-Type"), binding.MIMEXML)
+
+		recorder = serve(app, "application/json;q=0, */*;q=0.1")
+		assert.Contains(t, recorder.Header().Get("Content-Type"), binding.MIMEXML)
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		recorder := serve(app, "text/html, image/*;q=0.8")
+		assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"code":"not_acceptable"`)
+	})
+}
+
+func TestNegotiationPlugin_WithFormats(t *testing.T) {
+	app := newNegotiationTestApp(t, NewNegotiationPlugin(WithFormats(XML, JSON)))
+
+	assert.Contains(t, serve(app, "").Header().Get("Content-Type"), binding.MIMEXML)
+	assert.Contains(t, serve(app, "application/json").Header().Get("Content-Type"), binding.MIMEJSON)
+	assert.Equal(t, http.StatusNotAcceptable, serve(app, "application/msgpack").Code)
+}
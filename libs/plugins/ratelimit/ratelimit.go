@@ -0,0 +1,305 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// OptionKey sets a route's own limit through RouteConfig.Options, e.g.
+// RouteConfig{Options: map[string]interface{}{ratelimit.OptionKey: ratelimit.Limit{Requests: 5, Window: time.Minute}}}
+const OptionKey = "rateLimit"
+
+// ErrRateLimited is answered, with a Retry-After header, to clients over their limit
+var ErrRateLimited = core.NewAPIError(http.StatusTooManyRequests, "rate_limited", http.StatusText(http.StatusTooManyRequests))
+
+// Limit allows Requests per Window to each client, in bursts of up to Burst requests
+// Tokens are refilled continuously, so a client spending its burst regains one
+// request every Window/Requests
+type Limit struct {
+	Requests int
+	Window   time.Duration
+	Burst    int // Defaults to Requests
+}
+
+// rate returns the tokens refilled per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// burst returns the bucket capacity
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// validate reports a limit that allows nothing
+func (l Limit) validate() error {
+	if l.Requests <= 0 || l.Window <= 0 || l.Burst < 0 {
+		return fmt.Errorf("invalid rate limit %+v: Requests and Window must be positive and Burst not negative", l)
+	}
+	return nil
+}
+
+// KeyFunc returns the client a request is counted against
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP counts requests by client IP (see core.ClientIP); the default
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + core.ClientIP(c)
+}
+
+// KeyBySubject counts authenticated requests by the subject of their token, read
+// from the core.AuthResult in the request container (or the gin context): the
+// claims' UserID() when they have one, otherwise a hash of the token
+// Unauthenticated requests are counted by IP
+func KeyBySubject(c *gin.Context) string {
+	result, ok := authResult(c)
+	if !ok || !result.Authenticated {
+		return KeyByIP(c)
+	}
+	if claims, ok := result.Claims.(interface{ UserID() string }); ok && claims.UserID() != "" {
+		return "sub:" + claims.UserID()
+	}
+	sum := sha256.Sum256([]byte(result.Token))
+	return "token:" + hex.EncodeToString(sum[:16])
+}
+
+// authResult returns the request's AuthResult, from the request container first
+func authResult(c *gin.Context) (*core.AuthResult, bool) {
+	if rc, ok := core.GetRequestContainer(c); ok {
+		if value, err := rc.Resolve(core.AuthResultKey); err == nil {
+			if result, ok := value.(*core.AuthResult); ok {
+				return result, true
+			}
+		}
+	}
+	return core.GetAuthResult(c)
+}
+
+// RateLimitPlugin limits how often each client calls the app with token buckets,
+// answering 429 with Retry-After once a client's bucket is empty
+// WithLimit applies a limit to every request; routes with OptionKey use their own
+// limit instead, and groups can be limited with Middleware
+type RateLimitPlugin struct {
+	core.BasePlugin
+
+	limit  Limit // Global limit; zero Requests limits only the routes with OptionKey
+	key    KeyFunc
+	store  Store
+	logger core.Logger
+	err    error
+
+	mu     sync.RWMutex
+	routes map[string]Limit // "METHOD:path" of the routes with OptionKey, to their limit
+
+	middlewares atomic.Int64 // Numbers the buckets of each Middleware
+}
+
+// Option configures a RateLimitPlugin
+type Option func(*RateLimitPlugin)
+
+// WithLimit limits every request; by default only the routes with OptionKey are
+func WithLimit(limit Limit) Option {
+	return func(p *RateLimitPlugin) {
+		p.limit = limit
+	}
+}
+
+// WithKeyFunc sets what requests are counted against, e.g. KeyBySubject
+func WithKeyFunc(key KeyFunc) Option {
+	return func(p *RateLimitPlugin) {
+		p.key = key
+	}
+}
+
+// WithStore replaces the in-memory store, e.g. to share limits across instances
+func WithStore(store Store) Option {
+	return func(p *RateLimitPlugin) {
+		p.store = store
+	}
+}
+
+// NewRateLimitPlugin creates a rate limiting plugin keying clients by IP and keeping
+// its buckets in a MemoryStore cleaned up every DefaultCleanupInterval
+func NewRateLimitPlugin(opts ...Option) *RateLimitPlugin {
+	p := &RateLimitPlugin{
+		key:    KeyByIP,
+		routes: make(map[string]Limit),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.store == nil {
+		p.store = NewMemoryStore(DefaultCleanupInterval)
+	}
+
+	// Validate the global limit once; Register reports the error
+	if p.limit != (Limit{}) {
+		p.err = p.limit.validate()
+	}
+	return p
+}
+
+func (p *RateLimitPlugin) Name() string {
+	return "rate-limit"
+}
+
+func (p *RateLimitPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *RateLimitPlugin) Register(container core.DIContainer) error {
+	if logger, err := container.Resolve("logger"); err == nil {
+		p.logger, _ = logger.(core.Logger)
+	}
+	return p.err
+}
+
+// Shutdown closes the store when it is an io.Closer, e.g. a MemoryStore
+func (p *RateLimitPlugin) Shutdown() error {
+	if closer, ok := p.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (p *RateLimitPlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&RateLimitHook{plugin: p},
+	}
+}
+
+// AppHooks records the routes registered with OptionKey
+func (p *RateLimitPlugin) AppHooks() []core.ApplicationHook {
+	return []core.ApplicationHook{
+		&core.ApplicationHookFunc{
+			OnRouteFunc: func(config *core.RouteConfig) {
+				limit, ok := routeLimit(config.Options[OptionKey])
+				if !ok {
+					return
+				}
+				if err := limit.validate(); err != nil {
+					p.logWarning("RateLimitRouteIgnored", fmt.Sprintf("%s %s: %v", config.Method, config.Path, err))
+					return
+				}
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				p.routes[config.Method+":"+config.Path] = limit
+			},
+		},
+	}
+}
+
+// routeLimit reads the Limit or *Limit of a route option
+func routeLimit(option interface{}) (Limit, bool) {
+	switch limit := option.(type) {
+	case Limit:
+		return limit, true
+	case *Limit:
+		if limit != nil {
+			return *limit, true
+		}
+	}
+	return Limit{}, false
+}
+
+// Middleware limits every route of a group with its own buckets, e.g.
+// router.Group("/login", plugin.Middleware(ratelimit.Limit{Requests: 5, Window: time.Minute}))
+// It panics when the limit is invalid, so the misconfiguration fails at startup
+func (p *RateLimitPlugin) Middleware(limit Limit) gin.HandlerFunc {
+	if err := limit.validate(); err != nil {
+		panic(err)
+	}
+	scope := "group" + strconv.FormatInt(p.middlewares.Add(1), 10)
+	return func(c *gin.Context) {
+		if p.allow(c, scope, limit) {
+			c.Next()
+		}
+	}
+}
+
+// limitFor returns the limit of the matched route, and the scope its buckets belong to
+func (p *RateLimitPlugin) limitFor(c *gin.Context) (string, Limit, bool) {
+	if path := c.FullPath(); path != "" {
+		p.mu.RLock()
+		for _, route := range []string{c.Request.Method + ":" + path, core.MethodAny + ":" + path} {
+			if limit, exists := p.routes[route]; exists {
+				p.mu.RUnlock()
+				return route, limit, true
+			}
+		}
+		p.mu.RUnlock()
+	}
+	if p.limit.Requests > 0 {
+		return "global", p.limit, true
+	}
+	return "", Limit{}, false
+}
+
+// allow takes a token for the request's client in scope, answering 429 and
+// returning false when there is none
+// Store errors let the request through: an unavailable store should not take the
+// app down with it
+func (p *RateLimitPlugin) allow(c *gin.Context, scope string, limit Limit) bool {
+	allowed, retryAfter, err := p.store.Take(c.Request.Context(), scope+"|"+p.key(c), limit)
+	if err != nil {
+		p.logWarning("RateLimitStoreFailed", fmt.Sprintf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err))
+		return true
+	}
+	if allowed {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	core.AbortWithError(c, ErrRateLimited)
+	return false
+}
+
+func (p *RateLimitPlugin) logWarning(event, message string) {
+	if p.logger == nil {
+		return
+	}
+	p.logger.Infor(&core.LoggerItem{
+		Level:    core.LevelWarn,
+		Event:    event,
+		Messages: message,
+	})
+}
+
+// RateLimitHook limits requests in OnRequest, after the authentication hook has
+// identified the client
+type RateLimitHook struct {
+	plugin *RateLimitPlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *RateLimitHook) OnRequest(c *gin.Context) {
+	if scope, limit, ok := h.plugin.limitFor(c); ok {
+		h.plugin.allow(c, scope, limit)
+	}
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *RateLimitHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *RateLimitHook) OnResponse(c *gin.Context, response interface{}) {
+}
+
+// OnError implements core.LifecycleHook
+func (h *RateLimitHook) OnError(c *gin.Context, err error) {
+}
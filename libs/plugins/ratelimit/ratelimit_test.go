@@ -0,0 +1,246 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitTestApp(t *testing.T, options *core.AppOptions, plugin *RateLimitPlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if options == nil {
+		options = &core.AppOptions{}
+	}
+	options.Name = "rate-limit-test"
+	options.Mode = gin.TestMode
+	app := core.CreateDoffApp(options).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+	t.Cleanup(func() { plugin.Shutdown() })
+	return app
+}
+
+func serve(app *core.DoffApp, path, remoteAddr, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	request.RemoteAddr = remoteAddr
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimitPlugin_GlobalLimitByIP(t *testing.T) {
+	plugin := NewRateLimitPlugin(WithLimit(Limit{Requests: 2, Window: time.Minute}))
+	app := newRateLimitTestApp(t, nil, plugin)
+	app.GetEngine().GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	assert.Equal(t, http.StatusOK, serve(app, "/ping", "10.0.0.1:1234", "").Code)
+	assert.Equal(t, http.StatusOK, serve(app, "/ping", "10.0.0.1:1234", "").Code)
+
+	recorder := serve(app, "/ping", "10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	assert.Contains(t, recorder.Body.String(), "rate_limited")
+
+	// Another client has its own bucket
+	assert.Equal(t, http.StatusOK, serve(app, "/ping", "10.0.0.2:1234", "").Code)
+}
+
+func TestRateLimitPlugin_RouteOption(t *testing.T) {
+	plugin := NewRateLimitPlugin()
+	app := newRateLimitTestApp(t, nil, plugin)
+	handler := func(c *gin.Context, container core.DIContainer) {
+		c.Status(http.StatusOK)
+	}
+	app.GetRouter().GET(core.RouteConfig{
+		Path:    "/login",
+		Options: map[string]interface{}{OptionKey: Limit{Requests: 1, Window: time.Minute}},
+	}, handler)
+	app.GetRouter().GET(core.RouteConfig{Path: "/open"}, handler)
+
+	assert.Equal(t, http.StatusOK, serve(app, "/login", "10.0.0.1:1234", "").Code)
+	recorder := serve(app, "/login", "10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+
+	// Without a global limit, other routes are not limited
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, serve(app, "/open", "10.0.0.1:1234", "").Code)
+	}
+}
+
+func TestRateLimitPlugin_Middleware(t *testing.T) {
+	plugin := NewRateLimitPlugin()
+	app := newRateLimitTestApp(t, nil, plugin)
+	group := app.GetEngine().Group("/admin", plugin.Middleware(Limit{Requests: 1, Window: time.Minute}))
+	group.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	group.GET("/roles", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusOK, serve(app, "/admin/users", "10.0.0.1:1234", "").Code)
+	// The group's routes share the bucket
+	assert.Equal(t, http.StatusTooManyRequests, serve(app, "/admin/roles", "10.0.0.1:1234", "").Code)
+
+	assert.Panics(t, func() { plugin.Middleware(Limit{Requests: 1}) })
+}
+
+// subjectClaims carry the subject KeyBySubject counts requests by
+type subjectClaims struct {
+	subject string
+}
+
+func (c *subjectClaims) UserID() string { return c.subject }
+
+// subjectAuthenticator accepts tokens of the form "<subject>.<anything>"
+type subjectAuthenticator struct{}
+
+func (a *subjectAuthenticator) Authenticate(ctx context.Context, token string) (bool, error) {
+	return true, nil
+}
+
+func (a *subjectAuthenticator) Assert(ctx context.Context, token string) (bool, error) {
+	return true, nil
+}
+
+func (a *subjectAuthenticator) ParseClaims(ctx context.Context, token string) (interface{}, error) {
+	subject, _, _ := strings.Cut(token, ".")
+	return &subjectClaims{subject: subject}, nil
+}
+
+func TestRateLimitPlugin_KeyBySubject(t *testing.T) {
+	plugin := NewRateLimitPlugin(
+		WithLimit(Limit{Requests: 2, Window: time.Minute}),
+		WithKeyFunc(KeyBySubject),
+	)
+	app := newRateLimitTestApp(t, &core.AppOptions{
+		Authenticator:     &subjectAuthenticator{},
+		RequestContainers: &core.RequestContainerOptions{},
+	}, plugin)
+	app.GetRouter().GET(core.RouteConfig{Path: "/me"}, func(c *gin.Context, container core.DIContainer) {
+		c.Status(http.StatusOK)
+	})
+
+	// Two tokens of the same subject, from different IPs, share the bucket
+	assert.Equal(t, http.StatusOK, serve(app, "/me", "10.0.0.1:1234", "alice.laptop").Code)
+	assert.Equal(t, http.StatusOK, serve(app, "/me", "10.0.0.2:1234", "alice.phone").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(app, "/me", "10.0.0.3:1234", "alice.tablet").Code)
+
+	assert.Equal(t, http.StatusOK, serve(app, "/me", "10.0.0.1:1234", "bob.laptop").Code)
+}
+
+func TestRateLimitPlugin_ConcurrentRequests(t *testing.T) {
+	plugin := NewRateLimitPlugin(WithLimit(Limit{Requests: 10, Window: time.Hour}))
+	app := newRateLimitTestApp(t, nil, plugin)
+	app.GetEngine().GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var allowed, limited atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch serve(app, "/ping", "10.0.0.1:1234", "").Code {
+			case http.StatusOK:
+				allowed.Add(1)
+			case http.StatusTooManyRequests:
+				limited.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(10), allowed.Load())
+	assert.Equal(t, int64(40), limited.Load())
+}
+
+// failingStore is a store that is unavailable
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unreachable")
+}
+
+func TestRateLimitPlugin_StoreErrorsLetRequestsThrough(t *testing.T) {
+	plugin := NewRateLimitPlugin(WithLimit(Limit{Requests: 1, Window: time.Minute}), WithStore(failingStore{}))
+	app := newRateLimitTestApp(t, nil, plugin)
+	app.GetEngine().GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusOK, serve(app, "/ping", "10.0.0.1:1234", "").Code)
+	assert.Equal(t, http.StatusOK, serve(app, "/ping", "10.0.0.1:1234", "").Code)
+}
+
+func TestRateLimitPlugin_InvalidLimit(t *testing.T) {
+	plugin := NewRateLimitPlugin(WithLimit(Limit{Requests: 5}))
+	app := core.CreateDoffApp(&core.AppOptions{Name: "rate-limit-test", Mode: gin.TestMode}).(*core.DoffApp)
+	err := app.RegisterPlugin(plugin)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rate limit")
+}
+
+func TestMemoryStore_RefillsAndCleansUp(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Unix(0, 0)
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Window: time.Second, Burst: 4}
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		allowed, _, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, allowed, "burst request %d", i)
+	}
+	allowed, retryAfter, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Two requests per second refill one token every half second
+	now = now.Add(500 * time.Millisecond)
+	allowed, _, _ = store.Take(ctx, "client", limit)
+	assert.True(t, allowed)
+
+	store.cleanup()
+	assert.Len(t, store.buckets, 1, "a bucket still refilling is kept")
+
+	now = now.Add(2 * time.Second)
+	store.cleanup()
+	assert.Empty(t, store.buckets, "a full bucket is dropped")
+}
+
+func TestMemoryStore_ConcurrentTakes(t *testing.T) {
+	store := NewMemoryStore(time.Millisecond)
+	defer store.Close()
+	limit := Limit{Requests: 100, Window: time.Hour}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if ok, _, err := store.Take(context.Background(), "client", limit); err == nil && ok {
+					allowed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(100), allowed.Load())
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCleanupInterval is how often the default MemoryStore drops idle buckets
+const DefaultCleanupInterval = time.Minute
+
+// Store keeps the token buckets; MemoryStore keeps them in process, a shared store
+// (e.g. Redis) limits clients across instances
+type Store interface {
+	// Take removes a token from the key's bucket, created full for limit on first use
+	// When the bucket is empty it reports false and how long until a token is available
+	Take(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// bucket is a token bucket refilled continuously at its limit's rate
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// refill adds the tokens accumulated since the last update, up to the burst
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(b.limit.burst()), b.tokens+elapsed.Seconds()*b.limit.rate())
+	}
+	b.last = now
+}
+
+// MemoryStore is an in-process Store; buckets that have refilled completely are
+// dropped periodically, as a new bucket would be identical
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemoryStore creates an in-memory store dropping idle buckets every
+// cleanupInterval; zero or negative disables the cleanup
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go s.cleanupEvery(cleanupInterval)
+	}
+	return s
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, exists := s.buckets[key]
+	if !exists || b.limit != limit {
+		b = &bucket{tokens: float64(limit.burst()), last: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	missing := (1 - b.tokens) / limit.rate()
+	return false, time.Duration(missing * float64(time.Second)), nil
+}
+
+// Close stops the periodic cleanup
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (s *MemoryStore) cleanupEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// cleanup drops the buckets that are full again
+func (s *MemoryStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.burst()) {
+			delete(s.buckets, key)
+		}
+	}
+}
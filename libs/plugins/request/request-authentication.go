@@ -1,22 +1,18 @@
 package request
 
 import (
-	"fmt"
-	"net/http"
-
 	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
 	"github.com/gin-gonic/gin"
 )
 
+// RequestAuthentication requires an Authorization header on every non-public route
+// Public routes (IsAuth: false) are tracked by the core route registry
 type RequestAuthentication struct {
 	core.BasePlugin
-	publicRoutes map[string]bool
 }
 
 func NewRequestAuthentication() *RequestAuthentication {
-	return &RequestAuthentication{
-		publicRoutes: make(map[string]bool),
-	}
+	return &RequestAuthentication{}
 }
 
 func (p *RequestAuthentication) Name() string {
@@ -37,22 +33,6 @@ func (p *RequestAuthentication) Hooks() []core.LifecycleHook {
 	}
 }
 
-// OnRoute implements core.RouteAwarePlugin
-func (p *RequestAuthentication) OnRoute(info core.RouteInfo) {
-	fmt.Printf("[RequestAuthentication] Route registered: %s %s\n", info.Method, info.Path)
-
-	// Check if isAuth option is present and false
-	if info.Options != nil {
-		if isAuth, ok := info.Options["isAuth"]; ok {
-			if isAuthBool, ok := isAuth.(bool); ok && !isAuthBool {
-				key := fmt.Sprintf("%s:%s", info.Method, info.Path)
-				p.publicRoutes[key] = true
-				fmt.Printf("[RequestAuthentication] Route marked as public: %s\n", key)
-			}
-		}
-	}
-}
-
 type RequestAuthenticationHook struct {
 	plugin *RequestAuthentication
 }
@@ -66,10 +46,7 @@ func NewRequestAuthenticationHook(plugin *RequestAuthentication) *RequestAuthent
 // OnRequest implements core.LifecycleHook
 func (h *RequestAuthenticationHook) OnRequest(c *gin.Context) {
 	// Check if route is public
-	// Note: c.FullPath() returns the matched path pattern (e.g. /users/:id)
-	key := fmt.Sprintf("%s:%s", c.Request.Method, c.FullPath())
-
-	if h.plugin.publicRoutes[key] {
+	if core.IsPublicRoute(c) {
 		// Public route, skip auth
 		return
 	}
@@ -78,8 +55,7 @@ func (h *RequestAuthenticationHook) OnRequest(c *gin.Context) {
 	// For demonstration, we just check for a header
 	token := c.GetHeader("Authorization")
 	if token == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		c.Abort()
+		core.AbortWithError(c, core.ErrUnauthorized)
 		return
 	}
 }
@@ -0,0 +1,142 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultHeader is the header read and echoed when no other header is configured
+const DefaultHeader = "X-Request-ID"
+
+// maxIncomingLength bounds trusted incoming IDs, which end up in logs
+const maxIncomingLength = 128
+
+// RequestIDPlugin assigns every request an ID, stored under core.RequestIDKey and
+// echoed back in the response header; the logger plugin includes it in request logs
+// Register it before other plugins so their hooks can read the ID
+type RequestIDPlugin struct {
+	core.BasePlugin
+
+	header        string
+	trustIncoming bool
+	generate      func() string
+}
+
+// Option configures a RequestIDPlugin
+type Option func(*RequestIDPlugin)
+
+// WithHeader sets the header the ID is read from and echoed in, e.g. "X-Correlation-ID"
+func WithHeader(header string) Option {
+	return func(p *RequestIDPlugin) {
+		p.header = header
+	}
+}
+
+// WithTrustIncoming controls whether an ID sent by the client is reused (the default)
+// Disable it for public-facing services so clients cannot choose IDs
+func WithTrustIncoming(trust bool) Option {
+	return func(p *RequestIDPlugin) {
+		p.trustIncoming = trust
+	}
+}
+
+// WithGenerator replaces the random UUID generator
+func WithGenerator(generate func() string) Option {
+	return func(p *RequestIDPlugin) {
+		p.generate = generate
+	}
+}
+
+// NewRequestIDPlugin creates a request ID plugin using DefaultHeader and trusting incoming IDs
+func NewRequestIDPlugin(opts ...Option) *RequestIDPlugin {
+	p := &RequestIDPlugin{
+		header:        DefaultHeader,
+		trustIncoming: true,
+		generate:      NewUUID,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *RequestIDPlugin) Name() string {
+	return "request-id"
+}
+
+func (p *RequestIDPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *RequestIDPlugin) Register(container core.DIContainer) error {
+	return nil
+}
+
+func (p *RequestIDPlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&RequestIDHook{plugin: p},
+	}
+}
+
+// RequestIDHook assigns the request ID in OnRequest
+type RequestIDHook struct {
+	plugin *RequestIDPlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *RequestIDHook) OnRequest(c *gin.Context) {
+	id := ""
+	if h.plugin.trustIncoming {
+		id = c.GetHeader(h.plugin.header)
+		if !validIncomingID(id) {
+			id = ""
+		}
+	}
+	if id == "" {
+		id = h.plugin.generate()
+	}
+
+	c.Set(core.RequestIDKey, id)
+	c.Header(h.plugin.header, id)
+	if requestContainer, ok := core.GetRequestContainer(c); ok {
+		requestContainer.DecorateRequest(core.RequestIDKey, id)
+	}
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *RequestIDHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *RequestIDHook) OnResponse(c *gin.Context, response interface{}) {
+}
+
+// OnError implements core.LifecycleHook
+func (h *RequestIDHook) OnError(c *gin.Context, err error) {
+}
+
+// validIncomingID accepts short IDs of printable ASCII so clients cannot inject
+// control characters or oversized values into logs
+func validIncomingID(id string) bool {
+	if id == "" || len(id) > maxIncomingLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewUUID returns a random (version 4) UUID
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])             // never fails; crashes the program if the system source is broken
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,72 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func newRequestIDTestApp(t *testing.T, plugin *RequestIDPlugin) *core.DoffApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	app := core.CreateDoffApp(&core.AppOptions{Name: "request-id-test", Mode: gin.TestMode}).(*core.DoffApp)
+	require.NoError(t, app.RegisterPlugin(plugin))
+
+	app.GetRouter().GET(core.RouteConfig{Path: "/id"}, func(c *gin.Context, container core.DIContainer) {
+		c.String(http.StatusOK, core.GetRequestID(c))
+	})
+	return app
+}
+
+func serveWithHeader(app *core.DoffApp, header, value string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/id", nil)
+	if value != "" {
+		request.Header.Set(header, value)
+	}
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRequestIDPlugin_GeneratesID(t *testing.T) {
+	app := newRequestIDTestApp(t, NewRequestIDPlugin())
+
+	first := serveWithHeader(app, DefaultHeader, "")
+	second := serveWithHeader(app, DefaultHeader, "")
+
+	assert.Regexp(t, uuidPattern, first.Body.String())
+	assert.Equal(t, first.Body.String(), first.Header().Get(DefaultHeader))
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+}
+
+func TestRequestIDPlugin_TrustsIncomingID(t *testing.T) {
+	app := newRequestIDTestApp(t, NewRequestIDPlugin(WithHeader("X-Correlation-ID")))
+
+	recorder := serveWithHeader(app, "X-Correlation-ID", "client-abc-123")
+	assert.Equal(t, "client-abc-123", recorder.Body.String())
+	assert.Equal(t, "client-abc-123", recorder.Header().Get("X-Correlation-ID"))
+
+	// Values that could corrupt logs are replaced
+	recorder = serveWithHeader(app, "X-Correlation-ID", "bad id\twith spaces")
+	assert.Regexp(t, uuidPattern, recorder.Body.String())
+}
+
+func TestRequestIDPlugin_IgnoresIncomingIDWhenUntrusted(t *testing.T) {
+	app := newRequestIDTestApp(t, NewRequestIDPlugin(
+		WithTrustIncoming(false),
+		WithGenerator(func() string { return "server-id" }),
+	))
+
+	recorder := serveWithHeader(app, DefaultHeader, "client-chosen")
+	assert.Equal(t, "server-id", recorder.Body.String())
+	assert.Equal(t, "server-id", recorder.Header().Get(DefaultHeader))
+}
@@ -0,0 +1,383 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDBName is the service the transactions are begun on
+const DefaultDBName = "db"
+
+// OptionKey opts a route in through RouteConfig.Options, e.g.
+// RouteConfig{Options: map[string]interface{}{transaction.OptionKey: true}}
+const OptionKey = "transaction"
+
+// TxName is the request container name of the transaction; it is also stored
+// under "*sql.Tx", so enhanced router handlers can take a *sql.Tx parameter
+const TxName = "tx"
+
+const (
+	// txTypeName is what the enhanced router resolves a *sql.Tx parameter by
+	txTypeName = "*sql.Tx"
+	// stateKey stores the request's transaction state in the gin context
+	stateKey = "doffy.transaction"
+)
+
+// Beginner starts transactions; *sql.DB and *sql.Conn implement it
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TransactionPlugin runs opted-in requests in a database transaction: it is begun
+// before the handler, committed when the response is 2xx and rolled back on
+// errors, panics and any other status
+// The response is held back until the transaction is finished, so a failed commit
+// is answered with a 500 instead of the handler's response
+// Routes opt in with OptionKey; groups by using Middleware
+type TransactionPlugin struct {
+	core.BasePlugin
+
+	dbName    string
+	txOptions *sql.TxOptions
+	container core.DIContainer
+	logger    core.Logger
+
+	mu     sync.RWMutex
+	routes map[string]bool // "METHOD:path" of opted-in routes
+}
+
+// Option configures a TransactionPlugin
+type Option func(*TransactionPlugin)
+
+// WithDBName sets the service the transactions are begun on; it must resolve to a Beginner
+func WithDBName(name string) Option {
+	return func(p *TransactionPlugin) {
+		p.dbName = name
+	}
+}
+
+// WithTxOptions sets the isolation level and read-only flag of the transactions
+func WithTxOptions(opts *sql.TxOptions) Option {
+	return func(p *TransactionPlugin) {
+		p.txOptions = opts
+	}
+}
+
+// NewTransactionPlugin creates a transaction plugin beginning on DefaultDBName
+func NewTransactionPlugin(opts ...Option) *TransactionPlugin {
+	p := &TransactionPlugin{
+		dbName: DefaultDBName,
+		routes: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *TransactionPlugin) Name() string {
+	return "transaction"
+}
+
+func (p *TransactionPlugin) Version() string {
+	return "1.0.0"
+}
+
+func (p *TransactionPlugin) Register(container core.DIContainer) error {
+	p.container = container
+	if logger, err := container.Resolve("logger"); err == nil {
+		p.logger, _ = logger.(core.Logger)
+	}
+	return nil
+}
+
+func (p *TransactionPlugin) Hooks() []core.LifecycleHook {
+	return []core.LifecycleHook{
+		&TransactionHook{plugin: p},
+	}
+}
+
+// AppHooks records the routes registered with OptionKey
+func (p *TransactionPlugin) AppHooks() []core.ApplicationHook {
+	return []core.ApplicationHook{
+		&core.ApplicationHookFunc{
+			OnRouteFunc: func(config *core.RouteConfig) {
+				if enabled, _ := config.Options[OptionKey].(bool); !enabled {
+					return
+				}
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				p.routes[config.Method+":"+config.Path] = true
+			},
+		},
+	}
+}
+
+// Middleware begins the transaction for every route of a group, e.g.
+// router.Group("/orders", plugin.Middleware())
+// The plugin's hooks still commit or roll it back, so it must be registered
+func (p *TransactionPlugin) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p.begin(c) {
+			c.Next()
+		}
+	}
+}
+
+// FromContext returns the request's transaction, for handlers without injection
+func FromContext(c *gin.Context) (*sql.Tx, bool) {
+	state, ok := requestState(c)
+	if !ok {
+		return nil, false
+	}
+	return state.tx, true
+}
+
+// txState tracks a request's transaction until it is finished
+type txState struct {
+	tx     *sql.Tx
+	writer *commitWriter
+	done   bool
+}
+
+// optedIn reports whether the matched route was registered with OptionKey
+func (p *TransactionPlugin) optedIn(c *gin.Context) bool {
+	path := c.FullPath()
+	if path == "" {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.routes[c.Request.Method+":"+path] || p.routes[core.MethodAny+":"+path]
+}
+
+// begin starts the request's transaction, stores it in the request container and
+// holds back the response until it is finished
+// It aborts with 503 and returns false when the transaction cannot be begun
+func (p *TransactionPlugin) begin(c *gin.Context) bool {
+	if _, exists := requestState(c); exists {
+		return true
+	}
+
+	tx, err := p.beginTx(c.Request.Context())
+	if err != nil {
+		core.AbortWithError(c, core.ErrServiceUnavailable.WithCause(err))
+		return false
+	}
+
+	writer := &commitWriter{ResponseWriter: c.Writer, plugin: p, context: c}
+	c.Set(stateKey, &txState{tx: tx, writer: writer})
+	c.Writer = writer
+	rc := requestContainer(c)
+	rc.DecorateRequest(TxName, tx)
+	rc.DecorateRequest(txTypeName, tx)
+	return true
+}
+
+func (p *TransactionPlugin) beginTx(ctx context.Context) (*sql.Tx, error) {
+	if p.container == nil {
+		return nil, fmt.Errorf("transaction plugin is not registered")
+	}
+	db, err := p.container.ResolveWithContext(p.dbName, ctx)
+	if err != nil {
+		return nil, err
+	}
+	beginner, ok := db.(Beginner)
+	if !ok {
+		return nil, fmt.Errorf("service '%s' is a %T, not a Beginner", p.dbName, db)
+	}
+	return beginner.BeginTx(ctx, p.txOptions)
+}
+
+// finish commits or rolls back the request's transaction once, returning the
+// commit error
+func (p *TransactionPlugin) finish(c *gin.Context, commit bool) error {
+	state, ok := requestState(c)
+	if !ok || state.done {
+		return nil
+	}
+	state.done = true
+
+	if commit {
+		err := state.tx.Commit()
+		if err != nil {
+			p.logFailure(c, "TransactionCommitFailed", err)
+		}
+		return err
+	}
+	if err := state.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		p.logFailure(c, "TransactionRollbackFailed", err)
+	}
+	return nil
+}
+
+func (p *TransactionPlugin) logFailure(c *gin.Context, event string, err error) {
+	if p.logger == nil {
+		return
+	}
+	p.logger.Infor(&core.LoggerItem{
+		Level:    core.LevelError,
+		Event:    event,
+		Messages: fmt.Sprintf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err),
+	})
+}
+
+// requestState returns the transaction state stored by begin
+func requestState(c *gin.Context) (*txState, bool) {
+	value, exists := c.Get(stateKey)
+	if !exists {
+		return nil, false
+	}
+	state, ok := value.(*txState)
+	return state, ok
+}
+
+// requestContainer returns the request's container, creating it from the app
+// container when the app does not (see core.AppOptions.RequestContainers)
+func requestContainer(c *gin.Context) *core.RequestContainer {
+	if rc, ok := core.GetRequestContainer(c); ok {
+		return rc
+	}
+
+	var parent core.DIContainer
+	if value, exists := c.Get("container"); exists {
+		parent, _ = value.(core.DIContainer)
+	}
+	rc := core.NewRequestContainer(parent)
+	core.SetRequestContainer(c, rc)
+	return rc
+}
+
+// commitWriter holds back the response of a transactional request; the framework
+// finishes it (see core.ResponseFinisher) once the handler chain is done, which
+// commits or rolls back by the response status before anything reaches the client
+type commitWriter struct {
+	gin.ResponseWriter
+
+	plugin    *TransactionPlugin
+	context   *gin.Context
+	buffer    []byte
+	headerNow bool
+	released  bool
+}
+
+func (w *commitWriter) Write(data []byte) (int, error) {
+	if w.released {
+		return w.ResponseWriter.Write(data)
+	}
+	w.buffer = append(w.buffer, data...)
+	return len(data), nil
+}
+
+func (w *commitWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow is held back with the body
+func (w *commitWriter) WriteHeaderNow() {
+	if w.released {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.headerNow = true
+}
+
+// Written reports held back responses as written, so nothing else renders a second response
+func (w *commitWriter) Written() bool {
+	return w.headerNow || len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush finishes the transaction early: a streamed response cannot be held back
+func (w *commitWriter) Flush() {
+	w.release()
+	w.ResponseWriter.Flush()
+}
+
+// Finish implements core.ResponseFinisher: the transaction is finished and the
+// response released, then a writer beneath that buffers too (e.g. compression) is
+// finished
+func (w *commitWriter) Finish() error {
+	err := w.release()
+	if finisher, ok := w.ResponseWriter.(core.ResponseFinisher); ok {
+		if finishErr := finisher.Finish(); err == nil {
+			err = finishErr
+		}
+	}
+	return err
+}
+
+// release finishes the transaction by the response status, then writes the held
+// back response, or a 500 through core.AbortWithError when the commit failed
+func (w *commitWriter) release() error {
+	if w.released {
+		return nil
+	}
+	w.released = true
+
+	status := w.Status()
+	if err := w.plugin.finish(w.context, status >= http.StatusOK && status < http.StatusMultipleChoices); err != nil {
+		w.buffer = nil
+		header := w.Header()
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+
+		previous := w.context.Writer
+		w.context.Writer = w
+		core.AbortWithError(w.context, core.ErrInternal.WithMessage("Failed to commit the transaction").WithCause(err))
+		w.context.Writer = previous
+		return nil
+	}
+
+	buffered := w.buffer
+	w.buffer = nil
+	if len(buffered) == 0 {
+		if w.headerNow {
+			w.ResponseWriter.WriteHeaderNow()
+		}
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// TransactionHook begins transactions for opted-in routes in OnRequest and
+// finishes them in OnError and OnResponse
+type TransactionHook struct {
+	plugin *TransactionPlugin
+}
+
+// OnRequest implements core.LifecycleHook
+func (h *TransactionHook) OnRequest(c *gin.Context) {
+	if h.plugin.optedIn(c) {
+		h.plugin.begin(c)
+	}
+}
+
+// PreHandler implements core.LifecycleHook
+func (h *TransactionHook) PreHandler(c *gin.Context) {
+}
+
+// OnResponse implements core.LifecycleHook
+func (h *TransactionHook) OnResponse(c *gin.Context, response interface{}) {
+	status := c.Writer.Status()
+	if info, ok := response.(*core.ResponseInfo); ok {
+		status = info.StatusCode
+	}
+	h.plugin.finish(c, status >= http.StatusOK && status < http.StatusMultipleChoices)
+}
+
+// OnError implements core.LifecycleHook
+// Errors and panics roll back and release the held back response, so the error
+// response (or the panic recovery response) is written through
+func (h *TransactionHook) OnError(c *gin.Context, err error) {
+	h.plugin.finish(c, false)
+	if state, ok := requestState(c); ok {
+		state.writer.release()
+	}
+}
@@ -0,0 +1,171 @@
+package transaction
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transactional = map[string]interface{}{OptionKey: true}
+
+func newTransactionTestApp(t *testing.T) (*core.DoffApp, *TransactionPlugin, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	app := core.CreateDoffApp(&core.AppOptions{Name: "transaction-test", Mode: gin.TestMode}).(*core.DoffApp)
+	require.NoError(t, app.GetContainer().RegisterSingleton(DefaultDBName, func(c core.DIContainer) (interface{}, error) {
+		return db, nil
+	}))
+
+	plugin := NewTransactionPlugin()
+	require.NoError(t, app.RegisterPlugin(plugin))
+	return app, plugin, mock
+}
+
+func serve(app *core.DoffApp, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.GetEngine().ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestTransactionPlugin_CommitsSuccessfulRequests(t *testing.T) {
+	app, _, mock := newTransactionTestApp(t)
+	router := core.NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.POST(core.RouteConfig{Path: "/orders", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		_, err := tx.Exec("INSERT INTO orders DEFAULT VALUES")
+		require.NoError(t, err)
+		c.Status(http.StatusCreated)
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	recorder := serve(app, http.MethodPost, "/orders")
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionPlugin_CommitFailureAnswers500(t *testing.T) {
+	app, _, mock := newTransactionTestApp(t)
+	var hookErrors []error
+	app.GetPluginManager().GetLifecycleManager().AddHook(core.NewOnErrorHook(func(c *gin.Context, err error) {
+		hookErrors = append(hookErrors, err)
+	}))
+	router := core.NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.POST(core.RouteConfig{Path: "/orders", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		_, err := tx.Exec("INSERT INTO orders DEFAULT VALUES")
+		require.NoError(t, err)
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+
+	recorder := serve(app, http.MethodPost, "/orders")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"code":"internal_error"`)
+	assert.NotContains(t, recorder.Body.String(), `"id"`)
+	require.Len(t, hookErrors, 1)
+	assert.ErrorContains(t, hookErrors[0], "serialization failure")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionPlugin_RollsBackFailedRequests(t *testing.T) {
+	app, _, mock := newTransactionTestApp(t)
+	router := core.NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.POST(core.RouteConfig{Path: "/status", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		_, err := tx.Exec("INSERT INTO orders DEFAULT VALUES")
+		require.NoError(t, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "payment declined"})
+	})
+	router.POST(core.RouteConfig{Path: "/error", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		core.AbortWithError(c, core.ErrConflict)
+	})
+	router.POST(core.RouteConfig{Path: "/panic", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/status", http.StatusInternalServerError},
+		{"/error", http.StatusConflict},
+		{"/panic", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mock.ExpectBegin()
+			if tt.path == "/status" {
+				mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+			mock.ExpectRollback()
+
+			recorder := serve(app, http.MethodPost, tt.path)
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionPlugin_SkipsRoutesNotOptedIn(t *testing.T) {
+	app, _, mock := newTransactionTestApp(t)
+	router := core.NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.GET(core.RouteConfig{Path: "/orders"}, func(c *gin.Context, tx *sql.Tx) {
+		t.Fatal("handler must not run without a transaction to inject")
+	})
+
+	recorder := serve(app, http.MethodGet, "/orders")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionPlugin_GroupMiddleware(t *testing.T) {
+	app, plugin, mock := newTransactionTestApp(t)
+	group := core.NewRouter(app.GetEngine(), app.GetContainer()).Group("/orders", plugin.Middleware())
+	group.DELETE(core.RouteConfig{Path: "/:id"}, func(c *gin.Context, container core.DIContainer) {
+		tx, ok := FromContext(c)
+		require.True(t, ok)
+		_, err := tx.Exec("DELETE FROM orders WHERE id = ?", c.Param("id"))
+		require.NoError(t, err)
+		c.Status(http.StatusNoContent)
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM orders").WithArgs("42").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	recorder := serve(app, http.MethodDelete, "/orders/42")
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionPlugin_BeginFailure(t *testing.T) {
+	app, _, mock := newTransactionTestApp(t)
+	router := core.NewEnhancedRouter(app.GetEngine(), app.GetContainer())
+	router.POST(core.RouteConfig{Path: "/orders", Options: transactional}, func(c *gin.Context, tx *sql.Tx) {
+		t.Fatal("handler must not run when the transaction cannot begin")
+	})
+
+	mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+	recorder := serve(app, http.MethodPost, "/orders")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"code":"service_unavailable"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
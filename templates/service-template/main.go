@@ -1,14 +1,11 @@
 package main
 
 import (
-	"app/libs/core"
-	"app/libs/plugins/logger"
-	"context"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/core"
+	"github.com/dangvanduc1999/doffy-go-boostrap/libs/plugins/logger"
 	"github.com/gin-gonic/gin"
 )
 
@@ -78,7 +75,7 @@ func main() {
 	router := app.(*core.DoffApp).GetRouter()
 	
 	// Health check endpoint
-	router.GET("/health", func(c *gin.Context, container core.DIContainer) {
+	router.GET(core.RouteConfig{Path: "/health"}, func(c *gin.Context, container core.DIContainer) {
 		c.JSON(200, gin.H{
 			"status": "ok",
 			"service": "MyService",
@@ -86,24 +83,11 @@ func main() {
 		})
 	})
 	
-	// Start server in a goroutine
-	go func() {
-		app.Listen()
-	}()
-	
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	println("Shutting down server...")
-	
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := app.Shutdown(ctx); err != nil {
-		println("Server forced to shutdown:", err.Error())
+	// Serve until SIGINT/SIGTERM, then shut down gracefully
+	if err := app.Run(); err != nil {
+		println("Server stopped with error:", err.Error())
+		os.Exit(1)
 	}
 	
 	println("Server exiting")
-}
\ No newline at end of file
+}